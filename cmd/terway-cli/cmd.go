@@ -168,6 +168,10 @@ func runExecute(cmd *cobra.Command, args []string) error {
 
 	typ, name, command := args[0], args[1], args[2]
 	args = args[3:]
+	if executeJSON {
+		// the daemon take the last arg as the output format
+		args = append(args, "--json")
+	}
 
 	request := &rpc.ResourceExecuteRequest{
 		Type:    typ,
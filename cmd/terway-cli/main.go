@@ -23,6 +23,9 @@ var (
 	ctx           context.Context
 	contextCancel context.CancelFunc
 	client        rpc.TerwayTracingClient
+
+	// executeJSON request the command output the result as json
+	executeJSON bool
 )
 
 var (
@@ -92,6 +95,7 @@ var (
 )
 
 func init() {
+	executeCmd.Flags().BoolVar(&executeJSON, "json", false, "output the result as json, for the commands support it")
 	rootCmd.AddCommand(listCmd, showCmd, mappingCmd, executeCmd, metadataCmd)
 }
 
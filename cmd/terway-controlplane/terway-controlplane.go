@@ -56,6 +56,7 @@ func init() {
 	utilruntime.Must(networkv1beta1.AddToScheme(scheme))
 
 	metrics.Registry.MustRegister(metric.OpenAPILatency)
+	metrics.Registry.MustRegister(metric.OpenAPICallCount)
 }
 
 func main() {
@@ -0,0 +1,95 @@
+package daemon
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+)
+
+// defaultAllocFailureLogSize count of the recent failed allocations kept by default
+const defaultAllocFailureLogSize = 100
+
+// allocFailure the record of a failed allocation
+type allocFailure struct {
+	Time         time.Time
+	Pod          string
+	Reason       string
+	ResourceType string
+	Err          string
+}
+
+func (f allocFailure) String() string {
+	return fmt.Sprintf("%s pod: %s, reason: %s, resource type: %s, error: %s",
+		f.Time.Format(time.RFC3339), f.Pod, f.Reason, f.ResourceType, f.Err)
+}
+
+// allocFailureLog ring buffer keep the last failed allocations for post-mortem
+type allocFailureLog struct {
+	lock    sync.Mutex
+	entries []allocFailure
+	// next the index the next failure is written to
+	next int
+	full bool
+}
+
+func newAllocFailureLog(size int) *allocFailureLog {
+	return &allocFailureLog{
+		entries: make([]allocFailure, size),
+	}
+}
+
+// Add record the failure, the oldest one is dropped if the log is full
+func (l *allocFailureLog) Add(pod *types.PodInfo, reason string, err error) {
+	f := allocFailure{
+		Time:   time.Now(),
+		Reason: reason,
+		Err:    err.Error(),
+	}
+	if pod != nil {
+		f.Pod = podInfoKey(pod.Namespace, pod.Name)
+		f.ResourceType = podNetworkResourceType(pod.PodNetworkType)
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.entries[l.next] = f
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// List return the failures recorded, the oldest first
+func (l *allocFailureLog) List() []allocFailure {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if !l.full {
+		return append([]allocFailure(nil), l.entries[:l.next]...)
+	}
+	return append(append([]allocFailure(nil), l.entries[l.next:]...), l.entries[:l.next]...)
+}
+
+// podNetworkResourceType return the resource type allocated for the pod network type
+func podNetworkResourceType(podNetworkType string) string {
+	switch podNetworkType {
+	case podNetworkTypeENIMultiIP:
+		return types.ResourceTypeENIIP
+	case podNetworkTypeVPCENI:
+		return types.ResourceTypeENI
+	case podNetworkTypeVPCIP:
+		return types.ResourceTypeVeth
+	default:
+		return podNetworkType
+	}
+}
+
+// recordRequestFailure record the failure of the alloc request rejected before the pod info is got
+func (n *networkService) recordRequestFailure(r *rpc.AllocIPRequest, reason string, err error) {
+	if n.allocFailures == nil {
+		return
+	}
+	n.allocFailures.Add(&types.PodInfo{Namespace: r.K8SPodNamespace, Name: r.K8SPodName}, reason, err)
+}
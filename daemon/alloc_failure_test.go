@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_allocFailureLog(t *testing.T) {
+	l := newAllocFailureLog(2)
+	assert.Empty(t, l.List())
+
+	l.Add(&types.PodInfo{Namespace: "default", Name: "a", PodNetworkType: podNetworkTypeENIMultiIP}, "timeout", errors.New("a"))
+	failures := l.List()
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "default/a", failures[0].Pod)
+	assert.Equal(t, types.ResourceTypeENIIP, failures[0].ResourceType)
+	assert.Equal(t, "timeout", failures[0].Reason)
+
+	l.Add(&types.PodInfo{Namespace: "default", Name: "b"}, "other", errors.New("b"))
+	l.Add(&types.PodInfo{Namespace: "default", Name: "c"}, "other", errors.New("c"))
+	failures = l.List()
+	assert.Len(t, failures, 2)
+	assert.Equal(t, "default/b", failures[0].Pod)
+	assert.Equal(t, "default/c", failures[1].Pod)
+}
@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/tracing"
+	"github.com/AliyunContainerService/terway/rpc"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type allocPhasesKey struct{}
+
+// allocPhases collect the time spent on each phase of an allocation,
+// the durations of phase entered more than once are summed up
+type allocPhases struct {
+	lock      sync.Mutex
+	names     []string
+	durations map[string]time.Duration
+}
+
+func newAllocPhases() *allocPhases {
+	return &allocPhases{durations: make(map[string]time.Duration)}
+}
+
+// withAllocPhases attach the phases to ctx, the spans started from ctx are recorded into it
+func withAllocPhases(ctx context.Context, phases *allocPhases) context.Context {
+	return context.WithValue(ctx, allocPhasesKey{}, phases)
+}
+
+func allocPhasesFrom(ctx context.Context) *allocPhases {
+	phases, _ := ctx.Value(allocPhasesKey{}).(*allocPhases)
+	return phases
+}
+
+func (p *allocPhases) observe(name string, start time.Time) {
+	elapsed := time.Since(start)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.durations[name]; !ok {
+		p.names = append(p.names, name)
+	}
+	p.durations[name] += elapsed
+}
+
+// String format the phases in the order they first entered, eg. "GetPod: 10ms, Allocate: 8s"
+func (p *allocPhases) String() string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	parts := make([]string, 0, len(p.names))
+	for _, name := range p.names {
+		parts = append(parts, fmt.Sprintf("%s: %v", name, p.durations[name].Round(time.Millisecond)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reportSlowAlloc record a pod event with the phase durations if AllocIP took longer than the threshold
+func (n *networkService) reportSlowAlloc(r *rpc.AllocIPRequest, phases *allocPhases, elapsed time.Duration) {
+	if elapsed <= n.slowAllocThreshold {
+		return
+	}
+	msg := fmt.Sprintf("alloc ip took %v, exceed %v, phases: %s", elapsed.Round(time.Millisecond), n.slowAllocThreshold, phases)
+	serviceLog.Warnf("pod %s %s", podInfoKey(r.K8SPodNamespace, r.K8SPodName), msg)
+	_ = tracing.RecordPodEvent(r.K8SPodName, r.K8SPodNamespace, corev1.EventTypeWarning, "SlowAllocIP", msg)
+}
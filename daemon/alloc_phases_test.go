@@ -0,0 +1,32 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/terway/types/daemon"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_allocPhases(t *testing.T) {
+	phases := newAllocPhases()
+	ctx := withAllocPhases(context.Background(), phases)
+
+	_, endSpan := startSpan(ctx, "GetPod")
+	endSpan(nil)
+	for i := 0; i < 2; i++ {
+		_, endSpan = startSpan(ctx, "Allocate")
+		time.Sleep(10 * time.Millisecond)
+		endSpan(nil)
+	}
+	_, endSpan = startSpan(context.Background(), "PutResource")
+	endSpan(nil)
+
+	assert.Equal(t, []string{"GetPod", "Allocate"}, phases.names)
+	assert.True(t, phases.durations["Allocate"] >= 20*time.Millisecond)
+	assert.Contains(t, phases.String(), "GetPod: ")
+
+	assert.Error(t, validateConfig(&daemon.Config{SlowAllocThresholdSeconds: -1}))
+}
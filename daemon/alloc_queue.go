@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// allocQueueSeenTTL first seen time of pod not come back in it since last seen is forgot
+const allocQueueSeenTTL = 10 * time.Minute
+
+// ErrAllocQueueFull is returned when too many pods waiting for allocation
+var ErrAllocQueueFull = errors.New("too many pods waiting for ip allocation")
+
+// allocQueue serve AllocIP one by one in the order of the pod first seen time,
+// so the pod waiting longest is served first when the pool is exhausted.
+// the first seen time is kept across cni retries until the allocation succeed,
+// or the pod stop retrying for allocQueueSeenTTL
+type allocQueue struct {
+	lock    sync.Mutex
+	maxSize int
+	seen    map[string]*allocSeen
+	waiting []*allocWaiter
+}
+
+// allocSeen the time the pod first and last seen in queue
+type allocSeen struct {
+	first time.Time
+	last  time.Time
+}
+
+type allocWaiter struct {
+	key       string
+	firstSeen time.Time
+	served    bool
+	ready     chan struct{}
+}
+
+func newAllocQueue(maxSize int) *allocQueue {
+	return &allocQueue{
+		maxSize: maxSize,
+		seen:    make(map[string]*allocSeen),
+	}
+}
+
+// Enter wait until the pod is at the head of the queue or ctx done,
+// the returned func must be called with the allocation result to let the next pod in
+func (q *allocQueue) Enter(ctx context.Context, key string) (func(success bool), error) {
+	q.lock.Lock()
+	if len(q.waiting) >= q.maxSize {
+		q.lock.Unlock()
+		return nil, ErrAllocQueueFull
+	}
+	now := time.Now()
+	q.forgetExpiredLocked(now)
+	s, ok := q.seen[key]
+	if !ok {
+		s = &allocSeen{first: now}
+		q.seen[key] = s
+	}
+	s.last = now
+	seen := s.first
+	w := &allocWaiter{
+		key:       key,
+		firstSeen: seen,
+		ready:     make(chan struct{}),
+	}
+	i := sort.Search(len(q.waiting), func(i int) bool {
+		return q.waiting[i].firstSeen.After(seen)
+	})
+	// never jump over the one being served
+	if i == 0 && len(q.waiting) > 0 && q.waiting[0].served {
+		i = 1
+	}
+	q.waiting = append(q.waiting, nil)
+	copy(q.waiting[i+1:], q.waiting[i:])
+	q.waiting[i] = w
+	q.serveHeadLocked()
+	q.lock.Unlock()
+
+	select {
+	case <-w.ready:
+		return func(success bool) {
+			q.leave(w, success)
+		}, nil
+	case <-ctx.Done():
+		q.leave(w, false)
+		return nil, ctx.Err()
+	}
+}
+
+// Len return the count of pods in queue
+func (q *allocQueue) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return len(q.waiting)
+}
+
+func (q *allocQueue) leave(w *allocWaiter, success bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for i := range q.waiting {
+		if q.waiting[i] == w {
+			q.waiting = append(q.waiting[:i], q.waiting[i+1:]...)
+			break
+		}
+	}
+	if success {
+		delete(q.seen, w.key)
+	} else if s, ok := q.seen[w.key]; ok {
+		s.last = time.Now()
+	}
+	q.serveHeadLocked()
+}
+
+func (q *allocQueue) serveHeadLocked() {
+	if len(q.waiting) == 0 || q.waiting[0].served {
+		return
+	}
+	q.waiting[0].served = true
+	close(q.waiting[0].ready)
+}
+
+// forgetExpiredLocked forget the pods not seen for allocQueueSeenTTL, which have stopped retrying
+func (q *allocQueue) forgetExpiredLocked(now time.Time) {
+	for key, seen := range q.seen {
+		if now.Sub(seen.last) > allocQueueSeenTTL {
+			delete(q.seen, key)
+		}
+	}
+}
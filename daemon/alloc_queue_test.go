@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_allocQueue(t *testing.T) {
+	q := newAllocQueue(3)
+
+	leaveA, err := q.Enter(context.Background(), "default/a")
+	assert.NoError(t, err)
+
+	// b wait for a, and time out, its first seen time is kept
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = q.Enter(ctx, "default/b")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	served := make(chan string, 2)
+	enter := func(key string) {
+		leave, err := q.Enter(context.Background(), key)
+		assert.NoError(t, err)
+		served <- key
+		leave(true)
+	}
+	go enter("default/c")
+	assert.Eventually(t, func() bool { return q.Len() == 2 }, time.Second, time.Millisecond)
+
+	// b retry, and is put before c
+	go enter("default/b")
+	assert.Eventually(t, func() bool { return q.Len() == 3 }, time.Second, time.Millisecond)
+
+	_, err = q.Enter(context.Background(), "default/d")
+	assert.Equal(t, ErrAllocQueueFull, err)
+
+	leaveA(true)
+	assert.Equal(t, "default/b", <-served)
+	assert.Equal(t, "default/c", <-served)
+	assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, time.Millisecond)
+}
+
+func Test_allocQueue_forgetExpired(t *testing.T) {
+	q := newAllocQueue(3)
+	now := time.Now()
+	// starving longer than the ttl but still retrying
+	q.seen["default/a"] = &allocSeen{first: now.Add(-2 * allocQueueSeenTTL), last: now.Add(-time.Second)}
+	// stopped retrying
+	q.seen["default/b"] = &allocSeen{first: now.Add(-2 * allocQueueSeenTTL), last: now.Add(-allocQueueSeenTTL - time.Second)}
+
+	q.forgetExpiredLocked(now)
+	assert.Contains(t, q.seen, "default/a")
+	assert.NotContains(t, q.seen, "default/b")
+
+	// the first seen time is kept when the pod comes back
+	leave, err := q.Enter(context.Background(), "default/a")
+	assert.NoError(t, err)
+	assert.Equal(t, now.Add(-2*allocQueueSeenTTL), q.seen["default/a"].first)
+	assert.False(t, q.seen["default/a"].last.Before(now))
+	leave(true)
+	assert.NotContains(t, q.seen, "default/a")
+}
@@ -0,0 +1,176 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+)
+
+// allocWebhookTimeout max time waiting for the alloc webhook, it is also limited by the AllocIP deadline
+const allocWebhookTimeout = 2 * time.Second
+
+// ErrAllocRejected is returned when the allocation is rejected by the alloc webhook
+var ErrAllocRejected = errors.New("allocation rejected by webhook")
+
+// allocWebhookRequest is posted to the webhook before the allocation is stored
+type allocWebhookRequest struct {
+	Pod       *types.PodInfo       `json:"pod"`
+	Resources []types.ResourceItem `json:"resources"`
+}
+
+// allocWebhookResponse is the verdict of the webhook
+type allocWebhookResponse struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+	// ExtraRoutes dst of the routes added to the pod default interface
+	ExtraRoutes []string `json:"extra_routes,omitempty"`
+	// ENITags tags added to the enis the resources allocated on
+	ENITags map[string]string `json:"eni_tags,omitempty"`
+}
+
+// allocWebhook let a local webhook veto the allocation or supply extra routes and eni tags
+type allocWebhook struct {
+	url    string
+	client *http.Client
+
+	lock sync.Mutex
+	// tagged the eni tags from webhook applied, keyed by eni id, the eni already carry the tags is not tagged again
+	tagged map[string]map[string]string
+}
+
+func newAllocWebhook(url string) *allocWebhook {
+	return &allocWebhook{
+		url:    url,
+		client: &http.Client{Timeout: allocWebhookTimeout},
+		tagged: make(map[string]map[string]string),
+	}
+}
+
+// hasTags return true if the tags are all applied to the eni
+func (w *allocWebhook) hasTags(eniID string, tags map[string]string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for k, v := range tags {
+		applied, ok := w.tagged[eniID][k]
+		if !ok || applied != v {
+			return false
+		}
+	}
+	return true
+}
+
+// setTags record the tags applied to the eni
+func (w *allocWebhook) setTags(eniID string, tags map[string]string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.tagged[eniID] == nil {
+		w.tagged[eniID] = make(map[string]string)
+	}
+	for k, v := range tags {
+		w.tagged[eniID][k] = v
+	}
+}
+
+// Review post the allocation to webhook, error is returned if the allocation is rejected or webhook not work
+func (w *allocWebhook) Review(ctx context.Context, res types.PodResources) (*allocWebhookResponse, error) {
+	body, err := json.Marshal(&allocWebhookRequest{
+		Pod:       res.PodInfo,
+		Resources: res.Resources,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, allocWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error call alloc webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error read alloc webhook response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alloc webhook return status %d: %s", resp.StatusCode, string(data))
+	}
+	review := &allocWebhookResponse{}
+	err = json.Unmarshal(data, review)
+	if err != nil {
+		return nil, fmt.Errorf("error parse alloc webhook response: %w", err)
+	}
+	if !review.Allowed {
+		return nil, fmt.Errorf("%w: %s", ErrAllocRejected, review.Reason)
+	}
+	return review, nil
+}
+
+// reviewAllocation ask the alloc webhook for the allocation, return the extra routes supplied,
+// the eni tags supplied are added to the enis of the allocation
+func (n *networkService) reviewAllocation(ctx context.Context, res types.PodResources) ([]*rpc.Route, error) {
+	if n.allocWebhook == nil {
+		return nil, nil
+	}
+	_, endSpan := startSpan(ctx, "AllocWebhook")
+	review, err := n.allocWebhook.Review(ctx, res)
+	endSpan(err)
+	if err != nil {
+		return nil, err
+	}
+	var routes []*rpc.Route
+	for _, dst := range review.ExtraRoutes {
+		if _, _, err = net.ParseCIDR(dst); err != nil {
+			return nil, fmt.Errorf("invalid extra route %s from alloc webhook: %w", dst, err)
+		}
+		routes = append(routes, &rpc.Route{Dst: dst})
+	}
+	if len(review.ENITags) > 0 {
+		if err = normalizeTags("eni_tags from alloc webhook", review.ENITags); err != nil {
+			return nil, err
+		}
+		n.tagAllocatedENIs(ctx, res, review.ENITags)
+	}
+	return routes, nil
+}
+
+// tagAllocatedENIs add the tags to the enis the resources allocated on, the failure is only logged.
+// the eni already carry the tags is skipped, so the openapi is only called when the tags of eni change
+// as the tags are annotations of the allocation
+func (n *networkService) tagAllocatedENIs(ctx context.Context, res types.PodResources, tags map[string]string) {
+	tagged := make(map[string]bool)
+	for _, item := range res.Resources {
+		if item.ENIID == "" || tagged[item.ENIID] {
+			continue
+		}
+		if item.Type != types.ResourceTypeENI && item.Type != types.ResourceTypeENIIP {
+			continue
+		}
+		tagged[item.ENIID] = true
+		if n.allocWebhook.hasTags(item.ENIID, tags) {
+			continue
+		}
+		err := n.ecs.TagNetworkInterface(ctx, item.ENIID, tags)
+		if err != nil {
+			serviceLog.Warnf("error tag eni %s with the tags from alloc webhook: %v", item.ENIID, err)
+			continue
+		}
+		n.allocWebhook.setTags(item.ENIID, tags)
+	}
+}
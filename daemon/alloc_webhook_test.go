@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/ipam"
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTagAPI struct {
+	ipam.API
+	tags  map[string]map[string]string
+	calls int
+}
+
+func (f *fakeTagAPI) TagNetworkInterface(ctx context.Context, eniID string, tags map[string]string) error {
+	f.calls++
+	f.tags[eniID] = tags
+	return nil
+}
+
+func Test_reviewAllocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &allocWebhookRequest{}
+		_ = json.NewDecoder(r.Body).Decode(req)
+		if req.Pod.Name == "denied" {
+			_, _ = w.Write([]byte(`{"allowed": false, "reason": "not allowed"}`))
+			return
+		}
+		if req.Pod.Name == "bad-tag" {
+			_, _ = w.Write([]byte(`{"allowed": true, "eni_tags": {"aliyun:key": "v"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"allowed": true, "extra_routes": ["10.0.0.0/8"], "eni_tags": {"team": "a"}}`))
+	}))
+	defer server.Close()
+
+	api := &fakeTagAPI{tags: map[string]map[string]string{}}
+	n := &networkService{allocWebhook: newAllocWebhook(server.URL), ecs: api}
+	routes, err := n.reviewAllocation(context.Background(), types.PodResources{
+		PodInfo: &types.PodInfo{Name: "foo"},
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1", ENIID: "eni-1"},
+			{Type: types.ResourceTypeEIP, ID: "eip-1"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*rpc.Route{{Dst: "10.0.0.0/8"}}, routes)
+	assert.Equal(t, map[string]map[string]string{"eni-1": {"team": "a"}}, api.tags)
+	assert.Equal(t, 1, api.calls)
+
+	// the eni already carry the tags is not tagged again
+	_, err = n.reviewAllocation(context.Background(), types.PodResources{
+		PodInfo:   &types.PodInfo{Name: "bar"},
+		Resources: []types.ResourceItem{{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.2", ENIID: "eni-1"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, api.calls)
+
+	_, err = n.reviewAllocation(context.Background(), types.PodResources{PodInfo: &types.PodInfo{Name: "bad-tag"}})
+	assert.Error(t, err)
+
+	_, err = n.reviewAllocation(context.Background(), types.PodResources{PodInfo: &types.PodInfo{Name: "denied"}})
+	assert.ErrorIs(t, err, ErrAllocRejected)
+	assert.Equal(t, "webhook_rejected", rollbackReason(err))
+
+	n = &networkService{}
+	routes, err = n.reviewAllocation(context.Background(), types.PodResources{PodInfo: &types.PodInfo{Name: "foo"}})
+	assert.NoError(t, err)
+	assert.Nil(t, routes)
+}
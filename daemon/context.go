@@ -1,6 +1,8 @@
 package daemon
 
 import (
+	"net"
+
 	"github.com/AliyunContainerService/terway/pkg/logger"
 	"github.com/AliyunContainerService/terway/types"
 
@@ -13,6 +15,13 @@ type networkContext struct {
 	resources  []types.ResourceItem
 	pod        *types.PodInfo
 	k8sService Kubernetes
+	// previousIP ipv4 allocated to the pod before, preferred if the pod PreferPreviousIP
+	previousIP string
+	// subCIDR the eniip of pod should be in, nil for any. the ip out of it is left in pool and retried for subCIDRRetries
+	subCIDR        *net.IPNet
+	subCIDRRetries int
+	// eniAffinity the mac of eni the eniip is preferred to be allocated from, empty for any
+	eniAffinity string
 }
 
 func (networkContext *networkContext) Log() *logrus.Entry {
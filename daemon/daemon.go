@@ -5,19 +5,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/AliyunContainerService/terway/pkg/aliyun"
 	"github.com/AliyunContainerService/terway/pkg/aliyun/client"
+	apiErr "github.com/AliyunContainerService/terway/pkg/aliyun/client/errors"
 	podENITypes "github.com/AliyunContainerService/terway/pkg/apis/network.alibabacloud.com/v1beta1"
 	"github.com/AliyunContainerService/terway/pkg/backoff"
 	terwayIP "github.com/AliyunContainerService/terway/pkg/ip"
+	"github.com/AliyunContainerService/terway/pkg/ipam"
 	"github.com/AliyunContainerService/terway/pkg/link"
 	"github.com/AliyunContainerService/terway/pkg/logger"
 	"github.com/AliyunContainerService/terway/pkg/metric"
@@ -32,8 +38,13 @@ import (
 	"github.com/containernetworking/cni/libcni"
 	containertypes "github.com/containernetworking/cni/pkg/types"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	k8sErr "k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
@@ -45,6 +56,18 @@ const (
 	gcPeriod        = 5 * time.Minute
 	poolCheckPeriod = 10 * time.Minute
 
+	defaultPendingPodTTL  = 10 * time.Minute
+	pendingPodSweepPeriod = time.Minute
+
+	defaultSubCIDRAllocRetries = 3
+
+	defaultTrunkVlanMin = 1
+	defaultTrunkVlanMax = 4094
+
+	defaultMaxEniCapRatio = 1
+
+	defaultHostNetNSPrefix = "/proc/1/root/"
+
 	conditionFalse = "false"
 	conditionTrue  = "true"
 
@@ -55,8 +78,32 @@ const (
 	tracingKeyKubeConfig       = "kubeconfig"
 	tracingKeyMaster           = "master"
 	tracingKeyPendingPodsCount = "pending_pods_count"
+	tracingKeyMaxPendingPods   = "max_pending_pods"
+	tracingKeyStartTime        = "start_time"
+	tracingKeyUptime           = "uptime"
+	tracingKeyRestartCount     = "restart_count"
+	tracingKeyLogLevel         = "log_level"
+	// tracingKeyGCQuarantined the resource quarantined for failing gc repeatedly
+	tracingKeyGCQuarantined = "gc_quarantine/%s"
+
+	tracingKeyLimitMaxENI           = "limit_max_eni"
+	tracingKeyLimitIPv4PerENI       = "limit_ipv4_per_eni"
+	tracingKeyLimitIPv6PerENI       = "limit_ipv6_per_eni"
+	tracingKeyLimitSupportIPv6      = "limit_support_ipv6"
+	tracingKeyLimitSupportENIIPIPv6 = "limit_support_eniip_ipv6"
 
 	commandMapping = "mapping"
+	commandVerify  = "verify"
+	// commandFailures list the recent failed allocations
+	commandFailures = "failures"
+	// commandPending list the pods in processing and the time they have been pending
+	commandPending = "pending"
+	// commandHistory list the allocation and release history of the pod given by args, or the pods with history
+	commandHistory = "history"
+	// commandRetryGC collect the quarantined resources given by args
+	commandRetryGC = "retry"
+	// commandArgJSON the last arg of command to output the result as json
+	commandArgJSON = "--json"
 
 	cniDefaultPath = "/opt/cni/bin"
 	// this file is generated from configmap
@@ -78,7 +125,7 @@ type networkService struct {
 	eniIPResMgr    ResourceManager
 	eipResMgr      ResourceManager
 	//networkResourceMgr ResourceManager
-	mgrForResource map[string]ResourceManager
+	mgrForResource map[resourceManagerKey]ResourceManager
 	pendingPods    sync.Map
 	sync.RWMutex
 
@@ -90,15 +137,208 @@ type networkService struct {
 	ipamType     types.IPAMType
 	eniCapPolicy types.ENICapPolicy
 
+	// maxAllocLatency limit the time spent on AllocIP, 0 for no limit
+	maxAllocLatency time.Duration
+	// maxAllocLatencyByType override maxAllocLatency for pod network type
+	maxAllocLatencyByType map[string]time.Duration
+
+	// sufficientIPThreshold free ip count below which the node is reported as ip insufficient
+	sufficientIPThreshold int
+
+	// eniQuota the eni count can be attached to instance, eniQuotaAlertRatio alert when the attached exceeds the fraction of it
+	eniQuota           int
+	eniQuotaAlertRatio float64
+	eniQuotaCheckedAt  time.Time
+
+	// validateGateways check the derived gateway before return to cni
+	validateGateways bool
+
+	// dns default pod dns config, nil for not config
+	dns *types.DNSConfig
+
+	// pendingPodTTL entries in pendingPods older than it are considered leaked
+	pendingPodTTL time.Duration
+	// maxPendingPods AllocIP is rejected when the pods in processing exceed it, 0 for unlimited
+	maxPendingPods int
+
+	// trunkVlanMin trunkVlanMax the vlan id range allowed for trunk eni
+	trunkVlanMin uint32
+	trunkVlanMax uint32
+
+	// releaseAllOnShutdown release idle resources to ecs when daemon stop on a terminating node
+	releaseAllOnShutdown bool
+
+	// allocQueue serve AllocIP in the order of pod first seen, nil for disable
+	allocQueue *allocQueue
+
+	// vSwitchCIDRs cache of vswitch cidr, for replacing the host scoped cidr of eni
+	vSwitchCIDRs *vSwitchCIDRCache
+
+	// vSwitches the vswitches pod can request ip from by annotation
+	vSwitches sets.String
+
+	// hostNetNSPrefix prefix of the pod netns path to access it from the daemon
+	hostNetNSPrefix string
+
+	// ecs api for verifying the resources in db
+	ecs ipam.API
+
+	// defaultNetworkPriority network priority for pod not specify one or specify an invalid one
+	defaultNetworkPriority string
+
+	// extraRouteTable extraRoutePriority defaults of policy route for the extra routes in crd, 0 table for main table
+	extraRouteTable    int32
+	extraRoutePriority int32
+
+	// allocWebhook review the allocation before stored, nil for disable
+	allocWebhook *allocWebhook
+
+	// allowPartialDualStack degrade pod to single stack when one of the ip family not allocated in dual stack
+	allowPartialDualStack bool
+
+	// eipStickTime keep the eip of deleted pod for the duration before release
+	eipStickTime time.Duration
+	// maxIPStickDuration the resources of pod with ip stick time are not kept after allocated for the duration, 0 for unlimited
+	maxIPStickDuration time.Duration
+
+	// maxSecondaryIPCount max extra eniips a pod can request
+	maxSecondaryIPCount int
+
+	// allocFailures the recent failed allocations
+	allocFailures *allocFailureLog
+
+	// podHistory the allocation and release history of pods, nil for disable
+	podHistory *podHistory
+
+	// vpcExtraRoutes the routes of config added to the pods of vpc ip
+	vpcExtraRoutes []*rpc.Route
+	// validateExtraRoutes reject the extra routes of crd overlap the service cidr
+	validateExtraRoutes bool
+
+	// patchPodIPRetries retries of patching pod ip annotation, 0 for default
+	patchPodIPRetries int
+	// getPodRetries retries of getting pod on transient errors in AllocIP, 0 for default
+	getPodRetries int
+
+	// reserveIPsForCritical free ips only critical pods can use
+	reserveIPsForCritical int
+
+	// preferPreviousIP reuse the exact ip allocated before for all pods
+	preferPreviousIP bool
+
+	// disableRPFilter disable reverse path filter on interfaces of all pods
+	disableRPFilter bool
+
+	// readOnly the daemon is a standby serving the snapshot of resource db written by the active daemon, resources are not changed by it
+	readOnly bool
+	// standbySnapshotPath the file the resource db is snapshot to for the standby daemon, empty for disable
+	standbySnapshotPath string
+
+	// cniCheckFailureRatio failed ratio of cni check in a cycle above which the pod network on node is degraded, 0 for disable
+	cniCheckFailureRatio float64
+	// cniCheckDegraded the pod network was reported as degraded in last cycle
+	cniCheckDegraded bool
+
+	// containerIDMismatchThreshold consecutive container id mismatch of pod reaching it is reported by event, 0 for disable
+	containerIDMismatchThreshold int
+	containerIDMismatches        containerIDMismatches
+	// gcQuarantine the resources failed gc repeatedly, nil for disable
+	gcQuarantine *gcQuarantine
+	// deleteOrphanRoutes delete the ip rules and routes of the eni deleted, nil for disable
+	deleteOrphanRoutes func(rulePriority, tableMin, tableMax int, liveMACs []string) ([]string, error)
+	// cleanupDatapath cleanup the datapath on node of the resource no manager of the daemon mode handles, nil for disable
+	cleanupDatapath func(res types.ResourceItem) error
+
+	// slowAllocThreshold AllocIP took longer than it is reported by a pod event with the phase durations, 0 for disable
+	slowAllocThreshold time.Duration
+
+	// limit the instance limit got at startup
+	limit *aliyun.Limits
+
+	// podSubCIDRs the sub cidrs of vswitch the eniip of selected pods allocated in
+	podSubCIDRs         []podSubCIDR
+	subCIDRAllocRetries int
+	// ipAffinitySingleENI allocate the eniips of a pod from one eni if possible
+	ipAffinitySingleENI bool
+
+	// startTime restartCount the time daemon started and the times it started before on node
+	startTime    time.Time
+	restartCount int
+
+	// config the effective config, updated by ReloadConfig
+	config     *daemon.Config
+	reloadLock sync.Mutex
+
 	rpc.UnimplementedTerwayBackendServer
 }
 
 var serviceLog = logger.DefaultLogger.WithField("subSys", "network-service")
 
+// ErrIPReserved is returned when the free ips are reserved for critical pods
+var ErrIPReserved = errors.New("free ips are reserved for critical pods")
+
+// ErrThrottled is returned when AllocIP exceed the max alloc latency
+var ErrThrottled = errors.New("alloc ip throttled, exceed max alloc latency")
+
+// ErrTooBusy is returned when AllocIP exceed the max pending pods, the caller should retry later
+var ErrTooBusy = status.Error(codes.ResourceExhausted, "too many pods in processing, retry later")
+
+// ErrPartialDualStack is returned when only one ip family is allocated in dual stack
+var ErrPartialDualStack = errors.New("partial dual stack ip allocated")
+
 var _ rpc.TerwayBackendServer = (*networkService)(nil)
 
-func (n *networkService) getResourceManagerForRes(resType string) ResourceManager {
-	return n.mgrForResource[resType]
+// gcResourceTypeOrder is the order of resource types to do garbage collection,
+// resource depend on others (eip is bind to eni or eniip) should be released first
+var gcResourceTypeOrder = []string{types.ResourceTypeEIP, types.ResourceTypeVeth, types.ResourceTypeENIIP, types.ResourceTypeENI}
+
+// gcPriority return the index of resType in gcResourceTypeOrder, unknown types are put at last
+func gcPriority(resType string) int {
+	for i, t := range gcResourceTypeOrder {
+		if t == resType {
+			return i
+		}
+	}
+	return len(gcResourceTypeOrder)
+}
+
+// sortResourceTypesForGC sort the resource types by gcResourceTypeOrder, unknown types are put at last
+func sortResourceTypesForGC(resTypes []string) {
+	sort.SliceStable(resTypes, func(i, j int) bool {
+		return gcPriority(resTypes[i]) < gcPriority(resTypes[j])
+	})
+}
+
+// resourceManagerKey identify a resource manager, there may be multi managers for one resource type
+type resourceManagerKey struct {
+	resType string
+	poolID  string
+}
+
+func (k resourceManagerKey) String() string {
+	return k.resType + "/" + k.poolID
+}
+
+// sortResourceManagerKeysForGC sort the keys by gcResourceTypeOrder of the resource type
+func sortResourceManagerKeysForGC(keys []resourceManagerKey) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		if gcPriority(keys[i].resType) != gcPriority(keys[j].resType) {
+			return gcPriority(keys[i].resType) < gcPriority(keys[j].resType)
+		}
+		return keys[i].poolID < keys[j].poolID
+	})
+}
+
+func (n *networkService) getResourceManagerForRes(res types.ResourceItem) ResourceManager {
+	return n.mgrForResource[resourceManagerKey{resType: res.Type, poolID: res.GetPoolID()}]
+}
+
+// setResourceManagers register the managers for resource types with the default pool id
+func (n *networkService) setResourceManagers(mgrs map[string]ResourceManager) {
+	n.mgrForResource = make(map[resourceManagerKey]ResourceManager, len(mgrs))
+	for resType, mgr := range mgrs {
+		n.mgrForResource[resourceManagerKey{resType: resType, poolID: types.DefaultPoolID}] = mgr
+	}
 }
 
 // return resource relation in db, or return nil.
@@ -114,6 +354,144 @@ func (n *networkService) getPodResource(info *types.PodInfo) (types.PodResources
 	return types.PodResources{}, err
 }
 
+// allocLatency return the max alloc latency of the pod network type, 0 for no limit
+func (n *networkService) allocLatency(podNetworkType string) time.Duration {
+	if latency := n.maxAllocLatencyByType[podNetworkType]; latency > 0 {
+		return latency
+	}
+	return n.maxAllocLatency
+}
+
+type allocDeadlineKey struct{}
+
+// allocContext derive a context for allocation, which deadline is shorter than the grpc context
+// if max alloc latency of the pod network type is set.
+func (n *networkService) allocContext(ctx context.Context, podNetworkType string) (context.Context, context.CancelFunc) {
+	latency := n.allocLatency(podNetworkType)
+	if latency <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= latency {
+		return ctx, func() {}
+	}
+	deadline := time.Now().Add(latency)
+	return context.WithDeadline(context.WithValue(ctx, allocDeadlineKey{}, deadline), deadline)
+}
+
+// throttledErr return ErrThrottled if the deadline derived by allocContext is exceeded,
+// the error is kept as it is if the grpc context is done before it
+func (n *networkService) throttledErr(ctx context.Context, err error) error {
+	deadline, ok := ctx.Value(allocDeadlineKey{}).(time.Time)
+	if !ok || !errors.Is(ctx.Err(), context.DeadlineExceeded) || time.Now().Before(deadline) {
+		return err
+	}
+	return errors.Wrapf(ErrThrottled, "%v", err)
+}
+
+// startSpan start a tracing span for a phase of rpc, call the returned func with the phase result to end it
+func startSpan(ctx context.Context, name string, attrs ...tracing.Attribute) (context.Context, func(err error)) {
+	ctx, span := tracing.StartSpan(ctx, name, attrs...)
+	phases, start := allocPhasesFrom(ctx), time.Now()
+	return ctx, func(err error) {
+		if phases != nil {
+			phases.observe(name, start)
+		}
+		outcome := "success"
+		if err != nil {
+			outcome = "failure"
+			span.RecordError(err)
+		}
+		span.SetAttributes(tracing.Attr("outcome", outcome))
+		span.End()
+	}
+}
+
+// marshalPodResources serialize the pod resources stored in db with the current schema version
+func marshalPodResources(v interface{}) ([]byte, error) {
+	res, ok := v.(types.PodResources)
+	if !ok {
+		return json.Marshal(v)
+	}
+	res.SchemaVersion = types.PodResourcesSchemaVersion
+	return json.Marshal(res)
+}
+
+// unmarshalPodResources deserialize the pod resources stored in db,
+// the record from newer version is quarantined instead of misinterpreted,
+// the part understood is returned along with for the resources of it are kept in use
+func unmarshalPodResources(bytes []byte) (interface{}, error) {
+	resourceRel := &types.PodResources{}
+	err := json.Unmarshal(bytes, resourceRel)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error unmarshal pod relate resource")
+	}
+	switch {
+	case resourceRel.SchemaVersion <= types.PodResourcesSchemaVersion:
+		// version 0 is stored before the schema version introduced, compatible with version 1
+		return *resourceRel, nil
+	default:
+		return *resourceRel, errors.Wrapf(storage.ErrQuarantine, "pod relate resource schema version %d, supported %d",
+			resourceRel.SchemaVersion, types.PodResourcesSchemaVersion)
+	}
+}
+
+// listQuarantinedPodResources list the pod resources quarantined from db, the resources of them are restored as in use,
+// otherwise the ip still used by the pod may be handed out to another one. They are not released by this version.
+func listQuarantinedPodResources(db storage.Storage) ([]interface{}, error) {
+	lister, ok := db.(storage.QuarantineLister)
+	if !ok {
+		return nil, nil
+	}
+	list, err := lister.ListQuarantined()
+	if err != nil {
+		return nil, err
+	}
+	var ret []interface{}
+	for _, obj := range list {
+		podRes := obj.(types.PodResources)
+		if podRes.PodInfo == nil {
+			continue
+		}
+		serviceLog.Warnf("keep the resources of quarantined pod %s/%s in use: %+v",
+			podRes.PodInfo.Namespace, podRes.PodInfo.Name, podRes.Resources)
+		ret = append(ret, podRes)
+	}
+	return ret, nil
+}
+
+func (n *networkService) putPodResource(ctx context.Context, res types.PodResources) error {
+	_, endSpan := startSpan(ctx, "PutResource")
+	key := podInfoKey(res.PodInfo.Namespace, res.PodInfo.Name)
+	if res.AllocatedAt.IsZero() {
+		res.AllocatedAt = n.allocatedAt(key, res.Resources)
+	}
+	err := n.resourceDB.Put(key, res)
+	endSpan(err)
+	return err
+}
+
+// allocatedAt return the allocation time of the previous record of pod if any resource reused from it,
+// so the stick time is bounded across the pod recreated, otherwise now
+func (n *networkService) allocatedAt(key string, resources []types.ResourceItem) time.Time {
+	now := time.Now()
+	obj, err := n.resourceDB.Get(key)
+	if err != nil {
+		return now
+	}
+	old := obj.(types.PodResources)
+	if old.AllocatedAt.IsZero() {
+		return now
+	}
+	for _, res := range resources {
+		for _, oldRes := range old.Resources {
+			if res.Type == oldRes.Type && res.ID == oldRes.ID {
+				return old.AllocatedAt
+			}
+		}
+	}
+	return now
+}
+
 func (n *networkService) deletePodResource(info *types.PodInfo) error {
 	key := podInfoKey(info.Namespace, info.Name)
 	return n.resourceDB.Delete(key)
@@ -132,9 +510,11 @@ func (n *networkService) allocateVeth(ctx *networkContext, old *types.PodResourc
 		}
 	}
 
+	_, endSpan := startSpan(ctx, "Allocate", tracing.Attr("resource_type", types.ResourceTypeVeth))
 	res, err := n.vethResMgr.Allocate(ctx, oldVethID)
+	endSpan(err)
 	if err != nil {
-		return nil, err
+		return nil, n.throttledErr(ctx, err)
 	}
 	return res.(*types.Veth), nil
 }
@@ -152,10 +532,22 @@ func (n *networkService) allocateENI(ctx *networkContext, old *types.PodResource
 		}
 	}
 
+	_, endSpan := startSpan(ctx, "Allocate", tracing.Attr("resource_type", types.ResourceTypeENI))
 	res, err := n.eniResMgr.Allocate(ctx, oldENIID)
-	if err != nil {
+	endSpan(err)
+	if errors.Is(err, ErrENINotAttached) {
+		// the record is stale, drop it so the retry allocates a new eni
+		msg := fmt.Sprintf("eni %s of pod is not attached to this instance, drop the record and retry", oldENIID)
+		ctx.Log().Warn(msg)
+		_ = tracing.RecordPodEvent(ctx.pod.Name, ctx.pod.Namespace, corev1.EventTypeWarning, "ENINotAttached", msg)
+		if delErr := n.deletePodResource(ctx.pod); delErr != nil {
+			ctx.Log().Errorf("error delete stale resource of pod: %v", delErr)
+		}
 		return nil, err
 	}
+	if err != nil {
+		return nil, n.throttledErr(ctx, err)
+	}
 	return res.(*types.ENI), nil
 }
 
@@ -165,16 +557,22 @@ func (n *networkService) allocateENIMultiIP(ctx *networkContext, old *types.PodR
 	if old.PodInfo != nil {
 		if len(oldENIIPRes) == 0 {
 			ctx.Log().Debugf("eniip for pod %s is zero", podInfoKey(old.PodInfo.Namespace, old.PodInfo.Name))
-		} else if len(oldENIIPRes) > 1 {
+		} else if len(oldENIIPRes) > 1+old.PodInfo.SecondaryIPs {
 			ctx.Log().Warnf("eniip for pod %s is more than one", podInfoKey(old.PodInfo.Namespace, old.PodInfo.Name))
 		} else {
+			// the first one is primary
 			oldENIIPID = oldENIIPRes[0].ID
+			if ctx.pod.PreferPreviousIP {
+				ctx.previousIP = oldENIIPRes[0].IPv4
+			}
 		}
 	}
 
+	_, endSpan := startSpan(ctx, "Allocate", tracing.Attr("resource_type", types.ResourceTypeENIIP))
 	res, err := n.eniIPResMgr.Allocate(ctx, oldENIIPID)
+	endSpan(err)
 	if err != nil {
-		return nil, err
+		return nil, n.throttledErr(ctx, err)
 	}
 	return res.(*types.ENIIP), nil
 }
@@ -192,14 +590,19 @@ func (n *networkService) allocateEIP(ctx *networkContext, old *types.PodResource
 		}
 	}
 
+	_, endSpan := startSpan(ctx, "Allocate", tracing.Attr("resource_type", types.ResourceTypeEIP))
 	res, err := n.eipResMgr.Allocate(ctx, oldEIPID)
+	endSpan(err)
 	if err != nil {
-		return nil, err
+		return nil, n.throttledErr(ctx, err)
 	}
 	return res.(*types.EIP), nil
 }
 
 func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*rpc.AllocIPReply, error) {
+	if n.readOnly {
+		return nil, errStandby
+	}
 	serviceLog.WithFields(map[string]interface{}{
 		"pod":         podInfoKey(r.K8SPodNamespace, r.K8SPodName),
 		"containerID": r.K8SPodInfraContainerId,
@@ -207,13 +610,20 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 		"ifName":      r.IfName,
 	}).Info("alloc ip req")
 
-	_, exist := n.pendingPods.LoadOrStore(podInfoKey(r.K8SPodNamespace, r.K8SPodName), struct{}{})
-	if exist {
-		return nil, fmt.Errorf("pod %s resource processing", podInfoKey(r.K8SPodNamespace, r.K8SPodName))
+	done, ok := n.markPending(podInfoKey(r.K8SPodNamespace, r.K8SPodName))
+	if !ok {
+		err := fmt.Errorf("pod %s resource processing", podInfoKey(r.K8SPodNamespace, r.K8SPodName))
+		n.recordRequestFailure(r, "resource_processing", err)
+		return nil, err
+	}
+	defer done()
+	if n.maxPendingPods > 0 {
+		if pending := n.pendingCount(); pending > n.maxPendingPods {
+			serviceLog.Warnf("reject alloc ip of pod %s, pending pods %d exceed %d", podInfoKey(r.K8SPodNamespace, r.K8SPodName), pending, n.maxPendingPods)
+			n.recordRequestFailure(r, "too_busy", ErrTooBusy)
+			return nil, ErrTooBusy
+		}
 	}
-	defer func() {
-		n.pendingPods.Delete(podInfoKey(r.K8SPodNamespace, r.K8SPodName))
-	}()
 
 	n.RLock()
 	defer n.RUnlock()
@@ -224,30 +634,69 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 	defer func() {
 		metric.RPCLatency.WithLabelValues("AllocIP", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
 	}()
+	ctx, endSpan := startSpan(ctx, "AllocIP", tracing.Attr("pod", podInfoKey(r.K8SPodNamespace, r.K8SPodName)))
+	defer func() {
+		endSpan(err)
+	}()
+	if n.slowAllocThreshold > 0 {
+		phases := newAllocPhases()
+		ctx = withAllocPhases(ctx, phases)
+		defer func() {
+			n.reportSlowAlloc(r, phases, time.Since(start))
+		}()
+	}
 
 	// 0. Get pod Info
-	podinfo, err := n.k8s.GetPod(r.K8SPodNamespace, r.K8SPodName)
+	_, endGetPodSpan := startSpan(ctx, "GetPod")
+	podinfo, err := n.getPod(ctx, r.K8SPodNamespace, r.K8SPodName)
+	endGetPodSpan(err)
 	if err != nil {
-		return nil, errors.Wrapf(err, "error get pod info for: %+v", r)
+		err = errors.Wrapf(err, "error get pod info for: %+v", r)
+		n.recordRequestFailure(r, "get_pod_failed", err)
+		return nil, err
+	}
+	// cni should not be called for host network pod, no resource is allocated in case of the cni chain misconfigured
+	if podinfo.HostNetwork {
+		serviceLog.Debugf("pod %s is host network, skip allocation", podInfoKey(podinfo.Namespace, podinfo.Name))
+		return &rpc.AllocIPReply{Success: false, Error: rpc.Error_ErrHostNetworkPod, IPv4: n.ipFamily.IPv4, IPv6: n.ipFamily.IPv6, NodeName: n.k8s.GetNodeName()}, nil
+	}
+	podinfo.NetworkPriority = n.clampPodNetworkPriority(podinfo)
+	podinfo.PreferPreviousIP = podinfo.PreferPreviousIP || n.preferPreviousIP
+	podinfo.DisableRPFilter = podinfo.DisableRPFilter || n.disableRPFilter
+	if podinfo.EipInfo.PodEipDisabled && podinfo.EipInfo.PodEip {
+		serviceLog.Infof("eip of pod %s is disabled by annotation %s", podInfoKey(podinfo.Namespace, podinfo.Name), podEnableEIP)
+		podinfo.EipInfo.PodEip = false
 	}
 
 	// 1. Init Context
+	allocCtx, cancel := n.allocContext(ctx, podinfo.PodNetworkType)
+	defer cancel()
+
 	networkContext := &networkContext{
-		Context:    ctx,
-		resources:  []types.ResourceItem{},
-		pod:        podinfo,
-		k8sService: n.k8s,
+		Context:        allocCtx,
+		resources:      []types.ResourceItem{},
+		pod:            podinfo,
+		k8sService:     n.k8s,
+		subCIDR:        n.podSubCIDR(podinfo),
+		subCIDRRetries: n.subCIDRAllocRetries,
 	}
-	allocIPReply := &rpc.AllocIPReply{IPv4: n.ipFamily.IPv4, IPv6: n.ipFamily.IPv6}
+	allocIPReply := &rpc.AllocIPReply{IPv4: n.ipFamily.IPv4, IPv6: n.ipFamily.IPv6, NodeName: n.k8s.GetNodeName()}
 
 	defer func() {
 		// roll back allocated resource when error
 		if err != nil {
 			networkContext.Log().Errorf("alloc result with error, %+v", err)
+			reason := rollbackReason(err)
+			if n.allocFailures != nil {
+				n.allocFailures.Add(podinfo, reason, err)
+			}
+			_, endRollbackSpan := startSpan(ctx, "Rollback", tracing.Attr("reason", reason))
+			defer endRollbackSpan(nil)
 			for _, res := range networkContext.resources {
+				metric.RollbackCount.WithLabelValues(res.Type, reason).Inc()
 				err = n.deletePodResource(podinfo)
 				networkContext.Log().Errorf("rollback res[%v] with error, %+v", res, err)
-				mgr := n.getResourceManagerForRes(res.Type)
+				mgr := n.getResourceManagerForRes(res)
 				if mgr == nil {
 					networkContext.Log().Warnf("error cleanup allocated network resource %s, %s: %v", res.ID, res.Type, err)
 					continue
@@ -257,8 +706,11 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 					networkContext.Log().Infof("rollback res error: %+v", err)
 				}
 			}
-		} else {
+		} else if allocIPReply.Success {
 			networkContext.Log().Infof("alloc result: %+v", allocIPReply)
+			if n.podHistory != nil {
+				n.podHistory.Add(podInfoKey(podinfo.Namespace, podinfo.Name), podHistoryAlloc, allocatedResources(podinfo, networkContext.resources, allocIPReply.NetConfs))
+			}
 
 			for _, netConfig := range allocIPReply.NetConfs {
 				if netConfig.IfName != IfEth0 && netConfig.IfName != "" {
@@ -274,19 +726,47 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 				if netConfig.BasicInfo.PodIP.IPv6 != "" {
 					ips = append(ips, netConfig.BasicInfo.PodIP.IPv6)
 				}
-				_ = n.k8s.PatchPodIPInfo(podinfo, strings.Join(ips, ","))
+				n.patchPodIPInfo(podinfo, strings.Join(ips, ","))
 			}
 		}
 	}()
 
+	if n.allocQueue != nil {
+		_, endQueueSpan := startSpan(ctx, "Queue")
+		var leave func(success bool)
+		leave, err = n.allocQueue.Enter(allocCtx, podInfoKey(podinfo.Namespace, podinfo.Name))
+		endQueueSpan(err)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error wait in alloc queue, %d pods waiting", n.allocQueue.Len())
+		}
+		defer func() {
+			leave(err == nil)
+		}()
+	}
+
 	// 2. Find old resource info
+	_, endGetResourceSpan := startSpan(ctx, "GetResource")
 	oldRes, err := n.getPodResource(podinfo)
+	endGetResourceSpan(err)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error get pod resources from db for pod %+v", podinfo)
 	}
 
 	if !n.verifyPodNetworkType(podinfo.PodNetworkType) {
-		return nil, fmt.Errorf("unexpect pod network type allocate, maybe daemon mode changed: %+v", podinfo.PodNetworkType)
+		// not retryable, report to cni by error code instead of grpc error
+		msg := unsupportedNetworkTypeMsg(podinfo.PodNetworkType, n.daemonMode)
+		networkContext.Log().Error(msg)
+		if n.allocFailures != nil {
+			n.allocFailures.Add(podinfo, "unsupported_network_type", errors.New(msg))
+		}
+		_ = tracing.RecordPodEvent(podinfo.Name, podinfo.Namespace, corev1.EventTypeWarning, "UnsupportedNetworkType", msg)
+		allocIPReply.Success = false
+		allocIPReply.Error = rpc.Error_ErrUnsupportedNetworkType
+		return allocIPReply, nil
+	}
+	if podinfo.VSwitchID != "" && !n.vSwitches.Has(podinfo.VSwitchID) {
+		err = fmt.Errorf("vswitch %s requested by pod is not configured, configured vswitches: %v", podinfo.VSwitchID, n.vSwitches.List())
+		return nil, err
 	}
 	var netConf []*rpc.NetConf
 	// 3. Allocate network resource for pod
@@ -294,7 +774,9 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 	case podNetworkTypeENIMultiIP:
 		allocIPReply.IPType = rpc.IPType_TypeENIMultiIP
 		var netConfs []*rpc.NetConf
+		_, endCRDSpan := startSpan(ctx, "WaitCRD")
 		netConfs, err = n.multiIPFromCRD(podinfo, true)
+		endCRDSpan(err)
 		if err != nil {
 			return nil, err
 		}
@@ -306,7 +788,23 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 				defaultIfSet = true
 			}
 		}
-		if !defaultIfSet {
+		if defaultIfSet {
+			err = n.putCRDPodResource(networkContext, r, oldRes, netConfs)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error put resource into store")
+			}
+		} else {
+			err = n.checkSecondaryIPs(podinfo)
+			if err != nil {
+				return nil, err
+			}
+			// the ip stick to the pod is reused
+			if len(oldRes.GetResourceItemByType(types.ResourceTypeENIIP)) == 0 {
+				err = n.checkIPReserve(podinfo, 1+podinfo.SecondaryIPs)
+				if err != nil {
+					return nil, err
+				}
+			}
 			// alloc eniip
 			var eniIP *types.ENIIP
 			eniIP, err = n.allocateENIMultiIP(networkContext, &oldRes)
@@ -323,7 +821,26 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 					return &s
 				}(r.K8SPodInfraContainerId),
 			}
+			newRes.RouteFingerprint = extraRoutesFingerprint(netConfs)
 			networkContext.resources = append(networkContext.resources, newRes.Resources...)
+			err = n.verifyDualStack(podinfo, eniIP.IPSet, allocIPReply)
+			if err != nil {
+				return nil, err
+			}
+			var secondaryIPs []*types.ENIIP
+			if n.ipAffinitySingleENI {
+				networkContext.eniAffinity = eniIP.ENI.MAC
+			}
+			secondaryIPs, err = n.allocateSecondaryIPs(networkContext, &oldRes)
+			if err != nil {
+				return nil, fmt.Errorf("error get allocated secondary eniip for: %+v, result: %+v", podinfo, err)
+			}
+			for _, secondaryIP := range secondaryIPs {
+				newRes.Resources = append(newRes.Resources, secondaryIP.ToResItems()...)
+			}
+			if n.ipAffinitySingleENI {
+				newRes.ENIAffinity = singleENIOf(eniIP, secondaryIPs)
+			}
 			if n.eipResMgr != nil && podinfo.EipInfo.PodEip {
 				podinfo.PodIPs = eniIP.IPSet
 				var eipRes *types.EIP
@@ -335,7 +852,12 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 				newRes.Resources = append(newRes.Resources, eipResItem...)
 				networkContext.resources = append(networkContext.resources, eipResItem...)
 			}
-			err = n.resourceDB.Put(podInfoKey(podinfo.Namespace, podinfo.Name), newRes)
+			var extraRoutes []*rpc.Route
+			extraRoutes, err = n.reviewAllocation(networkContext, newRes)
+			if err != nil {
+				return nil, err
+			}
+			err = n.putPodResource(networkContext, newRes)
 			if err != nil {
 				return nil, errors.Wrapf(err, "error put resource into store")
 			}
@@ -343,13 +865,15 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 			netConf = append(netConf, &rpc.NetConf{
 				BasicInfo: &rpc.BasicInfo{
 					PodIP:       eniIP.IPSet.ToRPC(),
-					PodCIDR:     eniIP.ENI.VSwitchCIDR.ToRPC(),
+					PodCIDR:     n.podCIDRForENIIP(networkContext, eniIP).ToRPC(),
 					GatewayIP:   eniIP.ENI.GatewayIP.ToRPC(),
 					ServiceCIDR: n.k8s.GetServiceCIDR().ToRPC(),
 				},
 				ENIInfo: &rpc.ENIInfo{
-					MAC:   eniIP.ENI.MAC,
-					Trunk: false,
+					MAC:             eniIP.ENI.MAC,
+					Trunk:           false,
+					ENIPrimaryIP:    eniIP.ENI.PrimaryIP.ToRPC(),
+					DisableRPFilter: podinfo.DisableRPFilter,
 				},
 				Pod: &rpc.Pod{
 					Ingress:         podinfo.TcIngress,
@@ -357,24 +881,45 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 					NetworkPriority: podinfo.NetworkPriority,
 				},
 				IfName:       "",
-				ExtraRoutes:  nil,
+				ExtraRoutes:  extraRoutes,
 				DefaultRoute: true,
 			})
+			for i, secondaryIP := range secondaryIPs {
+				netConf = append(netConf, n.secondaryIPNetConf(networkContext, podinfo, secondaryIP, secondaryIfName(i)))
+			}
 		}
 
 		err = defaultForNetConf(netConf)
 		if err != nil {
 			return nil, err
 		}
+		if n.validateGateways {
+			err = validateIPv6Gateway(netConf)
+			if err != nil {
+				return nil, err
+			}
+		}
 		allocIPReply.Success = true
 	case podNetworkTypeVPCENI:
 		allocIPReply.IPType = rpc.IPType_TypeVPCENI
 		if n.ipamType == types.IPAMTypeCRD {
 			var netConfs []*rpc.NetConf
+			if mgr, ok := n.eniResMgr.(*eniResourceManager); ok && n.enableTrunk && podinfo.PodENI {
+				err = mgr.ensureTrunk(allocCtx)
+				if err != nil {
+					return nil, err
+				}
+			}
+			_, endCRDSpan := startSpan(ctx, "WaitCRD")
 			netConfs, err = n.exclusiveENIFromCRD(podinfo, true)
+			endCRDSpan(err)
 			if err != nil {
 				return nil, err
 			}
+			err = n.putCRDPodResource(networkContext, r, oldRes, netConfs)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error put resource into store")
+			}
 			netConf = append(netConf, netConfs...)
 		} else {
 			var eni *types.ENI
@@ -393,6 +938,10 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 				}(r.K8SPodInfraContainerId),
 			}
 			networkContext.resources = append(networkContext.resources, newRes.Resources...)
+			err = n.verifyDualStack(podinfo, eni.PrimaryIP, allocIPReply)
+			if err != nil {
+				return nil, err
+			}
 			if n.eipResMgr != nil && podinfo.EipInfo.PodEip {
 				podinfo.PodIPs = eni.PrimaryIP
 				var eipRes *types.EIP
@@ -404,7 +953,12 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 				newRes.Resources = append(newRes.Resources, eipResItem...)
 				networkContext.resources = append(networkContext.resources, eipResItem...)
 			}
-			err = n.resourceDB.Put(podInfoKey(podinfo.Namespace, podinfo.Name), newRes)
+			var extraRoutes []*rpc.Route
+			extraRoutes, err = n.reviewAllocation(networkContext, newRes)
+			if err != nil {
+				return nil, err
+			}
+			err = n.putPodResource(networkContext, newRes)
 			if err != nil {
 				return nil, errors.Wrapf(err, "error put resource into store")
 			}
@@ -416,8 +970,9 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 					ServiceCIDR: n.k8s.GetServiceCIDR().ToRPC(),
 				},
 				ENIInfo: &rpc.ENIInfo{
-					MAC:   eni.MAC,
-					Trunk: false,
+					MAC:             eni.MAC,
+					Trunk:           false,
+					DisableRPFilter: podinfo.DisableRPFilter,
 				},
 				Pod: &rpc.Pod{
 					Ingress:         podinfo.TcIngress,
@@ -425,7 +980,7 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 					NetworkPriority: podinfo.NetworkPriority,
 				},
 				IfName:       "",
-				ExtraRoutes:  nil,
+				ExtraRoutes:  extraRoutes,
 				DefaultRoute: true,
 			})
 		}
@@ -448,7 +1003,12 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 			}(r.K8SPodInfraContainerId),
 		}
 		networkContext.resources = append(networkContext.resources, newRes.Resources...)
-		err = n.resourceDB.Put(podInfoKey(podinfo.Namespace, podinfo.Name), newRes)
+		var extraRoutes []*rpc.Route
+		extraRoutes, err = n.reviewAllocation(networkContext, newRes)
+		if err != nil {
+			return nil, err
+		}
+		err = n.putPodResource(networkContext, newRes)
 		if err != nil {
 			return nil, errors.Wrapf(err, "error put resource into store")
 		}
@@ -466,12 +1026,10 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 				NetworkPriority: podinfo.NetworkPriority,
 			},
 			IfName:       "",
-			ExtraRoutes:  nil,
+			ExtraRoutes:  append(extraRoutes, n.vpcExtraRoutes...),
 			DefaultRoute: true,
 		})
 		allocIPReply.Success = true
-	default:
-		return nil, fmt.Errorf("not support pod network type")
 	}
 
 	// 4. grpc connection
@@ -480,26 +1038,36 @@ func (n *networkService) AllocIP(ctx context.Context, r *rpc.AllocIPRequest) (*r
 		return nil, fmt.Errorf("error on grpc connection, %w", err)
 	}
 
+	if dns := mergeDNS(n.dns, podinfo.DNS); dns != nil {
+		for _, conf := range netConf {
+			if defaultIf(conf.IfName) {
+				conf.DNS = dns
+			}
+		}
+	}
+
 	allocIPReply.NetConfs = netConf
 	allocIPReply.EnableTrunking = n.enableTrunk
+	allocIPReply.SchemaVersion = rpc.NetConfSchemaVersion
 
 	// 5. return allocate result
 	return allocIPReply, err
 }
 
 func (n *networkService) ReleaseIP(ctx context.Context, r *rpc.ReleaseIPRequest) (*rpc.ReleaseIPReply, error) {
+	if n.readOnly {
+		return nil, errStandby
+	}
 	serviceLog.WithFields(map[string]interface{}{
 		"pod":         podInfoKey(r.K8SPodNamespace, r.K8SPodName),
 		"containerID": r.K8SPodInfraContainerId,
 	}).Info("release ip req")
 
-	_, exist := n.pendingPods.LoadOrStore(podInfoKey(r.K8SPodNamespace, r.K8SPodName), struct{}{})
-	if exist {
+	done, ok := n.markPending(podInfoKey(r.K8SPodNamespace, r.K8SPodName))
+	if !ok {
 		return nil, fmt.Errorf("pod %s resource processing", podInfoKey(r.K8SPodNamespace, r.K8SPodName))
 	}
-	defer func() {
-		n.pendingPods.Delete(podInfoKey(r.K8SPodNamespace, r.K8SPodName))
-	}()
+	defer done()
 
 	n.RLock()
 	defer n.RUnlock()
@@ -510,9 +1078,15 @@ func (n *networkService) ReleaseIP(ctx context.Context, r *rpc.ReleaseIPRequest)
 	defer func() {
 		metric.RPCLatency.WithLabelValues("ReleaseIP", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
 	}()
+	ctx, endSpan := startSpan(ctx, "ReleaseIP", tracing.Attr("pod", podInfoKey(r.K8SPodNamespace, r.K8SPodName)))
+	defer func() {
+		endSpan(err)
+	}()
 
 	// 0. Get pod Info
+	_, endGetPodSpan := startSpan(ctx, "GetPod")
 	podinfo, err := n.k8s.GetPod(r.K8SPodNamespace, r.K8SPodName)
+	endGetPodSpan(err)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error get pod info for: %+v", r)
 	}
@@ -549,28 +1123,75 @@ func (n *networkService) ReleaseIP(ctx context.Context, r *rpc.ReleaseIPRequest)
 	}
 	if oldRes.ContainerID != nil {
 		if r.K8SPodInfraContainerId != *oldRes.ContainerID {
-			netCtx.Log().Warnf("cni request not macth stored resource, expect %s, got %s, ignored", *oldRes.ContainerID, r.K8SPodInfraContainerId)
+			n.containerIDMismatched(netCtx, "ReleaseIP", *oldRes.ContainerID, r.K8SPodInfraContainerId)
 			return releaseReply, nil
 		}
+		n.containerIDMatched(podinfo)
+	}
+	if len(r.ResourceTypes) > 0 {
+		err = n.releaseResourceTypes(netCtx, oldRes, r.ResourceTypes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error release %v resources for: %+v", r.ResourceTypes, r)
+		}
+		return releaseReply, nil
+	}
+	var stickEIPRes, released []types.ResourceItem
+	if podinfo.IPStickTime == 0 && n.eipStickTime > 0 {
+		// eip and the ip it associated with are released by gc after stick time
+		stickEIPRes = stickEIPResources(oldRes).Resources
 	}
+	stickRes := resourceIDs(stickEIPRes)
 	for _, res := range oldRes.Resources {
 		//record old resource for pod
 		netCtx.resources = append(netCtx.resources, res)
-		mgr := n.getResourceManagerForRes(res.Type)
+		mgr := n.getResourceManagerForRes(res)
 		if mgr == nil {
 			netCtx.Log().Warnf("error cleanup allocated network resource %s, %s: %v", res.ID, res.Type, err)
 			continue
 		}
+		if _, ok := stickRes[res.ID]; ok {
+			continue
+		}
 		if podinfo.IPStickTime == 0 {
-			if err = mgr.Release(netCtx, res); err != nil && err != pool.ErrInvalidState {
+			_, endReleaseSpan := startSpan(netCtx, "Release", tracing.Attr("resource_type", res.Type))
+			err = mgr.Release(netCtx, res)
+			endReleaseSpan(err)
+			if err != nil && err != pool.ErrInvalidState {
 				return nil, errors.Wrapf(err, "error release request network resource for: %+v", r)
 			}
-			if err = n.deletePodResource(podinfo); err != nil {
+			released = append(released, res)
+			_, endDeleteSpan := startSpan(netCtx, "DeleteResource")
+			err = n.deletePodResource(podinfo)
+			endDeleteSpan(err)
+			if err != nil {
 				return nil, errors.Wrapf(err, "error delete resource from db: %+v", r)
 			}
 		}
 	}
 
+	if oldRes.PodInfo != nil && len(oldRes.Resources) == 0 {
+		// the record of the pod its network is from crd
+		err = n.deletePodResource(podinfo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error delete resource from db: %+v", r)
+		}
+	}
+
+	if len(stickEIPRes) > 0 {
+		// the stick time starts from the release, it is kept in the record so that restart does not reset it
+		kept := stickEIPResources(oldRes)
+		kept.PodInfo = podinfo
+		kept.EIPStickUntil = time.Now().Add(n.eipStickTime)
+		err = n.putPodResource(netCtx, kept)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error store stick eip to resource db: %+v", r)
+		}
+	}
+
+	if n.podHistory != nil && len(released) > 0 {
+		n.podHistory.Add(podInfoKey(podinfo.Namespace, podinfo.Name), podHistoryRelease, released)
+	}
+
 	if netCtx.Err() != nil {
 		err = ctx.Err()
 		return nil, fmt.Errorf("error on grpc connection, %w", err)
@@ -586,6 +1207,8 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 	if err != nil {
 		return nil, errors.Wrapf(err, "error get pod info for: %+v", r)
 	}
+	podinfo.NetworkPriority = n.podNetworkPriority(podinfo)
+	podinfo.DisableRPFilter = podinfo.DisableRPFilter || n.disableRPFilter
 
 	if !n.verifyPodNetworkType(podinfo.PodNetworkType) {
 		return nil, fmt.Errorf("unexpect pod network type get info, maybe daemon mode changed: %+v", podinfo.PodNetworkType)
@@ -599,7 +1222,7 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 		k8sService: n.k8s,
 	}
 
-	getIPInfoResult := &rpc.GetInfoReply{IPv4: n.ipFamily.IPv4, IPv6: n.ipFamily.IPv6}
+	getIPInfoResult := &rpc.GetInfoReply{IPv4: n.ipFamily.IPv4, IPv6: n.ipFamily.IPv6, NodeName: n.k8s.GetNodeName()}
 
 	defer func() {
 		networkContext.Log().Debugf("getIpInfo result: %+v", getIPInfoResult)
@@ -615,9 +1238,10 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 
 	if podRes.ContainerID != nil {
 		if r.K8SPodInfraContainerId != *podRes.ContainerID {
-			networkContext.Log().Warnf("cni request not macth stored resource, expect %s, got %s, ignored", *podRes.ContainerID, r.K8SPodInfraContainerId)
+			n.containerIDMismatched(networkContext, "GetIPInfo", *podRes.ContainerID, r.K8SPodInfraContainerId)
 			return getIPInfoResult, nil
 		}
+		n.containerIDMatched(podinfo)
 	}
 
 	var netConf []*rpc.NetConf
@@ -626,13 +1250,14 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 	case podNetworkTypeENIMultiIP:
 		getIPInfoResult.IPType = rpc.IPType_TypeENIMultiIP
 		netConfs, err2 := n.multiIPFromCRD(podinfo, false)
-		if err != nil {
+		if err2 != nil {
 			if k8sErr.IsNotFound(err2) {
 				getIPInfoResult.Error = rpc.Error_ErrCRDNotFound
 			}
 			return getIPInfoResult, nil
 		}
 		netConf = append(netConf, netConfs...)
+		n.reportRoutesChanged(r, podRes, netConfs, getIPInfoResult)
 
 		defaultIfSet := false
 		for _, cfg := range netConf {
@@ -643,7 +1268,7 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 		if !defaultIfSet {
 			resItems := podRes.GetResourceItemByType(types.ResourceTypeENIIP)
 			if len(resItems) > 0 {
-				// only have one
+				// the first one is primary, others are secondary ips
 				res, err := n.eniIPResMgr.Stat(networkContext, resItems[0].ID)
 				if err == nil {
 					eniIP := res.(*types.ENIIP)
@@ -651,13 +1276,15 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 					netConf = append(netConf, &rpc.NetConf{
 						BasicInfo: &rpc.BasicInfo{
 							PodIP:       eniIP.IPSet.ToRPC(),
-							PodCIDR:     eniIP.ENI.VSwitchCIDR.ToRPC(),
+							PodCIDR:     n.podCIDRForENIIP(networkContext, eniIP).ToRPC(),
 							GatewayIP:   eniIP.ENI.GatewayIP.ToRPC(),
 							ServiceCIDR: n.k8s.GetServiceCIDR().ToRPC(),
 						},
 						ENIInfo: &rpc.ENIInfo{
-							MAC:   eniIP.ENI.MAC,
-							Trunk: false,
+							MAC:             eniIP.ENI.MAC,
+							Trunk:           false,
+							ENIPrimaryIP:    eniIP.ENI.PrimaryIP.ToRPC(),
+							DisableRPFilter: podinfo.DisableRPFilter,
 						},
 						Pod: &rpc.Pod{
 							Ingress:         podinfo.TcIngress,
@@ -672,6 +1299,14 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 					serviceLog.Debugf("failed to get res stat %s", resItems[0].ID)
 				}
 			}
+			for i := 1; i < len(resItems); i++ {
+				res, err := n.eniIPResMgr.Stat(networkContext, resItems[i].ID)
+				if err != nil {
+					serviceLog.Debugf("failed to get res stat %s", resItems[i].ID)
+					continue
+				}
+				netConf = append(netConf, n.secondaryIPNetConf(networkContext, podinfo, res.(*types.ENIIP), secondaryIfName(i-1)))
+			}
 		}
 		err = defaultForNetConf(netConf)
 		if err != nil {
@@ -691,6 +1326,7 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 				Egress:          podinfo.TcEgress,
 				NetworkPriority: podinfo.NetworkPriority,
 			},
+			ExtraRoutes:  n.vpcExtraRoutes,
 			DefaultRoute: true,
 		})
 	case podNetworkTypeVPCENI:
@@ -704,6 +1340,7 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 				return getIPInfoResult, nil
 			}
 			netConf = append(netConf, netConfs...)
+			n.reportRoutesChanged(r, podRes, netConfs, getIPInfoResult)
 		} else {
 			resItems := podRes.GetResourceItemByType(types.ResourceTypeENI)
 			if len(resItems) > 0 {
@@ -720,8 +1357,9 @@ func (n *networkService) GetIPInfo(ctx context.Context, r *rpc.GetInfoRequest) (
 							ServiceCIDR: n.k8s.GetServiceCIDR().ToRPC(),
 						},
 						ENIInfo: &rpc.ENIInfo{
-							MAC:   eni.MAC,
-							Trunk: podinfo.PodENI && n.enableTrunk && eni.Trunk,
+							MAC:             eni.MAC,
+							Trunk:           podinfo.PodENI && n.enableTrunk && eni.Trunk,
+							DisableRPFilter: podinfo.DisableRPFilter,
 						},
 						Pod: &rpc.Pod{
 							Ingress:         podinfo.TcIngress,
@@ -775,138 +1413,530 @@ func (n *networkService) RecordEvent(_ context.Context, r *rpc.EventRequest) (*r
 	return reply, nil
 }
 
-func (n *networkService) verifyPodNetworkType(podNetworkMode string) bool {
-	return (n.daemonMode == daemonModeVPC && //vpc
-		(podNetworkMode == podNetworkTypeVPCENI || podNetworkMode == podNetworkTypeVPCIP)) ||
-		// eni-multi-ip
-		(n.daemonMode == daemonModeENIMultiIP && podNetworkMode == podNetworkTypeENIMultiIP) ||
-		// eni-only
-		(n.daemonMode == daemonModeENIOnly && podNetworkMode == podNetworkTypeVPCENI)
+// markPending mark the pod is in processing, return false if the pod is already in processing
+// the returned func should be called when processing is done
+func (n *networkService) markPending(key string) (func(), bool) {
+	start := time.Now()
+	if _, exist := n.pendingPods.LoadOrStore(key, start); exist {
+		return nil, false
+	}
+	return func() {
+		// the entry may be evicted and stored by other call
+		if v, ok := n.pendingPods.Load(key); ok && v.(time.Time).Equal(start) {
+			n.pendingPods.Delete(key)
+		}
+	}, true
 }
 
-func (n *networkService) startGarbageCollectionLoop() {
-	// period do network resource gc
-	gcTicker := time.NewTicker(gcPeriod)
-	go func() {
-		for range gcTicker.C {
-			serviceLog.Debugf("do resource gc on node")
-			n.Lock()
-			pods, err := n.k8s.GetLocalPods()
-			if err != nil {
-				serviceLog.Warnf("error get local pods for gc")
-				n.Unlock()
-				continue
-			}
-			podKeyMap := make(map[string]bool)
-
-			for _, pod := range pods {
-				if !pod.SandboxExited {
-					podKeyMap[podInfoKey(pod.Namespace, pod.Name)] = true
-				}
-			}
+// pendingCount return the count of pods in processing
+func (n *networkService) pendingCount() int {
+	count := 0
+	n.pendingPods.Range(func(key, value interface{}) bool {
+		count++
+		return true
+	})
+	return count
+}
 
-			var (
-				inUseSet         = make(map[string]map[string]types.ResourceItem)
-				expireSet        = make(map[string]map[string]types.ResourceItem)
-				relateExpireList = make([]string, 0)
-			)
+// pendingPod the pod in processing
+type pendingPod struct {
+	key   string
+	start time.Time
+}
 
-			resRelateList, err := n.resourceDB.List()
-			if err != nil {
-				serviceLog.Warnf("error list resource db for gc")
-				n.Unlock()
-				continue
-			}
+// listPendingPods return the pods in processing, the longest pending first
+func (n *networkService) listPendingPods() []pendingPod {
+	var pods []pendingPod
+	n.pendingPods.Range(func(key, value interface{}) bool {
+		pods = append(pods, pendingPod{key: key.(string), start: value.(time.Time)})
+		return true
+	})
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].start.Before(pods[j].start)
+	})
+	return pods
+}
 
-			for _, resRelateObj := range resRelateList {
-				resRelate := resRelateObj.(types.PodResources)
-				_, podExist := podKeyMap[podInfoKey(resRelate.PodInfo.Namespace, resRelate.PodInfo.Name)]
-				if !podExist {
-					if resRelate.PodInfo.IPStickTime != 0 {
-						// delay resource garbage collection for sticky ip
-						resRelate.PodInfo.IPStickTime = 0
-						if err = n.resourceDB.Put(podInfoKey(resRelate.PodInfo.Namespace, resRelate.PodInfo.Name),
-							resRelate); err != nil {
-							serviceLog.Warnf("error store pod info to resource db")
-						}
-						podExist = true
-					} else {
-						relateExpireList = append(relateExpireList, podInfoKey(resRelate.PodInfo.Namespace, resRelate.PodInfo.Name))
-					}
+// evictStuckPendingPods remove the pending entries older than pendingPodTTL,
+// which are leaked by a panic or killed call, so the pod can be allocated again
+func (n *networkService) evictStuckPendingPods() {
+	n.pendingPods.Range(func(key, value interface{}) bool {
+		start := value.(time.Time)
+		if time.Since(start) > n.pendingPodTTL {
+			serviceLog.Warnf("evict stuck pending pod %s, pending since %s", key, start.Format(time.RFC3339))
+			n.pendingPods.Delete(key)
+		}
+		return true
+	})
+}
+
+// GetPodStatus return the allocation status of the pod, it will not hold the service lock
+func (n *networkService) GetPodStatus(_ context.Context, r *rpc.GetPodStatusRequest) (*rpc.GetPodStatusReply, error) {
+	key := podInfoKey(r.K8SPodNamespace, r.K8SPodName)
+	_, pending := n.pendingPods.Load(key)
+
+	obj, err := n.resourceDB.Get(key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, status.Errorf(codes.NotFound, "no resource record for pod %s", key)
+		}
+		return nil, err
+	}
+	podRes := obj.(types.PodResources)
+
+	reply := &rpc.GetPodStatusReply{
+		Pending: pending,
+	}
+	if podRes.PodInfo != nil {
+		reply.PodNetworkType = podRes.PodInfo.PodNetworkType
+		reply.NetworkTypeValid = n.verifyPodNetworkType(podRes.PodInfo.PodNetworkType)
+	}
+	if podRes.ContainerID != nil {
+		reply.ContainerID = *podRes.ContainerID
+	}
+	if podRes.NetNs != nil {
+		reply.NetNs = *podRes.NetNs
+	}
+	for _, res := range podRes.Resources {
+		reply.Resources = append(reply.Resources, &rpc.ResourceItem{
+			Type: res.Type,
+			ID:   res.ID,
+		})
+	}
+	return reply, nil
+}
+
+// WarmPool grow the resource pool to the target idle count synchronously
+func (n *networkService) WarmPool(ctx context.Context, r *rpc.WarmPoolRequest) (*rpc.WarmPoolReply, error) {
+	if n.readOnly {
+		return nil, errStandby
+	}
+	var mgr ResourceManager
+	switch n.daemonMode {
+	case daemonModeENIMultiIP:
+		mgr = n.eniIPResMgr
+	case daemonModeENIOnly:
+		mgr = n.eniResMgr
+	}
+	warmer, ok := mgr.(ResourceWarmer)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "warm pool is not supported in daemon mode %s", n.daemonMode)
+	}
+	added, err := warmer.Warm(ctx, int(r.Idle))
+	serviceLog.Infof("warm pool to idle %d, added %d, err: %v", r.Idle, added, err)
+	reply := &rpc.WarmPoolReply{
+		Added: int32(added),
+	}
+	if err != nil {
+		reply.Error = err.Error()
+	}
+	return reply, nil
+}
+
+// TriggerGC run a gc pass immediately, instead of waiting for the gc period
+func (n *networkService) TriggerGC(ctx context.Context, r *rpc.Empty) (*rpc.TriggerGCReply, error) {
+	if n.readOnly {
+		return nil, errStandby
+	}
+	reclaimed, err := n.gc()
+	serviceLog.Infof("triggered gc, reclaimed %d, err: %v", reclaimed, err)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error do gc, reclaimed %d: %v", reclaimed, err)
+	}
+	return &rpc.TriggerGCReply{
+		Reclaimed: int32(reclaimed),
+	}, nil
+}
+
+// poolResourceManager return the resource manager the pod ips allocated from by the daemon mode,
+// nil for the mode without ip pool
+func (n *networkService) poolResourceManager() ResourceManager {
+	switch n.daemonMode {
+	case daemonModeENIMultiIP:
+		return n.eniIPResMgr
+	case daemonModeENIOnly:
+		return n.eniResMgr
+	}
+	return nil
+}
+
+// GetAllocatableCapacity report the ips can be allocated on the node and the factor limits more pods,
+// only the counters of pool are read, no openapi called
+func (n *networkService) GetAllocatableCapacity(ctx context.Context, r *rpc.Empty) (*rpc.AllocatableCapacityReply, error) {
+	if n.readOnly {
+		return &rpc.AllocatableCapacityReply{Limit: rpc.CapacityLimit_CapacityLimitReadOnly}, nil
+	}
+	mgr := n.poolResourceManager()
+	counter, ok := mgr.(ResourceCounter)
+	if !ok {
+		return &rpc.AllocatableCapacityReply{Limit: rpc.CapacityLimit_CapacityLimitUnsupported}, nil
+	}
+	capacity, ok := mgr.(ResourceCapacity)
+	if !ok {
+		return &rpc.AllocatableCapacityReply{Limit: rpc.CapacityLimit_CapacityLimitUnsupported}, nil
+	}
+	free := counter.Free()
+	creatable, max := capacity.Capacity()
+	reply := &rpc.AllocatableCapacityReply{
+		Free:     int32(free),
+		Capacity: int32(max),
+		CanGrow:  creatable > 0,
+	}
+	switch {
+	case n.daemonMode == daemonModeENIMultiIP && n.reserveIPsForCritical > 0 && free <= n.reserveIPsForCritical:
+		// same as checkIPReserve, no more non critical pod is admitted
+		reply.Limit = rpc.CapacityLimit_CapacityLimitReserved
+	case creatable <= 0:
+		reply.Limit = rpc.CapacityLimit_CapacityLimitPoolCapacity
+	}
+	return reply, nil
+}
+
+// SetLogLevel change the log level of the running daemon, the level is not persisted across restarts
+func (n *networkService) SetLogLevel(ctx context.Context, r *rpc.SetLogLevelRequest) (*rpc.SetLogLevelReply, error) {
+	previous := logger.DefaultLogger.GetLevel().String()
+	err := logger.SetLevel(r.GetLevel())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid log level %q: %v", r.GetLevel(), err)
+	}
+	return &rpc.SetLogLevelReply{Previous: previous}, nil
+}
+
+// shutdown release the idle resources in pool back to ecs if the node is terminating,
+// in use resources are left for the cni DEL
+func (n *networkService) shutdown(ctx context.Context) {
+	if !n.releaseAllOnShutdown || n.readOnly {
+		return
+	}
+	terminating, err := n.k8s.IsNodeTerminating()
+	if err != nil {
+		serviceLog.Warnf("error check node terminating, skip release resources: %v", err)
+		return
+	}
+	if !terminating {
+		return
+	}
+
+	n.Lock()
+	defer n.Unlock()
+
+	var summary []string
+	for _, resType := range []string{types.ResourceTypeENIIP, types.ResourceTypeENI} {
+		drainer, ok := n.getResourceManagerForRes(types.ResourceItem{Type: resType}).(ResourceDrainer)
+		if !ok {
+			continue
+		}
+		released, err := drainer.Drain(ctx)
+		serviceLog.Infof("release idle %s on shutdown, released %d, err: %v", resType, released, err)
+		msg := fmt.Sprintf("%s released %d", resType, released)
+		if err != nil {
+			msg += fmt.Sprintf(", error: %v", err)
+		}
+		summary = append(summary, msg)
+	}
+	if len(summary) > 0 {
+		n.k8s.RecordNodeEvent(corev1.EventTypeNormal, "ReleaseOnShutdown", "release idle resources on node terminating, "+strings.Join(summary, "; "))
+	}
+}
+
+func (n *networkService) verifyPodNetworkType(podNetworkMode string) bool {
+	return (n.daemonMode == daemonModeVPC && //vpc
+		(podNetworkMode == podNetworkTypeVPCENI || podNetworkMode == podNetworkTypeVPCIP)) ||
+		// eni-multi-ip
+		(n.daemonMode == daemonModeENIMultiIP && podNetworkMode == podNetworkTypeENIMultiIP) ||
+		// eni-only
+		(n.daemonMode == daemonModeENIOnly && podNetworkMode == podNetworkTypeVPCENI)
+}
+
+func (n *networkService) startGarbageCollectionLoop() {
+	// period do network resource gc
+	gcTicker := time.NewTicker(gcPeriod)
+	go func() {
+		for range gcTicker.C {
+			_, _ = n.gc()
+		}
+	}()
+}
+
+// gc do one pass of resource garbage collection, return the count of resources reclaimed
+func (n *networkService) gc() (int, error) {
+	serviceLog.Debugf("do resource gc on node")
+	n.Lock()
+	defer n.Unlock()
+	pods, err := n.k8s.GetLocalPods()
+	if err != nil {
+		serviceLog.Warnf("error get local pods for gc")
+		return 0, err
+	}
+	podKeyMap := make(map[string]bool)
+
+	for _, pod := range pods {
+		if !pod.SandboxExited {
+			podKeyMap[podInfoKey(pod.Namespace, pod.Name)] = true
+		}
+	}
+
+	var (
+		inUseSet         = make(map[resourceManagerKey]map[string]types.ResourceItem)
+		expireSet        = make(map[resourceManagerKey]map[string]types.ResourceItem)
+		relateExpireList = make([]string, 0)
+		// quarantinedRes the quarantined resources of the pods expired, the record of them is kept to be retried
+		quarantinedRes = make(map[string]types.PodResources)
+		// expireOwner the pod and the history action of the resources expired
+		expireOwner = make(map[resourceManagerKey]map[string]gcReleaseOwner)
+	)
+
+	resRelateList, err := n.resourceDB.List()
+	if err != nil {
+		serviceLog.Warnf("error list resource db for gc")
+		return 0, err
+	}
+
+	for _, resRelateObj := range resRelateList {
+		resRelate := resRelateObj.(types.PodResources)
+		podKey := podInfoKey(resRelate.PodInfo.Namespace, resRelate.PodInfo.Name)
+		_, podExist := podKeyMap[podKey]
+		var stickRes map[string]struct{}
+		gcAction := podHistoryGCPodDeleted
+		if !podExist {
+			if resRelate.PodInfo.IPStickTime != 0 && n.ipStickExpired(resRelate) {
+				serviceLog.Infof("ip stick of pod %s expired, allocated at %s", podKey, resRelate.AllocatedAt.Format(time.RFC3339))
+				resRelate.PodInfo.IPStickTime = 0
+				gcAction = podHistoryGCIPStickExpired
+			}
+			if resRelate.PodInfo.IPStickTime != 0 {
+				// delay resource garbage collection for sticky ip
+				resRelate.PodInfo.IPStickTime = 0
+				resRelate = n.releaseNoStickIPv6(podKey, resRelate)
+				if err = n.resourceDB.Put(podKey, resRelate); err != nil {
+					serviceLog.Warnf("error store pod info to resource db")
 				}
-				for _, res := range resRelate.Resources {
-					if _, ok := inUseSet[res.Type]; !ok {
-						inUseSet[res.Type] = make(map[string]types.ResourceItem)
-						expireSet[res.Type] = make(map[string]types.ResourceItem)
-					}
-					// already in use by others
-					if _, ok := inUseSet[res.Type][res.ID]; ok {
-						continue
-					}
-					if podExist {
-						// remove resource from expirelist
-						delete(expireSet[res.Type], res.ID)
-						inUseSet[res.Type][res.ID] = res
-					} else {
-						if _, ok := inUseSet[res.Type][res.ID]; !ok {
-							expireSet[res.Type][res.ID] = res
-						}
-					}
+				podExist = true
+			} else if n.eipInStickTime(podKey, &resRelate) {
+				// delay eip garbage collection, only the eip and the ip it associated with are kept in resource db
+				stickEIPRes := stickEIPResources(resRelate)
+				stickRes = resourceIDs(stickEIPRes.Resources)
+				if err = n.resourceDB.Put(podKey, stickEIPRes); err != nil {
+					serviceLog.Warnf("error store stick eip to resource db")
+				}
+			} else {
+				if !resRelate.EIPStickUntil.IsZero() {
+					gcAction = podHistoryGCEIPStickExpired
 				}
+				relateExpireList = append(relateExpireList, podKey)
 			}
-			gcDone := true
-			for mgrType := range inUseSet {
-				mgr, ok := n.mgrForResource[mgrType]
-				if ok {
-					serviceLog.Debugf("start garbage collection for %v, list: %+v， %+v", mgrType, inUseSet[mgrType], expireSet[mgrType])
-					err = mgr.GarbageCollection(inUseSet[mgrType], expireSet[mgrType])
-					if err != nil {
-						serviceLog.Warnf("error do garbage collection for %+v, inuse: %v, expire: %v, err: %v", mgrType, inUseSet[mgrType], expireSet[mgrType], err)
-						gcDone = false
-					}
+		}
+		for _, res := range resRelate.Resources {
+			key := resourceManagerKey{resType: res.Type, poolID: res.GetPoolID()}
+			if _, ok := inUseSet[key]; !ok {
+				inUseSet[key] = make(map[string]types.ResourceItem)
+				expireSet[key] = make(map[string]types.ResourceItem)
+				expireOwner[key] = make(map[string]gcReleaseOwner)
+			}
+			// already in use by others
+			if _, ok := inUseSet[key][res.ID]; ok {
+				continue
+			}
+			_, stick := stickRes[res.ID]
+			if podExist || stick {
+				// remove resource from expirelist
+				delete(expireSet[key], res.ID)
+				inUseSet[key][res.ID] = res
+			} else if n.gcQuarantine != nil && n.gcQuarantine.Quarantined(res.ID) {
+				serviceLog.Debugf("skip gc quarantined resource %s", res.ID)
+				kept, ok := quarantinedRes[podKey]
+				if !ok {
+					kept = resRelate
+					kept.Resources = nil
+				}
+				kept.Resources = append(kept.Resources, res)
+				quarantinedRes[podKey] = kept
+			} else {
+				if _, ok := inUseSet[key][res.ID]; !ok {
+					expireSet[key][res.ID] = res
+					expireOwner[key][res.ID] = gcReleaseOwner{podKey: podKey, action: gcAction}
 				}
 			}
-			if gcDone {
-				func() {
-					resMap, ok := expireSet[types.ResourceTypeENIIP]
-					if !ok {
+		}
+	}
+	var gcErrs []error
+	reclaimed := 0
+	mgrKeys := make([]resourceManagerKey, 0, len(inUseSet))
+	for mgrKey := range inUseSet {
+		mgrKeys = append(mgrKeys, mgrKey)
+	}
+	sortResourceManagerKeysForGC(mgrKeys)
+	for _, mgrKey := range mgrKeys {
+		mgr, ok := n.mgrForResource[mgrKey]
+		if ok {
+			serviceLog.Debugf("start garbage collection for %v, list: %+v， %+v", mgrKey, inUseSet[mgrKey], expireSet[mgrKey])
+			released, err := mgr.GarbageCollection(inUseSet[mgrKey], expireSet[mgrKey])
+			reclaimed += released
+			if err != nil {
+				serviceLog.Warnf("error do garbage collection for %+v, inuse: %v, expire: %v, err: %v", mgrKey, inUseSet[mgrKey], expireSet[mgrKey], err)
+				gcErrs = append(gcErrs, fmt.Errorf("error gc %s resources: %w", mgrKey.resType, err))
+				n.gcFailed(expireSet[mgrKey], err)
+				continue
+			}
+			if n.gcQuarantine != nil {
+				n.gcQuarantine.Succeeded(expireSet[mgrKey])
+			}
+			n.recordGCHistory(expireSet[mgrKey], expireOwner[mgrKey])
+		}
+	}
+	if len(gcErrs) == 0 {
+		func() {
+			for mgrKey, resMap := range expireSet {
+				if mgrKey.resType != types.ResourceTypeENIIP {
+					continue
+				}
+				for resID := range resMap {
+					// try clean ip rules
+					list := strings.SplitAfterN(resID, ".", 2)
+					if len(list) <= 1 {
+						serviceLog.Debugf("skip gc res id %s", resID)
+						continue
+					}
+					serviceLog.Debugf("checking ip %s", list[1])
+					_, addr, err := net.ParseCIDR(fmt.Sprintf("%s/32", list[1]))
+					if err != nil {
+						serviceLog.Errorf("failed parse ip %s", list[1])
 						return
 					}
-					for resID := range resMap {
-						// try clean ip rules
-						list := strings.SplitAfterN(resID, ".", 2)
-						if len(list) <= 1 {
-							serviceLog.Debugf("skip gc res id %s", resID)
-							continue
-						}
-						serviceLog.Debugf("checking ip %s", list[1])
-						_, addr, err := net.ParseCIDR(fmt.Sprintf("%s/32", list[1]))
-						if err != nil {
-							serviceLog.Errorf("failed parse ip %s", list[1])
-							return
-						}
-						// try clean all
-						err = link.DeleteIPRulesByIP(addr)
-						if err != nil {
-							serviceLog.Errorf("failed release ip rules %v", err)
-						}
-						err = link.DeleteRouteByIP(addr)
-						if err != nil {
-							serviceLog.Errorf("failed delete route %v", err)
-						}
+					// try clean all
+					err = link.DeleteIPRulesByIP(addr)
+					if err != nil {
+						serviceLog.Errorf("failed release ip rules %v", err)
 					}
-				}()
-
-				for _, relate := range relateExpireList {
-					err = n.resourceDB.Delete(relate)
+					err = link.DeleteRouteByIP(addr)
 					if err != nil {
-						serviceLog.Warnf("error delete resource db relation: %v", err)
+						serviceLog.Errorf("failed delete route %v", err)
 					}
 				}
 			}
-			n.Unlock()
+		}()
+
+		for _, relate := range relateExpireList {
+			if kept, ok := quarantinedRes[relate]; ok {
+				// keep the quarantined resources in db, they are lost on restart otherwise
+				err = n.resourceDB.Put(relate, kept)
+				if err != nil {
+					serviceLog.Warnf("error store quarantined resources to resource db: %v", err)
+				}
+				continue
+			}
+			err = n.resourceDB.Delete(relate)
+			if err != nil {
+				serviceLog.Warnf("error delete resource db relation: %v", err)
+				continue
+			}
+			n.containerIDMismatches.Lock()
+			delete(n.containerIDMismatches.counts, relate)
+			n.containerIDMismatches.Unlock()
 		}
-	}()
+	}
+	return reclaimed, utilerrors.NewAggregate(gcErrs)
+}
+
+// containerIDMismatches the consecutive count of cni requests not match the container id stored, keyed by pod
+type containerIDMismatches struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+// containerIDMismatched record the cni request ignored for the container id not match the stored one,
+// the pod mismatched repeatedly is reported by a warning event as the sandbox may be recreated in race
+func (n *networkService) containerIDMismatched(ctx *networkContext, api, expect, got string) {
+	ctx.Log().Warnf("cni request not macth stored resource, expect %s, got %s, ignored", expect, got)
+	metric.ContainerIDMismatchCount.WithLabelValues(api).Inc()
+	if n.containerIDMismatchThreshold <= 0 {
+		return
+	}
+
+	m := &n.containerIDMismatches
+	m.Lock()
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	key := podInfoKey(ctx.pod.Namespace, ctx.pod.Name)
+	m.counts[key]++
+	count := m.counts[key]
+	if count >= n.containerIDMismatchThreshold {
+		delete(m.counts, key)
+	}
+	m.Unlock()
+
+	if count < n.containerIDMismatchThreshold {
+		return
+	}
+	_ = tracing.RecordPodEvent(ctx.pod.Name, ctx.pod.Namespace, corev1.EventTypeWarning, "ContainerIDMismatch",
+		fmt.Sprintf("%d cni requests not match the container id %s stored, last %s by %s", count, expect, got, api))
+}
+
+// containerIDMatched reset the mismatch count of pod
+func (n *networkService) containerIDMatched(pod *types.PodInfo) {
+	n.containerIDMismatches.Lock()
+	defer n.containerIDMismatches.Unlock()
+	delete(n.containerIDMismatches.counts, podInfoKey(pod.Namespace, pod.Name))
+}
+
+// eipInStickTime return true if the eip of the deleted pod should be kept. the stick time starts from
+// the release of pod, or from the first gc after pod deleted if it is not released, which is set to the record
+func (n *networkService) eipInStickTime(podKey string, res *types.PodResources) bool {
+	if n.eipStickTime <= 0 || len(res.GetResourceItemByType(types.ResourceTypeEIP)) == 0 {
+		return false
+	}
+	if res.EIPStickUntil.IsZero() {
+		res.EIPStickUntil = time.Now().Add(n.eipStickTime)
+		serviceLog.Infof("keep eip of deleted pod %s until %s", podKey, res.EIPStickUntil.Format(time.RFC3339))
+	}
+	return time.Now().Before(res.EIPStickUntil)
+}
+
+// stickEIPResources return the pod resources only keep the eip and the eni or eniip it associated with,
+// so the private ip is not handed out to other pod while the eip is still associated
+func stickEIPResources(res types.PodResources) types.PodResources {
+	var eipRes []types.ResourceItem
+	for _, item := range res.Resources {
+		if item.Type == types.ResourceTypeEIP {
+			eipRes = append(eipRes, item)
+		}
+	}
+	var stickRes []types.ResourceItem
+	for _, item := range res.Resources {
+		if item.Type == types.ResourceTypeEIP {
+			stickRes = append(stickRes, item)
+			continue
+		}
+		for _, eip := range eipRes {
+			if eipAssociatedWith(eip, item) {
+				stickRes = append(stickRes, item)
+				break
+			}
+		}
+	}
+	res.Resources = stickRes
+	return res
+}
+
+// eipAssociatedWith return true if the eip is associated with the ip of the eni or eniip resource
+func eipAssociatedWith(eip, res types.ResourceItem) bool {
+	if eip.ExtraEipInfo == nil || eip.ExtraEipInfo.AssociateENIIP == nil {
+		return false
+	}
+	if res.Type != types.ResourceTypeENI && res.Type != types.ResourceTypeENIIP {
+		return false
+	}
+	return res.ENIID == eip.ExtraEipInfo.AssociateENI && res.IPv4 == eip.ExtraEipInfo.AssociateENIIP.String()
+}
+
+// resourceIDs return the ids of the resources
+func resourceIDs(res []types.ResourceItem) map[string]struct{} {
+	ids := make(map[string]struct{}, len(res))
+	for _, item := range res {
+		ids[item.ID] = struct{}{}
+	}
+	return ids
 }
 
 func (n *networkService) startPeriodCheck() {
@@ -918,10 +1948,21 @@ func (n *networkService) startPeriodCheck() {
 			serviceLog.Error(err)
 			return
 		}
+		invalid := map[string]int{
+			mismatchKindLocal:   0,
+			mismatchKindRemote:  0,
+			mismatchKindPodBind: 0,
+		}
+		defer func() {
+			for kind, count := range invalid {
+				metric.InvalidResourceMappingCount.WithLabelValues(kind).Set(float64(count))
+			}
+		}()
 		for _, res := range podMapping {
 			if res.Valid {
 				continue
 			}
+			invalid[mappingMismatchKind(res)]++
 			if res.Name == "" || res.Namespace == "" {
 				// just log
 				serviceLog.Warnf("found resource invalid %s %s", res.LocalResID, res.RemoteResID)
@@ -930,6 +1971,70 @@ func (n *networkService) startPeriodCheck() {
 			}
 		}
 	}()
+	// report ip exhaustion by node condition
+	func() {
+		if n.sufficientIPThreshold <= 0 {
+			return
+		}
+		counter, ok := n.poolResourceManager().(ResourceCounter)
+		if !ok {
+			return
+		}
+		free := counter.Free()
+		status, reason := corev1.ConditionTrue, "SufficientIP"
+		if free < n.sufficientIPThreshold {
+			status, reason = corev1.ConditionFalse, "InsufficientIP"
+		}
+		err := n.k8s.SetNodeCondition(types.NodeConditionSufficientIP, status, reason,
+			fmt.Sprintf("free ip count %d, threshold %d", free, n.sufficientIPThreshold))
+		if err != nil {
+			serviceLog.Errorf("error set node condition %s, %v", types.NodeConditionSufficientIP, err)
+		}
+	}()
+	n.checkENIQuota()
+	n.cleanOrphanRoutes()
+	// detach the trunk eni no trunk pod used for a while
+	func() {
+		mgr, ok := n.eniResMgr.(*eniResourceManager)
+		if !ok || mgr.trunkIdleDetach <= 0 {
+			return
+		}
+		pods, err := n.k8s.GetLocalPods()
+		if err != nil {
+			serviceLog.Errorf("error list local pods for trunk eni idle check, %v", err)
+			return
+		}
+		trunkPods := 0
+		for _, pod := range pods {
+			if pod.PodENI && !pod.HostNetwork {
+				trunkPods++
+			}
+		}
+		mgr.checkIdleTrunk(trunkPods)
+	}()
+	// check container id referenced by more than one pod, read only
+	func() {
+		n.RLock()
+		podResList, err := n.resourceDB.List()
+		n.RUnlock()
+		if err != nil {
+			serviceLog.Error(err)
+			return
+		}
+		duplicated := duplicateContainerIDs(podResList)
+		metric.DuplicateContainerIDCount.Set(float64(len(duplicated)))
+		for containerID, pods := range duplicated {
+			var keys []string
+			for _, pod := range pods {
+				keys = append(keys, podInfoKey(pod.Namespace, pod.Name))
+			}
+			msg := fmt.Sprintf("container id %s is referenced by pods %s", containerID, strings.Join(keys, ", "))
+			serviceLog.Warn(msg)
+			for _, pod := range pods {
+				_ = tracing.RecordPodEvent(pod.Name, pod.Namespace, corev1.EventTypeWarning, "DuplicateContainerID", msg)
+			}
+		}
+	}()
 	// call CNI CHECK, make sure all dev is ok
 	func() {
 		serviceLog.Debugf("call CNI CHECK")
@@ -948,14 +2053,19 @@ func (n *networkService) startPeriodCheck() {
 			serviceLog.Error(err)
 			return
 		}
+		checked, failed := 0, 0
+		defer func() {
+			n.reportCNICheck(checked, failed)
+		}()
 		for _, v := range podResList {
 			res := v.(types.PodResources)
 			if res.NetNs == nil {
 				continue
 			}
 			serviceLog.Debugf("checking pod name %s", res.PodInfo.Name)
+			checked++
 			cniCfg := libcni.NewCNIConfig([]string{n.cniBinPath}, nil)
-			netNs := filepath.Join("/proc/1/root/", *res.NetNs)
+			netNs := filepath.Join(n.hostNetNSPrefix, *res.NetNs)
 			if utils.IsWindowsOS() {
 				netNs = *res.NetNs
 			}
@@ -985,6 +2095,7 @@ func (n *networkService) startPeriodCheck() {
 					Args:        args,
 				})
 				if err != nil {
+					failed++
 					serviceLog.Error(err)
 					return
 				}
@@ -993,6 +2104,110 @@ func (n *networkService) startPeriodCheck() {
 	}()
 }
 
+// splitResourcesByTypes split the pod resources into the ones to release and the ones to keep by the resource types,
+// the eip is associated with the eni or eniip, so they can not be released without the eip
+func splitResourcesByTypes(res types.PodResources, resourceTypes []string) (release, keep []types.ResourceItem, err error) {
+	typeSet := sets.NewString(resourceTypes...)
+	for _, t := range typeSet.List() {
+		switch t {
+		case types.ResourceTypeVeth, types.ResourceTypeENI, types.ResourceTypeENIIP, types.ResourceTypeEIP:
+		default:
+			return nil, nil, fmt.Errorf("unknown resource type %s", t)
+		}
+	}
+	if typeSet.HasAny(types.ResourceTypeENI, types.ResourceTypeENIIP) && !typeSet.Has(types.ResourceTypeEIP) &&
+		len(res.GetResourceItemByType(types.ResourceTypeEIP)) > 0 {
+		return nil, nil, fmt.Errorf("eip of pod should be released together with %v", resourceTypes)
+	}
+	for _, item := range res.Resources {
+		if typeSet.Has(item.Type) {
+			release = append(release, item)
+		} else {
+			keep = append(keep, item)
+		}
+	}
+	return release, keep, nil
+}
+
+// releaseResourceTypes release the resources of the types for pod and keep the others in db,
+// the resources are released at once regardless of the ip stick time
+func (n *networkService) releaseResourceTypes(ctx *networkContext, oldRes types.PodResources, resourceTypes []string) error {
+	release, keep, err := splitResourcesByTypes(oldRes, resourceTypes)
+	if err != nil {
+		return err
+	}
+	for _, res := range release {
+		mgr := n.getResourceManagerForRes(res)
+		if mgr == nil {
+			ctx.Log().Warnf("no resource manager for network resource %s, %s", res.ID, res.Type)
+			continue
+		}
+		_, endReleaseSpan := startSpan(ctx, "Release", tracing.Attr("resource_type", res.Type))
+		err = mgr.Release(ctx, res)
+		endReleaseSpan(err)
+		if err != nil && err != pool.ErrInvalidState {
+			return err
+		}
+	}
+	if n.podHistory != nil && len(release) > 0 {
+		n.podHistory.Add(podInfoKey(ctx.pod.Namespace, ctx.pod.Name), podHistoryRelease, release)
+	}
+	if len(keep) == 0 {
+		return n.deletePodResource(ctx.pod)
+	}
+	oldRes.Resources = keep
+	return n.putPodResource(ctx, oldRes)
+}
+
+// cniCheckDegraded whether the failed ratio of cni check exceed the threshold
+func cniCheckDegraded(checked, failed int, ratio float64) bool {
+	if ratio <= 0 || checked == 0 {
+		return false
+	}
+	return float64(failed)/float64(checked) > ratio
+}
+
+// reportCNICheck set node condition PodNetworkHealthy by the cni check result of a cycle,
+// and record a node event when the pod network become degraded
+func (n *networkService) reportCNICheck(checked, failed int) {
+	if n.cniCheckFailureRatio <= 0 {
+		return
+	}
+	degraded := cniCheckDegraded(checked, failed, n.cniCheckFailureRatio)
+	msg := fmt.Sprintf("cni check failed %d of %d pods, threshold ratio %v", failed, checked, n.cniCheckFailureRatio)
+	status, reason := corev1.ConditionTrue, "CNICheckPassed"
+	if degraded {
+		status, reason = corev1.ConditionFalse, "CNICheckFailed"
+		if !n.cniCheckDegraded {
+			serviceLog.Warn(msg)
+			n.k8s.RecordNodeEvent(corev1.EventTypeWarning, "PodNetworkDegraded", msg)
+		}
+	}
+	n.cniCheckDegraded = degraded
+	err := n.k8s.SetNodeCondition(types.NodeConditionPodNetworkHealthy, status, reason, msg)
+	if err != nil {
+		serviceLog.Errorf("error set node condition %s, %v", types.NodeConditionPodNetworkHealthy, err)
+	}
+}
+
+// duplicateContainerIDs return the container ids referenced by more than one pod resources, and the pods
+func duplicateContainerIDs(podResList []interface{}) map[string][]*types.PodInfo {
+	pods := make(map[string][]*types.PodInfo)
+	for _, v := range podResList {
+		res := v.(types.PodResources)
+		if res.ContainerID == nil || *res.ContainerID == "" || res.PodInfo == nil {
+			continue
+		}
+		pods[*res.ContainerID] = append(pods[*res.ContainerID], res.PodInfo)
+	}
+	for containerID, infos := range pods {
+		if len(infos) < 2 {
+			delete(pods, containerID)
+		}
+	}
+	return pods
+}
+
 // requestCRD get crd from api
 // note: need tolerate crd is not exist, so contained can del pod normally
 func (n *networkService) requestCRD(podInfo *types.PodInfo, waitReady bool) (*podENITypes.PodENI, error) {
@@ -1057,17 +2272,26 @@ func (n *networkService) multiIPFromCRD(podInfo *types.PodInfo, waitReady bool)
 			}
 		}
 		eniInfo := &rpc.ENIInfo{
-			MAC:       nodeTrunkENI.MAC, // set trunk eni mac
-			Trunk:     true,
-			GatewayIP: nodeTrunkENI.GatewayIP.ToRPC(),
+			MAC:             nodeTrunkENI.MAC, // set trunk eni mac
+			Trunk:           true,
+			GatewayIP:       nodeTrunkENI.GatewayIP.ToRPC(),
+			DisableRPFilter: podInfo.DisableRPFilter,
 		}
 		info, ok := podEni.Status.ENIInfos[alloc.ENI.ID]
 		if !ok {
 			return nil, fmt.Errorf("error get podENI status")
 		}
 		vid := uint32(info.Vid)
+		if err = n.validateVlanID(vid); err != nil {
+			return nil, err
+		}
 		eniInfo.Vid = vid
 
+		extraRoutes := parseExtraRoute(alloc.ExtraRoutes, n.extraRouteTable, n.extraRoutePriority)
+		if err = n.checkExtraRoutes(podInfo, extraRoutes); err != nil {
+			return nil, err
+		}
+
 		netConf = append(netConf, &rpc.NetConf{
 			BasicInfo: &rpc.BasicInfo{
 				PodIP:       podIP,
@@ -1082,10 +2306,16 @@ func (n *networkService) multiIPFromCRD(podInfo *types.PodInfo, waitReady bool)
 				NetworkPriority: podInfo.NetworkPriority,
 			},
 			IfName:       alloc.Interface,
-			ExtraRoutes:  parseExtraRoute(alloc.ExtraRoutes),
+			ExtraRoutes:  extraRoutes,
 			DefaultRoute: alloc.DefaultRoute,
 		})
 	}
+	if n.validateGateways {
+		err = validateIPv6Gateway(netConf)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return netConf, nil
 }
@@ -1100,7 +2330,7 @@ func (n *networkService) exclusiveENIFromCRD(podInfo *types.PodInfo, waitReady b
 	}
 
 	if n.enableTrunk {
-		nodeTrunkENI = n.eniResMgr.(*eniResourceManager).trunkENI
+		nodeTrunkENI = n.eniResMgr.(*eniResourceManager).getTrunkENI()
 		if nodeTrunkENI == nil || nodeTrunkENI.ID != podEni.Status.TrunkENIID {
 			return nil, fmt.Errorf("pod status eni parent not match instance trunk eni")
 		}
@@ -1130,8 +2360,9 @@ func (n *networkService) exclusiveENIFromCRD(podInfo *types.PodInfo, waitReady b
 			}
 		}
 		eniInfo := &rpc.ENIInfo{
-			MAC:   alloc.ENI.MAC,
-			Trunk: false,
+			MAC:             alloc.ENI.MAC,
+			Trunk:           false,
+			DisableRPFilter: podInfo.DisableRPFilter,
 		}
 		if n.enableTrunk {
 			eniInfo.MAC = nodeTrunkENI.MAC // set trunk eni mac
@@ -1141,8 +2372,15 @@ func (n *networkService) exclusiveENIFromCRD(podInfo *types.PodInfo, waitReady b
 				return nil, fmt.Errorf("error get podENI status")
 			}
 			eniInfo.Vid = uint32(info.Vid)
+			if err = n.validateVlanID(eniInfo.Vid); err != nil {
+				return nil, err
+			}
 			eniInfo.GatewayIP = nodeTrunkENI.GatewayIP.ToRPC()
 		}
+		extraRoutes := parseExtraRoute(alloc.ExtraRoutes, n.extraRouteTable, n.extraRoutePriority)
+		if err = n.checkExtraRoutes(podInfo, extraRoutes); err != nil {
+			return nil, err
+		}
 		netConf = append(netConf, &rpc.NetConf{
 			BasicInfo: &rpc.BasicInfo{
 				PodIP:       podIP,
@@ -1157,7 +2395,7 @@ func (n *networkService) exclusiveENIFromCRD(podInfo *types.PodInfo, waitReady b
 				NetworkPriority: podInfo.NetworkPriority,
 			},
 			IfName:       alloc.Interface,
-			ExtraRoutes:  parseExtraRoute(alloc.ExtraRoutes),
+			ExtraRoutes:  extraRoutes,
 			DefaultRoute: alloc.DefaultRoute,
 		})
 	}
@@ -1165,9 +2403,23 @@ func (n *networkService) exclusiveENIFromCRD(podInfo *types.PodInfo, waitReady b
 	if err != nil {
 		return nil, err
 	}
+	if n.validateGateways {
+		err = validateIPv6Gateway(netConf)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return netConf, nil
 }
 
+// validateVlanID check the vlan id from controller is in the allowed range
+func (n *networkService) validateVlanID(vid uint32) error {
+	if vid < n.trunkVlanMin || vid > n.trunkVlanMax {
+		return fmt.Errorf("vlan id %d is out of range [%d, %d]", vid, n.trunkVlanMin, n.trunkVlanMax)
+	}
+	return nil
+}
+
 // tracing
 func (n *networkService) Config() []tracing.MapKeyValueEntry {
 	// name, daemon_mode, configFilePath, kubeconfig, master
@@ -1178,19 +2430,49 @@ func (n *networkService) Config() []tracing.MapKeyValueEntry {
 		{Key: tracingKeyKubeConfig, Value: n.kubeConfig},
 		{Key: tracingKeyMaster, Value: n.master},
 	}
+	if n.limit != nil {
+		config = append(config,
+			tracing.MapKeyValueEntry{Key: tracingKeyLimitMaxENI, Value: fmt.Sprint(n.limit.Adapters)},
+			tracing.MapKeyValueEntry{Key: tracingKeyLimitIPv4PerENI, Value: fmt.Sprint(n.limit.IPv4PerAdapter)},
+			tracing.MapKeyValueEntry{Key: tracingKeyLimitIPv6PerENI, Value: fmt.Sprint(n.limit.IPv6PerAdapter)},
+			tracing.MapKeyValueEntry{Key: tracingKeyLimitSupportIPv6, Value: fmt.Sprint(n.limit.SupportIPv6())},
+			tracing.MapKeyValueEntry{Key: tracingKeyLimitSupportENIIPIPv6, Value: fmt.Sprint(n.limit.SupportMultiIPIPv6())},
+		)
+	}
 
 	return config
 }
 
-func (n *networkService) Trace() []tracing.MapKeyValueEntry {
-	count := 0
-	n.pendingPods.Range(func(key, value interface{}) bool {
-		count++
-		return true
-	})
+// gcFailed count the failure on the resource failed gc, quarantine it if failed repeatedly
+func (n *networkService) gcFailed(expireSet map[string]types.ResourceItem, err error) {
+	var gcErr *resourceGCError
+	if n.gcQuarantine == nil || !errors.As(err, &gcErr) {
+		return
+	}
+	item, ok := expireSet[gcErr.resID]
+	if !ok {
+		return
+	}
+	quarantined, err := n.gcQuarantine.Failed(item, gcErr.err)
+	if err != nil {
+		serviceLog.Warnf("error quarantine resource %s: %v", item.ID, err)
+		return
+	}
+	if quarantined {
+		serviceLog.Warnf("resource %s failed gc repeatedly, quarantined until retried manually: %v", item.ID, gcErr.err)
+		n.k8s.RecordNodeEvent(corev1.EventTypeWarning, "ResourceQuarantined",
+			fmt.Sprintf("resource %s failed gc repeatedly, quarantined until retried manually: %v", item.ID, gcErr.err))
+	}
+}
 
+func (n *networkService) Trace() []tracing.MapKeyValueEntry {
 	trace := []tracing.MapKeyValueEntry{
-		{Key: tracingKeyPendingPodsCount, Value: fmt.Sprint(count)},
+		{Key: tracingKeyPendingPodsCount, Value: fmt.Sprint(n.pendingCount())},
+		{Key: tracingKeyMaxPendingPods, Value: fmt.Sprint(n.maxPendingPods)},
+		{Key: tracingKeyStartTime, Value: n.startTime.Format(time.RFC3339)},
+		{Key: tracingKeyUptime, Value: time.Since(n.startTime).Truncate(time.Second).String()},
+		{Key: tracingKeyRestartCount, Value: fmt.Sprint(n.restartCount)},
+		{Key: tracingKeyLogLevel, Value: logger.DefaultLogger.GetLevel().String()},
 	}
 	resList, err := n.resourceDB.List()
 	if err != nil {
@@ -1211,14 +2493,80 @@ func (n *networkService) Trace() []tracing.MapKeyValueEntry {
 		trace = append(trace, tracing.MapKeyValueEntry{Key: key, Value: strings.Join(resources, " ")})
 	}
 
+	if n.gcQuarantine != nil {
+		quarantined, err := n.gcQuarantine.List()
+		if err != nil {
+			trace = append(trace, tracing.MapKeyValueEntry{Key: "error", Value: err.Error()})
+			return trace
+		}
+		for _, res := range quarantined {
+			key := fmt.Sprintf(tracingKeyGCQuarantined, res.Item.ID)
+			trace = append(trace, tracing.MapKeyValueEntry{Key: key, Value: res.String()})
+		}
+	}
+
 	return trace
 }
 
-func (n *networkService) Execute(cmd string, _ []string, message chan<- string) {
+func (n *networkService) Execute(cmd string, args []string, message chan<- string) {
+	args, asJSON := jsonOutput(args)
 	switch cmd {
 	case commandMapping:
 		mapping, err := n.GetResourceMapping()
+		if asJSON {
+			out := struct {
+				Mapping []*tracing.PodMapping
+				Error   string `json:",omitempty"`
+			}{Mapping: mapping}
+			if err != nil {
+				out.Error = err.Error()
+			}
+			writeJSON(message, out)
+			break
+		}
 		message <- fmt.Sprintf("mapping: %v, err: %s\n", mapping, err)
+	case commandVerify:
+		n.verifyResource(context.Background(), message)
+	case commandFailures:
+		if n.allocFailures == nil {
+			if asJSON {
+				writeJSON(message, []allocFailure{})
+				break
+			}
+			message <- "failed allocations not recorded\n"
+			break
+		}
+		failures := n.allocFailures.List()
+		if asJSON {
+			writeJSON(message, failures)
+			break
+		}
+		for _, f := range failures {
+			message <- f.String() + "\n"
+		}
+		message <- fmt.Sprintf("%d failed allocations\n", len(failures))
+	case commandPending:
+		pods := n.listPendingPods()
+		if asJSON {
+			out := make([]map[string]interface{}, 0, len(pods))
+			for _, p := range pods {
+				out = append(out, map[string]interface{}{"Pod": p.key, "Since": p.start})
+			}
+			writeJSON(message, out)
+			break
+		}
+		for _, p := range pods {
+			message <- fmt.Sprintf("%s pending for %s, since %s\n", p.key, time.Since(p.start).Truncate(time.Millisecond), p.start.Format(time.RFC3339))
+		}
+		message <- fmt.Sprintf("%d pending pods\n", len(pods))
+	case commandRetryGC:
+		n.retryQuarantined(args, message)
+	case commandHistory:
+		if asJSON {
+			n.writePodHistoryJSON(args, message)
+			break
+		}
+		n.listPodHistory(args, message)
 	default:
 		message <- "can't recognize command\n"
 	}
@@ -1226,6 +2574,23 @@ func (n *networkService) Execute(cmd string, _ []string, message chan<- string)
 	close(message)
 }
 
+// jsonOutput strip the json arg from the end of args, return true if it is set
+func jsonOutput(args []string) ([]string, bool) {
+	if len(args) > 0 && args[len(args)-1] == commandArgJSON {
+		return args[:len(args)-1], true
+	}
+	return args, false
+}
+
+// writeJSON write v to message as a line of json
+func writeJSON(message chan<- string, v interface{}) {
+	out, err := json.Marshal(v)
+	if err != nil {
+		out, _ = json.Marshal(map[string]string{"Error": err.Error()})
+	}
+	message <- string(out) + "\n"
+}
+
 func (n *networkService) GetResourceMapping() ([]*tracing.PodMapping, error) {
 	var poolStats tracing.ResourcePoolStats
 	var err error
@@ -1255,6 +2620,30 @@ func (n *networkService) GetResourceMapping() ([]*tracing.PodMapping, error) {
 	return toResMapping(poolStats, pods)
 }
 
+// kinds of the invalid resource mapping
+const (
+	// mismatchKindLocal resource missing in pool
+	mismatchKindLocal = "local"
+	// mismatchKindRemote resource missing in ecs
+	mismatchKindRemote = "remote"
+	// mismatchKindPodBind resource bind to pod is unknown to both pool and ecs, or not agree with them
+	mismatchKindPodBind = "pod_bind"
+)
+
+// mappingMismatchKind return the kind of mismatch of the invalid resource mapping
+func mappingMismatchKind(res *tracing.PodMapping) string {
+	switch {
+	case res.LocalResID == "" && res.RemoteResID == "":
+		return mismatchKindPodBind
+	case res.LocalResID == "":
+		return mismatchKindLocal
+	case res.RemoteResID == "":
+		return mismatchKindRemote
+	default:
+		return mismatchKindPodBind
+	}
+}
+
 // toResMapping toResMapping
 func toResMapping(poolStats tracing.ResourcePoolStats, pods []interface{}) ([]*tracing.PodMapping, error) {
 	// three way compare, use resource id as key
@@ -1325,7 +2714,7 @@ func toResMapping(poolStats tracing.ResourcePoolStats, pods []interface{}) ([]*t
 	return mapping, nil
 }
 
-func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (rpc.TerwayBackendServer, error) {
+func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (*networkService, error) {
 	serviceLog.Debugf("start network service with: %s, %s", configFilePath, daemonMode)
 	cniBinPath := os.Getenv("CNI_PATH")
 	if cniBinPath == "" {
@@ -1337,6 +2726,7 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 		master:         master,
 		pendingPods:    sync.Map{},
 		cniBinPath:     utils.NormalizePath(cniBinPath),
+		startTime:      time.Now(),
 	}
 	if daemonMode == daemonModeENIMultiIP || daemonMode == daemonModeVPC || daemonMode == daemonModeENIOnly {
 		netSrv.daemonMode = daemonMode
@@ -1383,6 +2773,8 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 	if err := setDefault(config); err != nil {
 		return nil, err
 	}
+	netSrv.config = config
+	applyLogLevel(config.LogLevel)
 
 	netSrv.ipamType = config.IPAMType
 	netSrv.eniCapPolicy = config.ENICapPolicy
@@ -1396,6 +2788,21 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 		return nil, errors.Wrapf(err, "error create aliyun client")
 	}
 
+	if config.ENINamePrefix != "" {
+		aliyunClient.ENINamePrefix, aliyunClient.ENIDescription, err = eniNameAndDescription(config.ENINamePrefix, netSrv.k8s.GetNodeName(), config.ENITags[types.TagKeyClusterID])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.ResourceGroupID != "" {
+		err = aliyunClient.GetResourceGroup(config.ResourceGroupID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error check resource_group_id %s", config.ResourceGroupID)
+		}
+		aliyunClient.ResourceGroupID = config.ResourceGroupID
+	}
+
 	limit, err := aliyun.GetLimit(aliyunClient, ins.InstanceType)
 	if err != nil {
 		return nil, fmt.Errorf("upable get instance limit, %w", err)
@@ -1411,8 +2818,90 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 	}
 
 	ecs := aliyun.NewAliyunImpl(aliyunClient, config.EnableENITrunking && !config.WaitTrunkENI, ipFamily, config.ENITagFilter)
+	if config.VSwitchCacheTTLSeconds > 0 {
+		ecs = newVSwitchCachedAPI(ecs, time.Duration(config.VSwitchCacheTTLSeconds)*time.Second)
+	}
+	if config.EnableEIPPool == conditionTrue && config.EIPBandwidthPackageID != "" {
+		_, err = ecs.DescribeCommonBandwidthPackage(config.EIPBandwidthPackageID)
+		if err != nil {
+			return nil, fmt.Errorf("error get eip bandwidth package %s, %w", config.EIPBandwidthPackageID, err)
+		}
+	}
 
 	netSrv.enableTrunk = config.EnableENITrunking
+	netSrv.maxAllocLatency = time.Duration(config.MaxAllocLatencySeconds) * time.Second
+	netSrv.maxAllocLatencyByType = make(map[string]time.Duration, len(config.MaxAllocLatencySecondsByNetworkType))
+	for podNetworkType, seconds := range config.MaxAllocLatencySecondsByNetworkType {
+		netSrv.maxAllocLatencyByType[podNetworkType] = time.Duration(seconds) * time.Second
+	}
+	netSrv.sufficientIPThreshold = config.SufficientIPThreshold
+	netSrv.containerIDMismatchThreshold = config.ContainerIDMismatchEventThreshold
+	netSrv.podSubCIDRs, err = parsePodSubCIDRs(config.PodSubCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	netSrv.subCIDRAllocRetries = defaultSubCIDRAllocRetries
+	if config.SubCIDRAllocRetries > 0 {
+		netSrv.subCIDRAllocRetries = config.SubCIDRAllocRetries
+	}
+	netSrv.ipAffinitySingleENI = config.IPAffinitySingleENI
+	netSrv.validateGateways = config.ValidateGateways
+	netSrv.dns = config.DNS
+	netSrv.releaseAllOnShutdown = config.ReleaseAllOnShutdown
+	netSrv.ecs = ecs
+	netSrv.eniQuota = limit.Adapters
+	netSrv.eniQuotaAlertRatio = config.ENIQuotaAlertRatio
+	netSrv.limit = limit
+	netSrv.vSwitchCIDRs = newVSwitchCIDRCache(ecs)
+	if config.AllocQueueSize > 0 {
+		netSrv.allocQueue = newAllocQueue(config.AllocQueueSize)
+	}
+	netSrv.trunkVlanMin, netSrv.trunkVlanMax = defaultTrunkVlanMin, defaultTrunkVlanMax
+	if config.TrunkVlanMin > 0 {
+		netSrv.trunkVlanMin = uint32(config.TrunkVlanMin)
+	}
+	if config.TrunkVlanMax > 0 {
+		netSrv.trunkVlanMax = uint32(config.TrunkVlanMax)
+	}
+	netSrv.pendingPodTTL = defaultPendingPodTTL
+	if config.PendingPodTTLSeconds > 0 {
+		netSrv.pendingPodTTL = time.Duration(config.PendingPodTTLSeconds) * time.Second
+	}
+	netSrv.defaultNetworkPriority = config.DefaultNetworkPriority
+	netSrv.extraRouteTable = int32(config.ExtraRouteTable)
+	netSrv.extraRoutePriority = int32(config.ExtraRoutePriority)
+	netSrv.allowPartialDualStack = config.AllowPartialDualStack
+	netSrv.eipStickTime = time.Duration(config.EIPStickTimeSeconds) * time.Second
+	netSrv.maxIPStickDuration = time.Duration(config.MaxIPStickDurationSeconds) * time.Second
+	netSrv.maxSecondaryIPCount = config.MaxSecondaryIPCount
+	netSrv.validateExtraRoutes = config.ValidateExtraRoutes
+	netSrv.patchPodIPRetries = config.PatchPodIPRetries
+	netSrv.getPodRetries = config.GetPodRetries
+	netSrv.reserveIPsForCritical = config.ReserveIPsForCritical
+	netSrv.preferPreviousIP = config.PreferPreviousIP
+	netSrv.disableRPFilter = config.DisableRPFilter
+	netSrv.slowAllocThreshold = time.Duration(config.SlowAllocThresholdSeconds) * time.Second
+	netSrv.cniCheckFailureRatio = config.CNICheckFailureRatio
+	netSrv.maxPendingPods = config.MaxPendingPods
+	netSrv.allocFailures = newAllocFailureLog(defaultAllocFailureLogSize)
+	if config.AllocFailureLogSize > 0 {
+		netSrv.allocFailures = newAllocFailureLog(config.AllocFailureLogSize)
+	}
+	if config.PodHistoryRetentionSeconds > 0 {
+		netSrv.podHistory = newPodHistory(time.Duration(config.PodHistoryRetentionSeconds) * time.Second)
+	}
+	if config.AllocWebhookURL != "" {
+		netSrv.allocWebhook = newAllocWebhook(config.AllocWebhookURL)
+	}
+	netSrv.hostNetNSPrefix = defaultHostNetNSPrefix
+	if config.HostNetNSPrefix != "" {
+		netSrv.hostNetNSPrefix = config.HostNetNSPrefix
+	}
+	if !utils.IsWindowsOS() {
+		if _, err := os.Stat(netSrv.hostNetNSPrefix); err != nil {
+			return nil, fmt.Errorf("invalid host_netns_prefix %s: %w", netSrv.hostNetNSPrefix, err)
+		}
+	}
 
 	ipNetSet := &types.IPNetSet{}
 	if config.ServiceCIDR != "" {
@@ -1423,46 +2912,97 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 		}
 	}
 
-	err = netSrv.k8s.SetSvcCidr(ipNetSet)
+	err = retryStartupK8S("set service cidr", config.StartupK8SRetries, func() error {
+		return netSrv.k8s.SetSvcCidr(ipNetSet)
+	})
+	if err != nil {
+		if serviceCIDRRequired(daemonMode) && !config.AllowEmptyServiceCIDR {
+			return nil, errors.Wrapf(err, "error set k8s svcCidr")
+		}
+		serviceLog.Warnf("service cidr is not configured and auto detect failed, pod will not get service cidr: %v", err)
+	}
+
+	err = retryStartupK8S("set custom stateful workload kinds", config.StartupK8SRetries, func() error {
+		return netSrv.k8s.SetCustomStatefulWorkloadKinds(config.CustomStatefulWorkloadKinds)
+	})
 	if err != nil {
-		return nil, errors.Wrapf(err, "error set k8s svcCidr")
+		serviceLog.Warnf("error set custom stateful workload kinds: %v", err)
 	}
 
-	_ = netSrv.k8s.SetCustomStatefulWorkloadKinds(config.CustomStatefulWorkloadKinds)
+	if daemonMode == daemonModeVPC {
+		netSrv.vpcExtraRoutes, err = vpcExtraRoutes(config.GetExtraRouteDsts(), netSrv.k8s.GetNodeCidr(), netSrv.k8s.GetServiceCIDR())
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	netSrv.resourceDB, err = storage.NewDiskStorage(
-		resDBName, utils.NormalizePath(resDBPath), json.Marshal, func(bytes []byte) (interface{}, error) {
-			resourceRel := &types.PodResources{}
-			err = json.Unmarshal(bytes, resourceRel)
-			if err != nil {
-				return nil, errors.Wrapf(err, "error unmarshal pod relate resource")
-			}
-			return *resourceRel, nil
+	if config.ReadOnly {
+		// standby only serve the resources from the snapshot of db written by the active daemon, no pool is started
+		serviceLog.Infof("start as read only standby, gc and period check are disabled")
+		netSrv.readOnly = true
+		netSrv.resourceDB = storage.NewReadOnlyDiskStorage(resDBName, utils.NormalizePath(config.StandbySnapshotPath), unmarshalPodResources)
+		eniByMAC := aliyun.NewENIMetadata(netSrv.ipFamily).GetENIByMac
+		netSrv.vethResMgr = newStandbyResourceManager(types.ResourceTypeVeth, netSrv.resourceDB, eniByMAC)
+		netSrv.eniResMgr = newStandbyResourceManager(types.ResourceTypeENI, netSrv.resourceDB, eniByMAC)
+		netSrv.eniIPResMgr = newStandbyResourceManager(types.ResourceTypeENIIP, netSrv.resourceDB, eniByMAC)
+		netSrv.setResourceManagers(map[string]ResourceManager{
+			types.ResourceTypeVeth:  netSrv.vethResMgr,
+			types.ResourceTypeENI:   netSrv.eniResMgr,
+			types.ResourceTypeENIIP: netSrv.eniIPResMgr,
 		})
+		go wait.Until(netSrv.refreshStandbyDB, standbyRefreshPeriod, wait.NeverStop)
+
+		_ = tracing.Register(tracing.ResourceTypeNetworkService, "default", netSrv)
+		tracing.RegisterEventRecorder(netSrv.k8s.RecordNodeEvent, netSrv.k8s.RecordPodEvent)
+		return netSrv, nil
+	}
+
+	netSrv.resourceDB, err = storage.NewDiskStorage(
+		resDBName, utils.NormalizePath(resDBPath), marshalPodResources, unmarshalPodResources)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error init resource manager storage")
 	}
+	netSrv.restartCount = increaseRestartCount(utils.NormalizePath(restartCountPath))
+
+	if config.CleanOrphanRoutes {
+		netSrv.deleteOrphanRoutes = orphanRoutesDeleter()
+	}
+	netSrv.cleanupDatapath = cleanupResourceDatapath
+
+	if config.GCQuarantineThreshold > 0 {
+		quarantineDB, err := storage.NewDiskStorage(gcQuarantineDBName, utils.NormalizePath(gcQuarantineDBPath),
+			marshalQuarantinedResource, unmarshalQuarantinedResource)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error init gc quarantine storage")
+		}
+		netSrv.gcQuarantine = newGCQuarantine(config.GCQuarantineThreshold, quarantineDB)
+	}
 
 	// get pool config
-	poolConfig, err := getPoolConfig(config, config.IPAMType)
+	poolConfig, err := getPoolConfig(config, daemonMode, config.IPAMType, limit)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error get pool config")
 	}
 	serviceLog.Infof("init pool config: %+v", poolConfig)
+	netSrv.vSwitches = sets.NewString(poolConfig.VSwitch...).Insert(poolConfig.FallbackVSwitch...)
 
-	localResource := make(map[string]map[string]resourceManagerInitItem)
 	resObjList, err := netSrv.resourceDB.List()
 	if err != nil {
 		return nil, errors.Wrapf(err, "error list resource relation db")
 	}
-	for _, resObj := range resObjList {
-		podRes := resObj.(types.PodResources)
-		for _, res := range podRes.Resources {
-			if localResource[res.Type] == nil {
-				localResource[res.Type] = make(map[string]resourceManagerInitItem)
-			}
-			localResource[res.Type][res.ID] = resourceManagerInitItem{item: res, podInfo: podRes.PodInfo}
+	quarantinedList, err := listQuarantinedPodResources(netSrv.resourceDB)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error list quarantined resource relation db")
+	}
+	localResource := localResourcesOfPool(append(resObjList, quarantinedList...), types.DefaultPoolID)
+
+	if len(localResource[types.ResourceTypeENI]) > 0 || len(localResource[types.ResourceTypeENIIP]) > 0 {
+		// eni may be detached by ecs when node reboot, drop them from the restore set
+		attachedMACs, err := ecs.GetSecondaryENIMACs(context.Background())
+		if err != nil {
+			return nil, errors.Wrapf(err, "error get attached eni for verify local resource")
 		}
+		dropDetachedENIResource(localResource, attachedMACs)
 	}
 
 	resStr, err := json.Marshal(localResource)
@@ -1489,10 +3029,10 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 			return nil, errors.Wrapf(err, "error init vpc resource manager")
 		}
 
-		netSrv.mgrForResource = map[string]ResourceManager{
+		netSrv.setResourceManagers(map[string]ResourceManager{
 			types.ResourceTypeENI:  netSrv.eniResMgr,
 			types.ResourceTypeVeth: netSrv.vethResMgr,
-		}
+		})
 
 	case daemonModeENIMultiIP:
 		//init ENI multi ip
@@ -1501,12 +3041,12 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 			return nil, errors.Wrapf(err, "error init ENI ip resource manager")
 		}
 		if config.EnableEIPPool == conditionTrue {
-			netSrv.eipResMgr = newEipResourceManager(ecs, netSrv.k8s, config.AllowEIPRob == conditionTrue)
+			netSrv.eipResMgr = newEipResourceManager(ecs, netSrv.k8s, config.AllowEIPRob == conditionTrue, config.MaxEIPPoolSize, config.EIPBandwidthPackageID, localResource[types.ResourceTypeEIP])
 		}
-		netSrv.mgrForResource = map[string]ResourceManager{
+		netSrv.setResourceManagers(map[string]ResourceManager{
 			types.ResourceTypeENIIP: netSrv.eniIPResMgr,
 			types.ResourceTypeEIP:   netSrv.eipResMgr,
-		}
+		})
 	case daemonModeENIOnly:
 		//init eni
 		netSrv.eniResMgr, err = newENIResourceManager(poolConfig, ecs, localResource[types.ResourceTypeENI], ipFamily, netSrv.k8s)
@@ -1514,16 +3054,29 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 			return nil, errors.Wrapf(err, "error init eni resource manager")
 		}
 		if config.EnableEIPPool == conditionTrue && !config.EnableENITrunking {
-			netSrv.eipResMgr = newEipResourceManager(ecs, netSrv.k8s, config.AllowEIPRob == conditionTrue)
+			netSrv.eipResMgr = newEipResourceManager(ecs, netSrv.k8s, config.AllowEIPRob == conditionTrue, config.MaxEIPPoolSize, config.EIPBandwidthPackageID, localResource[types.ResourceTypeEIP])
 		}
-		netSrv.mgrForResource = map[string]ResourceManager{
+		netSrv.setResourceManagers(map[string]ResourceManager{
 			types.ResourceTypeENI: netSrv.eniResMgr,
 			types.ResourceTypeEIP: netSrv.eipResMgr,
-		}
+		})
 	default:
 		panic("unsupported daemon mode" + daemonMode)
 	}
 
+	err = netSrv.cleanMismatchedResource()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error clean resource allocated under previous daemon mode")
+	}
+
+	// reclaim resources of pods gone during daemon down, instead of waiting for the first gc period
+	reclaimed, err := netSrv.gc()
+	if err != nil {
+		serviceLog.Warnf("error do gc on startup, reclaimed %d: %v", reclaimed, err)
+	} else {
+		serviceLog.Infof("gc on startup reclaimed %d resources", reclaimed)
+	}
+
 	//start gc loop
 	netSrv.startGarbageCollectionLoop()
 	period := poolCheckPeriod
@@ -1534,6 +3087,11 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 	}
 
 	go wait.JitterUntil(netSrv.startPeriodCheck, period, 1, true, wait.NeverStop)
+	go wait.Until(netSrv.evictStuckPendingPods, pendingPodSweepPeriod, wait.NeverStop)
+	if config.StandbySnapshotPath != "" {
+		netSrv.standbySnapshotPath = utils.NormalizePath(config.StandbySnapshotPath)
+		go wait.Until(netSrv.snapshotResourceDB, standbyRefreshPeriod, wait.NeverStop)
+	}
 
 	// register for tracing
 	_ = tracing.Register(tracing.ResourceTypeNetworkService, "default", netSrv)
@@ -1543,11 +3101,88 @@ func newNetworkService(configFilePath, kubeconfig, master, daemonMode string) (r
 	return netSrv, nil
 }
 
+var eniNamePrefixRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9:_.-]*$`)
+
+// limits of ecs tag, the length counts in characters
+const (
+	tagKeyMaxLength   = 128
+	tagValueMaxLength = 128
+)
+
+// normalizeTags trim the spaces around keys and values of tags in place, and check them against the rules of ecs tag,
+// the tags violate the rules fail CreateNetworkInterface at allocation
+func normalizeTags(name string, tags map[string]string) error {
+	for key, value := range tags {
+		k, v := strings.TrimSpace(key), strings.TrimSpace(value)
+		if k != key || v != value {
+			serviceLog.Warnf("trim spaces of %s %q: %q to %q: %q", name, key, value, k, v)
+			delete(tags, key)
+			if _, ok := tags[k]; ok {
+				return fmt.Errorf("duplicated key %q in %s after trim spaces", k, name)
+			}
+			tags[k] = v
+		}
+	}
+	for key, value := range tags {
+		switch {
+		case key == "":
+			return fmt.Errorf("invalid %s, key is empty", name)
+		case utf8.RuneCountInString(key) > tagKeyMaxLength:
+			return fmt.Errorf("invalid key %q in %s, exceed %d characters", key, name, tagKeyMaxLength)
+		case strings.HasPrefix(key, "aliyun") || strings.HasPrefix(key, "acs:"):
+			return fmt.Errorf("invalid key %q in %s, should not start with aliyun or acs:", key, name)
+		case utf8.RuneCountInString(value) > tagValueMaxLength:
+			return fmt.Errorf("invalid value %q of %s in %s, exceed %d characters", value, key, name, tagValueMaxLength)
+		case strings.HasPrefix(value, "acs:"):
+			return fmt.Errorf("invalid value %q of %s in %s, should not start with acs:", value, key, name)
+		}
+		for _, str := range []string{key, value} {
+			if strings.Contains(str, "http://") || strings.Contains(str, "https://") {
+				return fmt.Errorf("invalid %q of %s in %s, should not contain http:// or https://", str, key, name)
+			}
+			if !utf8.ValidString(str) || strings.IndexFunc(str, unicode.IsControl) >= 0 {
+				return fmt.Errorf("invalid %q of %s in %s, contain invalid characters", str, key, name)
+			}
+		}
+	}
+	return nil
+}
+
+// eniNameAndDescription build the name prefix and description of eni created by the node,
+// so the eni can be identified in ecs console
+func eniNameAndDescription(prefix, nodeName, clusterID string) (string, string, error) {
+	parts := []string{prefix}
+	desc := "interface create by terway"
+	if clusterID != "" {
+		parts = append(parts, clusterID)
+		desc += ", cluster: " + clusterID
+	}
+	parts = append(parts, nodeName)
+	desc += ", node: " + nodeName
+
+	namePrefix := strings.Join(parts, "-") + "-"
+	if len(namePrefix)+client.ENINameSuffixLength > client.ENINameMaxLength {
+		return "", "", fmt.Errorf("eni name prefix %s is too long, max length is %d", namePrefix, client.ENINameMaxLength-client.ENINameSuffixLength)
+	}
+	if len(desc) > client.ENIDescriptionMaxLength {
+		return "", "", fmt.Errorf("eni description %s is too long, max length is %d", desc, client.ENIDescriptionMaxLength)
+	}
+	return namePrefix, desc, nil
+}
+
 // setup default value
 func setDefault(cfg *daemon.Config) error {
 	if cfg.EniCapRatio == 0 {
 		cfg.EniCapRatio = 1
 	}
+	if cfg.MaxEniCapRatio == 0 {
+		cfg.MaxEniCapRatio = defaultMaxEniCapRatio
+	}
+	// over-subscribe the eni cause allocation failures on attaching
+	if cfg.EniCapRatio > cfg.MaxEniCapRatio {
+		serviceLog.Warnf("eni_cap_ratio %v exceed max_eni_cap_ratio %v, clamp to %v", cfg.EniCapRatio, cfg.MaxEniCapRatio, cfg.MaxEniCapRatio)
+		cfg.EniCapRatio = cfg.MaxEniCapRatio
+	}
 
 	// Default policy for vswitch selection is random.
 	if cfg.VSwitchSelectionPolicy == "" {
@@ -1558,9 +3193,58 @@ func setDefault(cfg *daemon.Config) error {
 		cfg.IPStack = string(types.IPStackIPv4)
 	}
 
+	// terway create the trunk eni itself, keep one eni so the first trunk pod not wait for eni creation.
+	// if WaitTrunkENI the trunk eni is created by others
+	if cfg.EnableENITrunking && !cfg.WaitTrunkENI && cfg.MinENI < 1 {
+		serviceLog.Infof("eni trunking enabled, raise min_eni from %d to 1", cfg.MinENI)
+		cfg.MinENI = 1
+	}
+
 	return nil
 }
 
+// localResourcesOfPool group the resources of the pool in db by type for restoring the managers of the pool,
+// the resources of other pools are left out, they are never handed to the managers of the pool
+func localResourcesOfPool(resObjList []interface{}, poolID string) map[string]map[string]resourceManagerInitItem {
+	localResource := make(map[string]map[string]resourceManagerInitItem)
+	for _, resObj := range resObjList {
+		podRes := resObj.(types.PodResources)
+		for _, res := range podRes.Resources {
+			if res.GetPoolID() != poolID {
+				serviceLog.Warnf("skip restore resource %s %s of pool %s, not the pool %s", res.Type, res.ID, res.GetPoolID(), poolID)
+				continue
+			}
+			if localResource[res.Type] == nil {
+				localResource[res.Type] = make(map[string]resourceManagerInitItem)
+			}
+			localResource[res.Type][res.ID] = resourceManagerInitItem{item: res, podInfo: podRes.PodInfo}
+		}
+	}
+	return localResource
+}
+
+// dropDetachedENIResource remove eni and eniip resources whose eni is not attached to this instance
+func dropDetachedENIResource(localResource map[string]map[string]resourceManagerInitItem, attachedMACs []string) {
+	attached := make(map[string]struct{}, len(attachedMACs))
+	for _, mac := range attachedMACs {
+		attached[mac] = struct{}{}
+	}
+	for _, resType := range []string{types.ResourceTypeENI, types.ResourceTypeENIIP} {
+		for id, res := range localResource[resType] {
+			mac := res.item.ENIMAC
+			if mac == "" {
+				// compatible with the resource stored by old version, the id is mac or mac.ip
+				mac = strings.SplitN(id, ".", 2)[0]
+			}
+			if _, ok := attached[mac]; ok {
+				continue
+			}
+			serviceLog.Warnf("drop resource %s %s from restore, eni %s is not attached", resType, id, mac)
+			delete(localResource[resType], id)
+		}
+	}
+}
+
 func validateConfig(cfg *daemon.Config) error {
 	switch cfg.IPStack {
 	case "", string(types.IPStackIPv4), string(types.IPStackDual):
@@ -1568,10 +3252,415 @@ func validateConfig(cfg *daemon.Config) error {
 		return fmt.Errorf("unsupported ipStack %s in configMap", cfg.IPStack)
 	}
 
+	for _, cidr := range strings.Split(cfg.ServiceCIDR, ",") {
+		if cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid service_cidr %s", cfg.ServiceCIDR)
+		}
+	}
+
+	if cfg.LogLevel != "" {
+		if _, err := logrus.ParseLevel(cfg.LogLevel); err != nil {
+			return fmt.Errorf("invalid log_level %s", cfg.LogLevel)
+		}
+	}
+
+	if cfg.ENINamePrefix != "" && !eniNamePrefixRegexp.MatchString(cfg.ENINamePrefix) {
+		return fmt.Errorf("invalid eni_name_prefix %s, should start with letter and contain only letters, digits, ':', '_', '-' or '.'", cfg.ENINamePrefix)
+	}
+
+	if err := normalizeTags("eni_tags", cfg.ENITags); err != nil {
+		return err
+	}
+	if err := normalizeTags("eni_tag_filter", cfg.ENITagFilter); err != nil {
+		return err
+	}
+	for _, key := range cfg.PodLabelENITags {
+		if err := normalizeTags("pod_label_eni_tags", map[string]string{key: ""}); err != nil {
+			return err
+		}
+		_, static := cfg.ENITags[key]
+		if static || key == types.NetworkInterfaceTagCreatorKey || key == types.TagKubernetesPodNamespace || key == types.TagKubernetesPodName {
+			return fmt.Errorf("invalid key %q in pod_label_eni_tags, conflict with the other tags of eni", key)
+		}
+	}
+
+	if cfg.EniCapRatio < 0 {
+		return fmt.Errorf("invalid eni_cap_ratio %v, should be greater than 0", cfg.EniCapRatio)
+	}
+	if cfg.MaxEniCapRatio < 0 || (cfg.MaxEniCapRatio > 0 && cfg.MaxEniCapRatio < 1) {
+		return fmt.Errorf("invalid max_eni_cap_ratio %v, should not be less than 1", cfg.MaxEniCapRatio)
+	}
+
+	if cfg.SufficientIPThreshold < 0 {
+		return fmt.Errorf("invalid sufficient_ip_threshold %d", cfg.SufficientIPThreshold)
+	}
+
+	if cfg.MaxAllocLatencySeconds < 0 {
+		return fmt.Errorf("invalid max_alloc_latency_seconds %d", cfg.MaxAllocLatencySeconds)
+	}
+	for podNetworkType, seconds := range cfg.MaxAllocLatencySecondsByNetworkType {
+		switch podNetworkType {
+		case podNetworkTypeVPCIP, podNetworkTypeVPCENI, podNetworkTypeENIMultiIP:
+		default:
+			return fmt.Errorf("invalid pod network type %s in max_alloc_latency_seconds_by_network_type", podNetworkType)
+		}
+		if seconds < 0 {
+			return fmt.Errorf("invalid max_alloc_latency_seconds %d for %s", seconds, podNetworkType)
+		}
+	}
+
+	if cfg.CNICheckFailureRatio < 0 || cfg.CNICheckFailureRatio > 1 {
+		return fmt.Errorf("invalid cni_check_failure_ratio %v, should be in [0, 1]", cfg.CNICheckFailureRatio)
+	}
+
+	if cfg.VSwitchCacheTTLSeconds < 0 {
+		return fmt.Errorf("invalid vswitch_cache_ttl_seconds %d", cfg.VSwitchCacheTTLSeconds)
+	}
+
+	if cfg.MaxPendingPods < 0 {
+		return fmt.Errorf("invalid max_pending_pods %d", cfg.MaxPendingPods)
+	}
+
+	if cfg.SlowAllocThresholdSeconds < 0 {
+		return fmt.Errorf("invalid slow_alloc_threshold_seconds %d", cfg.SlowAllocThresholdSeconds)
+	}
+
+	if cfg.TrunkVlanMin < 0 || cfg.TrunkVlanMax < 0 || cfg.TrunkVlanMin > defaultTrunkVlanMax || cfg.TrunkVlanMax > defaultTrunkVlanMax ||
+		(cfg.TrunkVlanMax > 0 && cfg.TrunkVlanMin > cfg.TrunkVlanMax) {
+		return fmt.Errorf("invalid trunk vlan range [%d, %d]", cfg.TrunkVlanMin, cfg.TrunkVlanMax)
+	}
+
+	for _, ip := range cfg.ReservedIPs {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid reserved ip %s", ip)
+		}
+	}
+
+	if cfg.MaxEIPPoolSize < 0 {
+		return fmt.Errorf("invalid max_eip_pool_size %d", cfg.MaxEIPPoolSize)
+	}
+
+	if cfg.DefaultNetworkPriority != "" && !types.NetworkPrio(cfg.DefaultNetworkPriority).IsValid() {
+		return fmt.Errorf("invalid default_network_priority %s", cfg.DefaultNetworkPriority)
+	}
+
+	if cfg.ExtraRouteTable < 0 || cfg.ExtraRoutePriority < 0 {
+		return fmt.Errorf("invalid extra_route_table %d or extra_route_priority %d", cfg.ExtraRouteTable, cfg.ExtraRoutePriority)
+	}
+
+	if cfg.AllocWebhookURL != "" {
+		u, err := url.Parse(cfg.AllocWebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid alloc_webhook_url %s", cfg.AllocWebhookURL)
+		}
+	}
+
+	if cfg.EIPStickTimeSeconds < 0 {
+		return fmt.Errorf("invalid eip_stick_time_seconds %d", cfg.EIPStickTimeSeconds)
+	}
+
+	if cfg.MaxIPStickDurationSeconds < 0 {
+		return fmt.Errorf("invalid max_ip_stick_duration_seconds %d", cfg.MaxIPStickDurationSeconds)
+	}
+
+	if cfg.MaxSecondaryIPCount < 0 {
+		return fmt.Errorf("invalid max_secondary_ip_count %d", cfg.MaxSecondaryIPCount)
+	}
+
+	if cfg.PodHistoryRetentionSeconds < 0 {
+		return fmt.Errorf("invalid pod_history_retention_seconds %d", cfg.PodHistoryRetentionSeconds)
+	}
+
+	if cfg.AllocFailureLogSize < 0 {
+		return fmt.Errorf("invalid alloc_failure_log_size %d", cfg.AllocFailureLogSize)
+	}
+
+	if _, err := parsePodSubCIDRs(cfg.PodSubCIDRs); err != nil {
+		return err
+	}
+	for vSwitch, weight := range cfg.VSwitchWeights {
+		if weight <= 0 {
+			return fmt.Errorf("invalid vswitch_weights %d of %s", weight, vSwitch)
+		}
+	}
+	if cfg.ENIAttachTimeoutSeconds < 0 || cfg.ENIAttachPollIntervalMilliseconds < 0 {
+		return fmt.Errorf("invalid eni_attach_timeout_seconds %d, eni_attach_poll_interval_ms %d", cfg.ENIAttachTimeoutSeconds, cfg.ENIAttachPollIntervalMilliseconds)
+	}
+	if cfg.GCQuarantineThreshold < 0 {
+		return fmt.Errorf("invalid gc_quarantine_threshold %d", cfg.GCQuarantineThreshold)
+	}
+	if cfg.RestoreParallelism < 0 {
+		return fmt.Errorf("invalid restore_parallelism %d", cfg.RestoreParallelism)
+	}
+	if cfg.StartupK8SRetries < 0 {
+		return fmt.Errorf("invalid startup_k8s_retries %d", cfg.StartupK8SRetries)
+	}
+	if cfg.SubCIDRAllocRetries < 0 {
+		return fmt.Errorf("invalid sub_cidr_alloc_retries %d", cfg.SubCIDRAllocRetries)
+	}
+	if cfg.ContainerIDMismatchEventThreshold < 0 {
+		return fmt.Errorf("invalid container_id_mismatch_event_threshold %d", cfg.ContainerIDMismatchEventThreshold)
+	}
+	if cfg.ENIQuotaAlertRatio < 0 || cfg.ENIQuotaAlertRatio > 1 {
+		return fmt.Errorf("invalid eni_quota_alert_ratio %v, should be in [0, 1]", cfg.ENIQuotaAlertRatio)
+	}
+	if cfg.ENICreateRateLimit < 0 || cfg.ENICreateBurst < 0 {
+		return fmt.Errorf("invalid eni_create_rate_limit %v, eni_create_burst %d", cfg.ENICreateRateLimit, cfg.ENICreateBurst)
+	}
+	if cfg.TrunkENIIdleDetachSeconds < 0 {
+		return fmt.Errorf("invalid trunk_eni_idle_detach_seconds %d", cfg.TrunkENIIdleDetachSeconds)
+	}
+	// the trunk eni created by others should not be detached by daemon
+	if cfg.TrunkENIIdleDetachSeconds > 0 && cfg.WaitTrunkENI {
+		return fmt.Errorf("trunk_eni_idle_detach_seconds is not supported with wait_trunk_eni")
+	}
+
+	if cfg.ENIIdleRetainSeconds < 0 {
+		return fmt.Errorf("invalid eni_idle_retain_seconds %d", cfg.ENIIdleRetainSeconds)
+	}
+
+	if cfg.PatchPodIPRetries < 0 {
+		return fmt.Errorf("invalid patch_pod_ip_retries %d", cfg.PatchPodIPRetries)
+	}
+	if cfg.GetPodRetries < 0 {
+		return fmt.Errorf("invalid get_pod_retries %d", cfg.GetPodRetries)
+	}
+
+	if cfg.ReserveIPsForCritical < 0 {
+		return fmt.Errorf("invalid reserve_ips_for_critical %d", cfg.ReserveIPsForCritical)
+	}
+
+	if cfg.AllocQueueSize < 0 {
+		return fmt.Errorf("invalid alloc_queue_size %d", cfg.AllocQueueSize)
+	}
+
+	if cfg.PendingPodTTLSeconds < 0 {
+		return fmt.Errorf("invalid pending_pod_ttl_seconds %d", cfg.PendingPodTTLSeconds)
+	}
+
+	if cfg.CriticalKubeClientQPS < 0 || cfg.CriticalKubeClientBurst < 0 {
+		return fmt.Errorf("invalid critical_kube_client_qps %v or critical_kube_client_burst %d", cfg.CriticalKubeClientQPS, cfg.CriticalKubeClientBurst)
+	}
+
+	// the standby reads the snapshot file, the resource db is file locked by the active daemon
+	if cfg.ReadOnly && cfg.StandbySnapshotPath == "" {
+		return fmt.Errorf("standby_snapshot_path is required for read_only")
+	}
+
+	return nil
+}
+
+// verifyDualStack make sure both ip family allocated in dual stack, the reply is degraded to the allocated family
+// if partial dual stack is allowed
+func (n *networkService) verifyDualStack(podinfo *types.PodInfo, ipSet types.IPSet, reply *rpc.AllocIPReply) error {
+	if !n.ipFamily.IPv4 || !n.ipFamily.IPv6 {
+		return nil
+	}
+	if ipSet.IPv4 != nil && ipSet.IPv6 != nil {
+		return nil
+	}
+	if ipSet.IPv4 == nil && ipSet.IPv6 == nil {
+		return fmt.Errorf("no ip allocated")
+	}
+	if !n.allowPartialDualStack {
+		return fmt.Errorf("%w: %s", ErrPartialDualStack, ipSet.String())
+	}
+	_ = tracing.RecordPodEvent(podinfo.Name, podinfo.Namespace, corev1.EventTypeWarning, "PartialDualStack",
+		fmt.Sprintf("only %s allocated in dual stack, degrade to single stack", ipSet.String()))
+	reply.IPv4 = ipSet.IPv4 != nil
+	reply.IPv6 = ipSet.IPv6 != nil
+	return nil
+}
+
+// checkIPReserve reject the non critical pod if the free ips would fall below the reserve after count ips allocated
+func (n *networkService) checkIPReserve(podinfo *types.PodInfo, count int) error {
+	if n.reserveIPsForCritical <= 0 || podinfo.Critical {
+		return nil
+	}
+	counter, ok := n.eniIPResMgr.(ResourceCounter)
+	if !ok {
+		return nil
+	}
+	free := counter.Free()
+	if free-count < n.reserveIPsForCritical {
+		return errors.Wrapf(ErrIPReserved, "free ip count %d, reserve %d", free, n.reserveIPsForCritical)
+	}
+	return nil
+}
+
+// getPod get the pod with retries on the transient errors of apiserver in the deadline of ctx,
+// NotFound is returned without retry
+func (n *networkService) getPod(ctx context.Context, namespace, name string) (*types.PodInfo, error) {
+	bo := backoff.Backoff(backoff.GetPod)
+	if n.getPodRetries > 0 {
+		bo.Steps = n.getPodRetries + 1
+	}
+	var (
+		podinfo *types.PodInfo
+		lastErr error
+	)
+	err := wait.ExponentialBackoffWithContext(ctx, bo, func() (bool, error) {
+		podinfo, lastErr = n.k8s.GetPod(namespace, name)
+		if lastErr == nil {
+			return true, nil
+		}
+		if k8sErr.IsNotFound(lastErr) {
+			return false, lastErr
+		}
+		serviceLog.Warnf("error get pod %s: %v", podInfoKey(namespace, name), lastErr)
+		return false, nil
+	})
+	if err != nil {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, err
+	}
+	return podinfo, nil
+}
+
+// retryStartupK8S retry the k8s call on daemon startup with backoff, so a transient apiserver error
+// not abort the startup. the not found and auth errors are returned without retry
+func retryStartupK8S(name string, retries int, fn func() error) error {
+	bo := backoff.Backoff(backoff.StartupK8S)
+	if retries > 0 {
+		bo.Steps = retries + 1
+	}
+	var lastErr error
+	err := wait.ExponentialBackoff(bo, func() (bool, error) {
+		lastErr = fn()
+		if lastErr == nil {
+			return true, nil
+		}
+		if k8sErr.IsNotFound(lastErr) || k8sErr.IsForbidden(lastErr) || k8sErr.IsUnauthorized(lastErr) {
+			return false, lastErr
+		}
+		serviceLog.Warnf("error %s on startup, retry: %v", name, lastErr)
+		return false, nil
+	})
+	if err != nil && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+// patchPodIPInfo set the pod ip annotation with retries, a warning event is recorded if all retries failed
+func (n *networkService) patchPodIPInfo(podinfo *types.PodInfo, ips string) {
+	bo := backoff.Backoff(backoff.PatchPodIPInfo)
+	if n.patchPodIPRetries > 0 {
+		bo.Steps = n.patchPodIPRetries + 1
+	}
+	var lastErr error
+	err := wait.ExponentialBackoff(bo, func() (bool, error) {
+		lastErr = n.k8s.PatchPodIPInfo(podinfo, ips)
+		return lastErr == nil, nil
+	})
+	if err == nil {
+		return
+	}
+	serviceLog.Warnf("error patch ip info %s for pod %s: %v", ips, podInfoKey(podinfo.Namespace, podinfo.Name), lastErr)
+	_ = tracing.RecordPodEvent(podinfo.Name, podinfo.Namespace, corev1.EventTypeWarning, "PatchPodIPFailed",
+		fmt.Sprintf("failed to patch pod ip %s after %d attempts: %v", ips, bo.Steps, lastErr))
+}
+
+// checkExtraRoutes fail if the extra route overlaps the service cidr, which blackhole the service traffic
+func (n *networkService) checkExtraRoutes(podInfo *types.PodInfo, routes []*rpc.Route) error {
+	if !n.validateExtraRoutes {
+		return nil
+	}
+	serviceCIDR := n.k8s.GetServiceCIDR()
+	if serviceCIDR == nil {
+		return nil
+	}
+	for _, route := range routes {
+		_, dst, err := net.ParseCIDR(route.Dst)
+		if err != nil {
+			return fmt.Errorf("invalid extra route %s, %w", route.Dst, err)
+		}
+		for _, cidr := range []*net.IPNet{serviceCIDR.IPv4, serviceCIDR.IPv6} {
+			if cidr == nil || !(cidr.Contains(dst.IP) || dst.Contains(cidr.IP)) {
+				continue
+			}
+			msg := fmt.Sprintf("extra route %s overlaps service cidr %s", route.Dst, cidr)
+			_ = tracing.RecordPodEvent(podInfo.Name, podInfo.Namespace, corev1.EventTypeWarning, "ExtraRouteOverlap", msg)
+			return fmt.Errorf("%s", msg)
+		}
+	}
 	return nil
 }
 
-func getPoolConfig(cfg *daemon.Config, ipamType types.IPAMType) (*types.PoolConfig, error) {
+// vpcExtraRoutes build the routes of config for the pods of vpc ip, fail if a route overlaps the node cidr
+// or the service cidr, which breaks the traffic to the pods on node or to the services. the pod of vpc ip
+// is ipv4 only, so the ipv6 route is rejected
+func vpcExtraRoutes(dsts []string, nodeCIDR, serviceCIDR *types.IPNetSet) ([]*rpc.Route, error) {
+	var routes []*rpc.Route
+	for _, d := range dsts {
+		_, dst, err := net.ParseCIDR(d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extra route %s, %w", d, err)
+		}
+		if dst.IP.To4() == nil {
+			return nil, fmt.Errorf("extra route %s is ipv6, the pod of vpc ip is ipv4 only", d)
+		}
+		for name, set := range map[string]*types.IPNetSet{"node cidr": nodeCIDR, "service cidr": serviceCIDR} {
+			if set == nil {
+				continue
+			}
+			for _, cidr := range []*net.IPNet{set.IPv4, set.IPv6} {
+				if cidr != nil && (cidr.Contains(dst.IP) || dst.Contains(cidr.IP)) {
+					return nil, fmt.Errorf("extra route %s overlaps %s %s", d, name, cidr)
+				}
+			}
+		}
+		routes = append(routes, &rpc.Route{Dst: dst.String()})
+	}
+	return routes, nil
+}
+
+// podNetworkPriority return the network priority for pod, the default one is used if pod not specify
+// a valid one
+func (n *networkService) podNetworkPriority(podinfo *types.PodInfo) string {
+	prio := podinfo.NetworkPriority
+	if prio == "" {
+		return n.defaultNetworkPriority
+	}
+	if types.NetworkPrio(prio).IsValid() {
+		return prio
+	}
+	return n.defaultNetworkPriority
+}
+
+// clampPodNetworkPriority is podNetworkPriority which records an event on pod when the invalid priority is clamped,
+// it is called on allocation only, so the event is not repeated by every GetIPInfo
+func (n *networkService) clampPodNetworkPriority(podinfo *types.PodInfo) string {
+	prio := n.podNetworkPriority(podinfo)
+	if podinfo.NetworkPriority != "" && prio != podinfo.NetworkPriority {
+		_ = tracing.RecordPodEvent(podinfo.Name, podinfo.Namespace, corev1.EventTypeWarning, "NetworkPriorityClamped",
+			fmt.Sprintf("invalid network priority %q, use %q instead", podinfo.NetworkPriority, prio))
+	}
+	return prio
+}
+
+// crossZoneVSwitches return the vswitches not in the zone, sorted by zone
+func crossZoneVSwitches(vSwitches map[string][]string, zone string) []string {
+	zones := make([]string, 0, len(vSwitches))
+	for z := range vSwitches {
+		if z != zone {
+			zones = append(zones, z)
+		}
+	}
+	sort.Strings(zones)
+	var result []string
+	for _, z := range zones {
+		result = append(result, vSwitches[z]...)
+	}
+	return result
+}
+
+func getPoolConfig(cfg *daemon.Config, daemonMode string, ipamType types.IPAMType, limit *aliyun.Limits) (*types.PoolConfig, error) {
 	poolConfig := &types.PoolConfig{
 		MaxPoolSize:               cfg.MaxPoolSize,
 		MinPoolSize:               cfg.MinPoolSize,
@@ -1588,6 +3677,18 @@ func getPoolConfig(cfg *daemon.Config, ipamType types.IPAMType) (*types.PoolConf
 		DisableDevicePlugin:       cfg.DisableDevicePlugin,
 		WaitTrunkENI:              cfg.WaitTrunkENI,
 		DisableSecurityGroupCheck: cfg.DisableSecurityGroupCheck,
+		EnablePrefixDelegation:    cfg.EnablePrefixDelegation,
+		ReservedIPs:               cfg.ReservedIPs,
+		ENIIdleRetain:             time.Duration(cfg.ENIIdleRetainSeconds) * time.Second,
+		TrunkENIIdleDetach:        time.Duration(cfg.TrunkENIIdleDetachSeconds) * time.Second,
+		TagENIWithPod:             cfg.TagENIWithPod,
+		ENICreateQPS:              cfg.ENICreateRateLimit,
+		ENICreateBurst:            cfg.ENICreateBurst,
+		RestoreParallelism:        cfg.RestoreParallelism,
+		ENIAttachTimeout:          time.Duration(cfg.ENIAttachTimeoutSeconds) * time.Second,
+		ENIAttachPollInterval:     time.Duration(cfg.ENIAttachPollIntervalMilliseconds) * time.Millisecond,
+		VSwitchWeights:            cfg.VSwitchWeights,
+		PodLabelENITags:           cfg.PodLabelENITags,
 	}
 	if len(poolConfig.SecurityGroups) > 5 {
 		return nil, fmt.Errorf("security groups should not be more than 5, current %d", len(poolConfig.SecurityGroups))
@@ -1603,10 +3704,21 @@ func getPoolConfig(cfg *daemon.Config, ipamType types.IPAMType) (*types.PoolConf
 	if len(poolConfig.VSwitch) == 0 {
 		poolConfig.VSwitch = []string{ins.VSwitchID}
 	}
+	if cfg.AllowCrossZoneFallback {
+		poolConfig.FallbackVSwitch = crossZoneVSwitches(cfg.VSwitches, zone)
+	}
+	poolConfig.Zone = zone
 	poolConfig.ENITags = cfg.ENITags
 	poolConfig.VPC = ins.VPCID
 	poolConfig.InstanceID = ins.InstanceID
 
+	// ignore the static value, size the pool by the instance type
+	if cfg.AutoSizePool && limit != nil {
+		poolConfig.MaxENI, poolConfig.MaxPoolSize = autoSizePool(cfg, daemonMode, limit)
+		serviceLog.Infof("auto size pool for instance type %s, max_eni: %d, max_pool_size: %d",
+			ins.InstanceType, poolConfig.MaxENI, poolConfig.MaxPoolSize)
+	}
+
 	if ipamType == types.IPAMTypeCRD {
 		poolConfig.MaxPoolSize = 0
 		poolConfig.MinPoolSize = 0
@@ -1616,15 +3728,46 @@ func getPoolConfig(cfg *daemon.Config, ipamType types.IPAMType) (*types.PoolConf
 	return poolConfig, nil
 }
 
-func parseExtraRoute(routes []podENITypes.Route) []*rpc.Route {
+// autoSizePool return the max eni and max pool size by the instance type, each eni is one resource
+// in ENIOnly mode, and the primary ip of eni is not counted into the pool in ENIMultiIP mode
+func autoSizePool(cfg *daemon.Config, daemonMode string, limit *aliyun.Limits) (int, int) {
+	maxENI := int(float64(limit.Adapters)*cfg.EniCapRatio) + cfg.EniCapShift - 1
+	if maxENI < 0 {
+		maxENI = 0
+	}
+	switch daemonMode {
+	case daemonModeENIOnly:
+		return maxENI, maxENI
+	case daemonModeENIMultiIP:
+		ipPerENI := limit.IPv4PerAdapter - 1
+		if ipPerENI < 0 {
+			ipPerENI = 0
+		}
+		return maxENI, maxENI * ipPerENI
+	}
+	return maxENI, cfg.MaxPoolSize
+}
+
+// parseExtraRoute convert the extra routes of crd, table and priority of policy route not set are filled with defaults
+func parseExtraRoute(routes []podENITypes.Route, defaultTable, defaultPriority int32) []*rpc.Route {
 	if routes == nil {
 		return nil
 	}
 	var res []*rpc.Route
 	for _, r := range routes {
-		res = append(res, &rpc.Route{
-			Dst: r.Dst,
-		})
+		route := &rpc.Route{
+			Dst:      r.Dst,
+			Src:      r.Src,
+			Table:    r.Table,
+			Priority: r.Priority,
+		}
+		if route.Table == 0 {
+			route.Table = defaultTable
+		}
+		if route.Table != 0 && route.Priority == 0 {
+			route.Priority = defaultPriority
+		}
+		res = append(res, route)
 	}
 	return res
 }
@@ -1664,6 +3807,86 @@ func defaultForNetConf(netConf []*rpc.NetConf) error {
 	return nil
 }
 
+// validateIPv6Gateway check the ipv6 gateway is a valid address in the pod cidr
+func validateIPv6Gateway(netConf []*rpc.NetConf) error {
+	for _, conf := range netConf {
+		if conf.BasicInfo == nil || conf.BasicInfo.PodIP == nil || conf.BasicInfo.PodIP.IPv6 == "" {
+			continue
+		}
+		var cidr, gw string
+		if conf.BasicInfo.PodCIDR != nil {
+			cidr = conf.BasicInfo.PodCIDR.IPv6
+		}
+		if conf.BasicInfo.GatewayIP != nil {
+			gw = conf.BasicInfo.GatewayIP.IPv6
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid ipv6 pod cidr %q for pod ip %s", cidr, conf.BasicInfo.PodIP.IPv6)
+		}
+		gwIP := net.ParseIP(gw)
+		if gwIP == nil || gwIP.To4() != nil {
+			return fmt.Errorf("invalid ipv6 gateway %q for pod ip %s", gw, conf.BasicInfo.PodIP.IPv6)
+		}
+		if gwIP.IsLoopback() || gwIP.IsUnspecified() {
+			return fmt.Errorf("ipv6 gateway %s is not routable", gw)
+		}
+		if !ipNet.Contains(gwIP) {
+			return fmt.Errorf("ipv6 gateway %s is not in pod cidr %s", gw, cidr)
+		}
+	}
+	return nil
+}
+
+// rollbackReason return a coarse category of the AllocIP error
+func rollbackReason(err error) string {
+	switch {
+	case errors.Is(err, ErrThrottled):
+		return "throttled"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled), errors.Is(err, pool.ErrContextDone):
+		return "timeout"
+	case errors.Is(err, pool.ErrNoAvailableResource):
+		return "no_available_resource"
+	case errors.Is(err, ErrAllocRejected):
+		return "webhook_rejected"
+	case errors.Is(err, ErrPartialDualStack):
+		return "partial_dual_stack"
+	case errors.Is(err, ErrIPReserved):
+		return "ip_reserved"
+	case strings.Contains(err.Error(), apiErr.InvalidVSwitchIDIPNotEnough):
+		return "vswitch_ip_not_enough"
+	case strings.Contains(err.Error(), apiErr.ErrThrottling):
+		return "openapi_throttling"
+	default:
+		return "other"
+	}
+}
+
+// mergeDNS override the default dns config with the pod's, return nil if both not set
+func mergeDNS(def, pod *types.DNSConfig) *rpc.DNS {
+	if def == nil && pod == nil {
+		return nil
+	}
+	dns := &rpc.DNS{}
+	if def != nil {
+		dns.Nameservers = def.Nameservers
+		dns.Search = def.Search
+		dns.Options = def.Options
+	}
+	if pod != nil {
+		if len(pod.Nameservers) > 0 {
+			dns.Nameservers = pod.Nameservers
+		}
+		if len(pod.Search) > 0 {
+			dns.Search = pod.Search
+		}
+		if len(pod.Options) > 0 {
+			dns.Options = pod.Options
+		}
+	}
+	return dns
+}
+
 func defaultIf(name string) bool {
 	if name == "" || name == IfEth0 {
 		return true
@@ -0,0 +1,144 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/AliyunContainerService/terway/pkg/link"
+	"github.com/AliyunContainerService/terway/pkg/pool"
+	"github.com/AliyunContainerService/terway/pkg/utils"
+	"github.com/AliyunContainerService/terway/types"
+)
+
+// serviceCIDRRequired return true if the datapath of the daemon mode can not work without the service cidr,
+// the ipvlan datapath of eni multi ip and the windows datapaths route the service cidr explicitly
+func serviceCIDRRequired(daemonMode string) bool {
+	return daemonMode == daemonModeENIMultiIP || utils.IsWindowsOS()
+}
+
+// unsupportedNetworkTypeMsg return the message explain why the pod network type is not supported
+func unsupportedNetworkTypeMsg(podNetworkType, daemonMode string) string {
+	return fmt.Sprintf("pod network type %q is not supported in daemon mode %s, "+
+		"check the network type annotations of the pod and the eni config of terway", podNetworkType, daemonMode)
+}
+
+// cleanMismatchedResource release the resource records allocated under a previous daemon mode.
+// Only the records of pods not exist on the node are released. The resource is released by the
+// resource manager of the type if the daemon mode has one, otherwise the datapath left on node is
+// cleaned up, the record is kept for the next start if any of the resources fails.
+func (n *networkService) cleanMismatchedResource() error {
+	resObjList, err := n.resourceDB.List()
+	if err != nil {
+		return err
+	}
+	var mismatched []types.PodResources
+	for _, resObj := range resObjList {
+		podRes := resObj.(types.PodResources)
+		if podRes.PodInfo == nil || n.verifyPodNetworkType(podRes.PodInfo.PodNetworkType) {
+			continue
+		}
+		mismatched = append(mismatched, podRes)
+	}
+	if len(mismatched) == 0 {
+		return nil
+	}
+
+	pods, err := n.k8s.GetLocalPods()
+	if err != nil {
+		return err
+	}
+	podKeyMap := make(map[string]bool)
+	for _, pod := range pods {
+		if !pod.SandboxExited {
+			podKeyMap[podInfoKey(pod.Namespace, pod.Name)] = true
+		}
+	}
+
+	for _, podRes := range mismatched {
+		key := podInfoKey(podRes.PodInfo.Namespace, podRes.PodInfo.Name)
+		if podKeyMap[key] {
+			serviceLog.Warnf("pod %s is allocated with network type %s under previous daemon mode, recreate the pod to migrate to daemon mode %s",
+				key, podRes.PodInfo.PodNetworkType, n.daemonMode)
+			continue
+		}
+		serviceLog.Infof("daemon mode changed to %s, release pod %s resource allocated with network type %s: %+v",
+			n.daemonMode, key, podRes.PodInfo.PodNetworkType, podRes.Resources)
+		netCtx := &networkContext{
+			Context:    context.Background(),
+			resources:  podRes.Resources,
+			pod:        podRes.PodInfo,
+			k8sService: n.k8s,
+		}
+		released := true
+		for _, res := range podRes.Resources {
+			err = n.releaseMismatchedResource(netCtx, res)
+			if err != nil {
+				serviceLog.Warnf("error release pod %s resource %s %s allocated under previous daemon mode, retry on next start: %v",
+					key, res.Type, res.ID, err)
+				released = false
+			}
+		}
+		if !released {
+			continue
+		}
+		err = n.resourceDB.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseMismatchedResource release the resource by the resource manager of the type, the resource type
+// of previous daemon mode has no manager, only the datapath of it on node is cleaned up
+func (n *networkService) releaseMismatchedResource(netCtx *networkContext, res types.ResourceItem) error {
+	if mgr := n.getResourceManagerForRes(res); mgr != nil {
+		err := mgr.Release(netCtx, res)
+		if err != nil && err != pool.ErrInvalidState {
+			return err
+		}
+		return nil
+	}
+	if n.cleanupDatapath == nil {
+		return nil
+	}
+	serviceLog.Infof("no resource manager of %s in daemon mode %s, cleanup the datapath of %s on node", res.Type, n.daemonMode, res.ID)
+	err := n.cleanupDatapath(res)
+	if errors.Is(err, link.ErrUnsupported) {
+		// nothing could be cleaned up on the platform, retry never succeeds
+		serviceLog.Warnf("cleanup the datapath of %s is not supported, skip it: %v", res.ID, err)
+		return nil
+	}
+	return err
+}
+
+// cleanupResourceDatapath delete the host veth of the veth resource, and the ip rules and routes of the pod ip of
+// the eni and eniip resource. The eni and eniip themselves are left to the pool of the current daemon mode
+func cleanupResourceDatapath(res types.ResourceItem) error {
+	switch res.Type {
+	case types.ResourceTypeVeth:
+		return link.DeleteLinkByName(res.ID)
+	case types.ResourceTypeENI, types.ResourceTypeENIIP:
+		for _, addr := range []string{res.IPv4, res.IPv6} {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				continue
+			}
+			ipNet := &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+			if ip.To4() != nil {
+				ipNet = &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}
+			}
+			err := link.DeleteIPRulesByIP(ipNet)
+			if err != nil {
+				return err
+			}
+			err = link.DeleteRouteByIP(ipNet)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
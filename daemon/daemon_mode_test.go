@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/link"
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/pkg/utils"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_serviceCIDRRequired(t *testing.T) {
+	assert.True(t, serviceCIDRRequired(daemonModeENIMultiIP))
+	assert.Equal(t, utils.IsWindowsOS(), serviceCIDRRequired(daemonModeVPC))
+	assert.Equal(t, utils.IsWindowsOS(), serviceCIDRRequired(daemonModeENIOnly))
+}
+
+func Test_unsupportedNetworkTypeMsg(t *testing.T) {
+	msg := unsupportedNetworkTypeMsg("Foo", daemonModeVPC)
+	assert.Contains(t, msg, `"Foo"`)
+	assert.Contains(t, msg, daemonModeVPC)
+}
+
+type releaseRecordManager struct {
+	ResourceManager
+	released []string
+}
+
+func (m *releaseRecordManager) Release(context *networkContext, resItem types.ResourceItem) error {
+	m.released = append(m.released, resItem.ID)
+	return nil
+}
+
+func Test_cleanMismatchedResource(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	for _, podRes := range []types.PodResources{
+		{PodInfo: &types.PodInfo{Namespace: "default", Name: "eniip", PodNetworkType: podNetworkTypeENIMultiIP},
+			Resources: []types.ResourceItem{{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1", IPv4: "192.168.0.1"}}},
+		{PodInfo: &types.PodInfo{Namespace: "default", Name: "vpc-running", PodNetworkType: podNetworkTypeVPCIP},
+			Resources: []types.ResourceItem{{Type: types.ResourceTypeVeth, ID: "cali-running"}}},
+		{PodInfo: &types.PodInfo{Namespace: "default", Name: "vpc-deleted", PodNetworkType: podNetworkTypeVPCIP},
+			Resources: []types.ResourceItem{{Type: types.ResourceTypeVeth, ID: "cali-deleted"}}},
+		{PodInfo: &types.PodInfo{Namespace: "default", Name: "eni-deleted", PodNetworkType: podNetworkTypeVPCENI},
+			Resources: []types.ResourceItem{
+				{Type: types.ResourceTypeENI, ID: "mac-2", IPv4: "192.168.0.2"},
+				{Type: types.ResourceTypeEIP, ID: "eip-1"},
+			}},
+		{PodInfo: &types.PodInfo{Namespace: "default", Name: "cleanup-failed", PodNetworkType: podNetworkTypeVPCIP},
+			Resources: []types.ResourceItem{{Type: types.ResourceTypeVeth, ID: "cali-failed"}}},
+		{PodInfo: &types.PodInfo{Namespace: "default", Name: "cleanup-unsupported", PodNetworkType: podNetworkTypeVPCIP},
+			Resources: []types.ResourceItem{{Type: types.ResourceTypeVeth, ID: "cali-unsupported"}}},
+	} {
+		assert.NoError(t, db.Put(podInfoKey(podRes.PodInfo.Namespace, podRes.PodInfo.Name), podRes))
+	}
+	eniIPMgr, eipMgr := &releaseRecordManager{}, &releaseRecordManager{}
+	var cleaned []string
+	n := &networkService{
+		daemonMode: daemonModeENIMultiIP,
+		resourceDB: db,
+		k8s: &fakeK8s{pods: []*types.PodInfo{
+			{Namespace: "default", Name: "eniip"},
+			{Namespace: "default", Name: "vpc-running"},
+		}},
+		cleanupDatapath: func(res types.ResourceItem) error {
+			if res.ID == "cali-failed" {
+				return fmt.Errorf("cleanup failed")
+			}
+			if res.ID == "cali-unsupported" {
+				return fmt.Errorf("delete link %s, %w", res.ID, link.ErrUnsupported)
+			}
+			cleaned = append(cleaned, res.ID)
+			return nil
+		},
+	}
+	n.setResourceManagers(map[string]ResourceManager{
+		types.ResourceTypeENIIP: eniIPMgr,
+		types.ResourceTypeEIP:   eipMgr,
+	})
+	assert.NoError(t, n.cleanMismatchedResource())
+
+	// the resource of previous daemon mode without manager is cleaned up on node, others released by the manager
+	assert.ElementsMatch(t, []string{"cali-deleted", "mac-2"}, cleaned)
+	assert.Equal(t, []string{"eip-1"}, eipMgr.released)
+	assert.Empty(t, eniIPMgr.released)
+
+	for name, kept := range map[string]bool{
+		"eniip":               true,
+		"vpc-running":         true,
+		"vpc-deleted":         false,
+		"eni-deleted":         false,
+		"cleanup-failed":      true,
+		"cleanup-unsupported": false,
+	} {
+		_, err := db.Get(podInfoKey("default", name))
+		if kept {
+			assert.NoError(t, err, name)
+		} else {
+			assert.ErrorIs(t, err, storage.ErrNotFound, name)
+		}
+	}
+}
@@ -1,11 +1,31 @@
 package daemon
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/AliyunContainerService/terway/pkg/aliyun"
+	apiErr "github.com/AliyunContainerService/terway/pkg/aliyun/client/errors"
+	podENITypes "github.com/AliyunContainerService/terway/pkg/apis/network.alibabacloud.com/v1beta1"
+	"github.com/AliyunContainerService/terway/pkg/logger"
+	"github.com/AliyunContainerService/terway/pkg/storage"
 	"github.com/AliyunContainerService/terway/pkg/tracing"
+	"github.com/AliyunContainerService/terway/rpc"
 	"github.com/AliyunContainerService/terway/types"
+	"github.com/AliyunContainerService/terway/types/daemon"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	k8sErr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 )
 
 func Test_toResMapping(t *testing.T) {
@@ -89,3 +109,1024 @@ func Test_toResMapping(t *testing.T) {
 		}
 	}
 }
+
+func Test_allocContext(t *testing.T) {
+	n := &networkService{}
+	ctx, cancel := n.allocContext(context.Background(), podNetworkTypeENIMultiIP)
+	defer cancel()
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+
+	n.maxAllocLatency = time.Second
+	ctx, cancel = n.allocContext(context.Background(), podNetworkTypeENIMultiIP)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.True(t, time.Until(deadline) <= time.Second)
+
+	parent, parentCancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer parentCancel()
+	ctx, cancel = n.allocContext(parent, podNetworkTypeENIMultiIP)
+	defer cancel()
+	assert.Equal(t, parent, ctx)
+}
+
+func Test_throttledErr(t *testing.T) {
+	n := &networkService{maxAllocLatency: time.Millisecond}
+	ctx, cancel := n.allocContext(context.Background(), podNetworkTypeENIMultiIP)
+	defer cancel()
+	<-ctx.Done()
+
+	err := n.throttledErr(ctx, errors.New("foo"))
+	assert.True(t, errors.Is(err, ErrThrottled))
+
+	// the grpc deadline fired before the derived one
+	n.maxAllocLatency = time.Minute
+	parent, parentCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer parentCancel()
+	ctx, cancel = n.allocContext(parent, podNetworkTypeENIMultiIP)
+	defer cancel()
+	<-ctx.Done()
+	err = n.throttledErr(ctx, errors.New("foo"))
+	assert.False(t, errors.Is(err, ErrThrottled))
+
+	n.maxAllocLatency = 0
+	ctx, cancel = n.allocContext(parent, podNetworkTypeENIMultiIP)
+	defer cancel()
+	err = n.throttledErr(ctx, errors.New("foo"))
+	assert.False(t, errors.Is(err, ErrThrottled))
+}
+
+type fakeK8s struct {
+	Kubernetes
+	pods        []*types.PodInfo
+	serviceCIDR *types.IPNetSet
+}
+
+func (f *fakeK8s) GetLocalPods() ([]*types.PodInfo, error) {
+	return f.pods, nil
+}
+
+func (f *fakeK8s) GetServiceCIDR() *types.IPNetSet {
+	return f.serviceCIDR
+}
+
+func Test_GetPodStatus(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	containerID := "container"
+	assert.NoError(t, db.Put(podInfoKey("default", "foo"), types.PodResources{
+		PodInfo:     &types.PodInfo{Namespace: "default", Name: "foo", PodNetworkType: podNetworkTypeENIMultiIP},
+		Resources:   []types.ResourceItem{{Type: types.ResourceTypeENIIP, ID: "mac.ip"}},
+		ContainerID: &containerID,
+	}))
+	n := &networkService{
+		daemonMode: daemonModeENIMultiIP,
+		resourceDB: db,
+	}
+	n.pendingPods.Store(podInfoKey("default", "foo"), time.Now())
+
+	reply, err := n.GetPodStatus(context.Background(), &rpc.GetPodStatusRequest{K8SPodNamespace: "default", K8SPodName: "foo"})
+	assert.NoError(t, err)
+	assert.True(t, reply.Pending)
+	assert.True(t, reply.NetworkTypeValid)
+	assert.Equal(t, containerID, reply.ContainerID)
+	assert.Equal(t, 1, len(reply.Resources))
+
+	_, err = n.GetPodStatus(context.Background(), &rpc.GetPodStatusRequest{K8SPodNamespace: "default", K8SPodName: "bar"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func Test_sortResourceTypesForGC(t *testing.T) {
+	resTypes := []string{types.ResourceTypeENI, "unknown", types.ResourceTypeENIIP, types.ResourceTypeEIP}
+	sortResourceTypesForGC(resTypes)
+	assert.Equal(t, []string{types.ResourceTypeEIP, types.ResourceTypeENIIP, types.ResourceTypeENI, "unknown"}, resTypes)
+}
+
+func Test_sortResourceManagerKeysForGC(t *testing.T) {
+	keys := []resourceManagerKey{
+		{resType: types.ResourceTypeENIIP, poolID: "sg-b"},
+		{resType: types.ResourceTypeEIP, poolID: types.DefaultPoolID},
+		{resType: types.ResourceTypeENIIP, poolID: "sg-a"},
+	}
+	sortResourceManagerKeysForGC(keys)
+	assert.Equal(t, []resourceManagerKey{
+		{resType: types.ResourceTypeEIP, poolID: types.DefaultPoolID},
+		{resType: types.ResourceTypeENIIP, poolID: "sg-a"},
+		{resType: types.ResourceTypeENIIP, poolID: "sg-b"},
+	}, keys)
+}
+
+type namedResourceManager struct {
+	ResourceManager
+	name string
+}
+
+func Test_getResourceManagerForRes(t *testing.T) {
+	n := &networkService{}
+	n.setResourceManagers(map[string]ResourceManager{
+		types.ResourceTypeENIIP: &namedResourceManager{name: "default"},
+	})
+	n.mgrForResource[resourceManagerKey{resType: types.ResourceTypeENIIP, poolID: "sg-a"}] = &namedResourceManager{name: "sg-a"}
+
+	mgr := n.getResourceManagerForRes(types.ResourceItem{Type: types.ResourceTypeENIIP})
+	assert.Equal(t, "default", mgr.(*namedResourceManager).name)
+	mgr = n.getResourceManagerForRes(types.ResourceItem{Type: types.ResourceTypeENIIP, PoolID: "sg-a"})
+	assert.Equal(t, "sg-a", mgr.(*namedResourceManager).name)
+	assert.Nil(t, n.getResourceManagerForRes(types.ResourceItem{Type: types.ResourceTypeENIIP, PoolID: "sg-b"}))
+}
+
+type gcFailManager struct {
+	ResourceManager
+	released int
+}
+
+func (m *gcFailManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) (int, error) {
+	return m.released, fmt.Errorf("release failed")
+}
+
+func Test_networkService_gcPartialFailure(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	n := &networkService{k8s: &fakeK8s{}, resourceDB: db}
+	n.setResourceManagers(map[string]ResourceManager{
+		types.ResourceTypeEIP: &gcFailManager{released: 1},
+	})
+	res := types.PodResources{
+		PodInfo: &types.PodInfo{Namespace: "default", Name: "foo"},
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeEIP, ID: "eip-1"},
+			{Type: types.ResourceTypeEIP, ID: "eip-2"},
+		},
+	}
+	assert.NoError(t, db.Put("default/foo", res))
+
+	// only the resources released are counted, and the failure is reported
+	reclaimed, err := n.gc()
+	assert.Error(t, err)
+	assert.Equal(t, 1, reclaimed)
+	_, err = db.Get("default/foo")
+	assert.NoError(t, err)
+}
+
+func Test_validateIPv6Gateway(t *testing.T) {
+	newConf := func(cidr, gw string) []*rpc.NetConf {
+		return []*rpc.NetConf{{
+			BasicInfo: &rpc.BasicInfo{
+				PodIP:     &rpc.IPSet{IPv6: "fd00::10"},
+				PodCIDR:   &rpc.IPSet{IPv6: cidr},
+				GatewayIP: &rpc.IPSet{IPv6: gw},
+			},
+		}}
+	}
+	assert.NoError(t, validateIPv6Gateway(newConf("fd00::/64", "fd00::ffff:ffff:ffff:fffd")))
+	assert.Error(t, validateIPv6Gateway(newConf("fd00::/64", "fd01::1")))
+	assert.Error(t, validateIPv6Gateway(newConf("fd00::/64", "::1")))
+	assert.Error(t, validateIPv6Gateway(newConf("fd00::/64", "")))
+	assert.Error(t, validateIPv6Gateway(newConf("", "fd00::1")))
+	assert.NoError(t, validateIPv6Gateway([]*rpc.NetConf{{BasicInfo: &rpc.BasicInfo{PodIP: &rpc.IPSet{IPv4: "192.168.0.1"}}}}))
+}
+
+func Test_rollbackReason(t *testing.T) {
+	assert.Equal(t, "throttled", rollbackReason(ErrThrottled))
+	assert.Equal(t, "timeout", rollbackReason(fmt.Errorf("wrap, %w", context.DeadlineExceeded)))
+	assert.Equal(t, "vswitch_ip_not_enough", rollbackReason(fmt.Errorf("error assign ip, %s", apiErr.InvalidVSwitchIDIPNotEnough)))
+	assert.Equal(t, "other", rollbackReason(fmt.Errorf("foo")))
+}
+
+func Test_dropDetachedENIResource(t *testing.T) {
+	localResource := map[string]map[string]resourceManagerInitItem{
+		types.ResourceTypeENIIP: {
+			"00:00:00:00:00:01.192.168.0.1": {item: types.ResourceItem{Type: types.ResourceTypeENIIP, ID: "00:00:00:00:00:01.192.168.0.1", ENIMAC: "00:00:00:00:00:01"}},
+			"00:00:00:00:00:02.192.168.0.2": {item: types.ResourceItem{Type: types.ResourceTypeENIIP, ID: "00:00:00:00:00:02.192.168.0.2"}},
+		},
+		types.ResourceTypeENI: {
+			"00:00:00:00:00:03": {item: types.ResourceItem{Type: types.ResourceTypeENI, ID: "00:00:00:00:00:03"}},
+		},
+	}
+	dropDetachedENIResource(localResource, []string{"00:00:00:00:00:01"})
+	assert.Equal(t, 1, len(localResource[types.ResourceTypeENIIP]))
+	assert.Contains(t, localResource[types.ResourceTypeENIIP], "00:00:00:00:00:01.192.168.0.1")
+	assert.Equal(t, 0, len(localResource[types.ResourceTypeENI]))
+}
+
+func Test_localResourcesOfPool(t *testing.T) {
+	pod := &types.PodInfo{Name: "foo", Namespace: "default"}
+	resObjList := []interface{}{
+		types.PodResources{PodInfo: pod, Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1"},
+			{Type: types.ResourceTypeEIP, ID: "eip-1", PoolID: types.DefaultPoolID},
+		}},
+		types.PodResources{PodInfo: pod, Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-2.192.168.0.2", PoolID: "sg-a"},
+		}},
+	}
+	localResource := localResourcesOfPool(resObjList, types.DefaultPoolID)
+	assert.Len(t, localResource[types.ResourceTypeENIIP], 1)
+	assert.Contains(t, localResource[types.ResourceTypeENIIP], "mac-1.192.168.0.1")
+	assert.Contains(t, localResource[types.ResourceTypeEIP], "eip-1")
+
+	// the resource of other pool is never restored to the default pool
+	localResource = localResourcesOfPool(resObjList, "sg-a")
+	assert.Len(t, localResource[types.ResourceTypeENIIP], 1)
+	assert.Contains(t, localResource[types.ResourceTypeENIIP], "mac-2.192.168.0.2")
+	assert.Empty(t, localResource[types.ResourceTypeEIP])
+}
+
+func Test_mergeDNS(t *testing.T) {
+	assert.Nil(t, mergeDNS(nil, nil))
+
+	def := &types.DNSConfig{Nameservers: []string{"10.0.0.10"}, Search: []string{"svc.cluster.local"}}
+	dns := mergeDNS(def, parsePodDNS(map[string]string{podDNSNameservers: "1.1.1.1, 8.8.8.8", podDNSOptions: "ndots:2"}))
+	assert.Equal(t, []string{"1.1.1.1", "8.8.8.8"}, dns.Nameservers)
+	assert.Equal(t, []string{"svc.cluster.local"}, dns.Search)
+	assert.Equal(t, []string{"ndots:2"}, dns.Options)
+
+	assert.Nil(t, parsePodDNS(map[string]string{podDNSSearch: " "}))
+}
+
+func Test_evictStuckPendingPods(t *testing.T) {
+	n := &networkService{pendingPodTTL: time.Minute}
+	done, ok := n.markPending("default/foo")
+	assert.True(t, ok)
+	_, ok = n.markPending("default/foo")
+	assert.False(t, ok)
+
+	n.pendingPods.Store("default/stuck", time.Now().Add(-2*time.Minute))
+	n.evictStuckPendingPods()
+	_, ok = n.pendingPods.Load("default/stuck")
+	assert.False(t, ok)
+
+	done()
+	_, ok = n.pendingPods.Load("default/foo")
+	assert.False(t, ok)
+}
+
+func Test_validateVlanID(t *testing.T) {
+	n := &networkService{trunkVlanMin: 100, trunkVlanMax: 200}
+	assert.NoError(t, n.validateVlanID(100))
+	assert.NoError(t, n.validateVlanID(200))
+	assert.Error(t, n.validateVlanID(99))
+	assert.Error(t, n.validateVlanID(201))
+}
+
+func Test_validateConfigServiceCIDR(t *testing.T) {
+	assert.NoError(t, validateConfig(&daemon.Config{}))
+	assert.NoError(t, validateConfig(&daemon.Config{ServiceCIDR: "172.21.0.0/20,fd00::/108"}))
+	assert.Error(t, validateConfig(&daemon.Config{ServiceCIDR: "172.21.0.0"}))
+}
+
+func Test_normalizeTags(t *testing.T) {
+	tags := map[string]string{" ack.aliyun.com ": "c123 ", "env": "prod"}
+	assert.NoError(t, normalizeTags("eni_tags", tags))
+	assert.Equal(t, map[string]string{"ack.aliyun.com": "c123", "env": "prod"}, tags)
+	assert.NoError(t, normalizeTags("eni_tags", nil))
+
+	for _, tags := range []map[string]string{
+		{"": "v"},
+		{"env": "prod", "env ": "test"},
+		{strings.Repeat("k", 129): "v"},
+		{"aliyun-env": "v"},
+		{"acs:env": "v"},
+		{"env": strings.Repeat("v", 129)},
+		{"env": "acs:prod"},
+		{"url": "https://example.com"},
+		{"en\tv": "v"},
+	} {
+		assert.Error(t, normalizeTags("eni_tags", tags), "%v", tags)
+	}
+
+	err := validateConfig(&daemon.Config{ENITagFilter: map[string]string{"acs:env": "v"}})
+	assert.ErrorContains(t, err, "eni_tag_filter")
+}
+
+func Test_eniNameAndDescription(t *testing.T) {
+	name, desc, err := eniNameAndDescription("terway", "cn-hangzhou.192.168.0.1", "c123")
+	assert.NoError(t, err)
+	assert.Equal(t, "terway-c123-cn-hangzhou.192.168.0.1-", name)
+	assert.Equal(t, "interface create by terway, cluster: c123, node: cn-hangzhou.192.168.0.1", desc)
+
+	name, _, err = eniNameAndDescription("terway", "node1", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "terway-node1-", name)
+
+	_, _, err = eniNameAndDescription("terway", strings.Repeat("n", 128), "")
+	assert.Error(t, err)
+}
+
+func Test_crossZoneVSwitches(t *testing.T) {
+	vsws := map[string][]string{
+		"cn-hangzhou-i": {"vsw-i"},
+		"cn-hangzhou-k": {"vsw-k1", "vsw-k2"},
+		"cn-hangzhou-j": {"vsw-j"},
+	}
+	assert.Equal(t, []string{"vsw-j", "vsw-k1", "vsw-k2"}, crossZoneVSwitches(vsws, "cn-hangzhou-i"))
+	assert.Nil(t, crossZoneVSwitches(nil, "cn-hangzhou-i"))
+}
+
+type eventRecorderK8s struct {
+	Kubernetes
+	events []string
+}
+
+func (f *eventRecorderK8s) RecordNodeEvent(eventType, reason, message string) {
+	f.events = append(f.events, reason)
+}
+
+func Test_podNetworkPriority(t *testing.T) {
+	n := &networkService{defaultNetworkPriority: string(types.NetworkPrioBurstable)}
+	assert.Equal(t, "burstable", n.podNetworkPriority(&types.PodInfo{}))
+	assert.Equal(t, "guaranteed", n.podNetworkPriority(&types.PodInfo{NetworkPriority: "guaranteed"}))
+	assert.Equal(t, "burstable", n.podNetworkPriority(&types.PodInfo{NetworkPriority: "foo"}))
+	assert.Error(t, validateConfig(&daemon.Config{DefaultNetworkPriority: "foo"}))
+
+	// the invalid annotation reaches the clamp
+	pod := &corev1.Pod{}
+	pod.Name, pod.Namespace = "foo", "default"
+	pod.Annotations = map[string]string{types.NetworkPriority: "foo"}
+	assert.Equal(t, "foo", convertPod(daemonModeENIMultiIP, nil, pod).NetworkPriority)
+	assert.Equal(t, "burstable", n.podNetworkPriority(convertPod(daemonModeENIMultiIP, nil, pod)))
+
+	// the event is recorded only by the clamp on allocation
+	var reasons []string
+	tracing.RegisterEventRecorder(nil, func(podName, podNamespace, eventType, reason, message string) error {
+		reasons = append(reasons, reason)
+		return nil
+	})
+	defer tracing.RegisterEventRecorder(nil, nil)
+	n.podNetworkPriority(&types.PodInfo{NetworkPriority: "foo"})
+	assert.Empty(t, reasons)
+	assert.Equal(t, "burstable", n.clampPodNetworkPriority(&types.PodInfo{NetworkPriority: "foo"}))
+	assert.Equal(t, "guaranteed", n.clampPodNetworkPriority(&types.PodInfo{NetworkPriority: "guaranteed"}))
+	assert.Equal(t, []string{"NetworkPriorityClamped"}, reasons)
+}
+
+func Test_parseExtraRoute(t *testing.T) {
+	assert.Nil(t, parseExtraRoute(nil, 100, 1000))
+
+	routes := parseExtraRoute([]podENITypes.Route{
+		{Dst: "10.0.0.0/8"},
+		{Dst: "172.16.0.0/12", Src: "192.168.0.10", Table: 200, Priority: 2000},
+	}, 100, 1000)
+	assert.Equal(t, &rpc.Route{Dst: "10.0.0.0/8", Table: 100, Priority: 1000}, routes[0])
+	assert.Equal(t, &rpc.Route{Dst: "172.16.0.0/12", Src: "192.168.0.10", Table: 200, Priority: 2000}, routes[1])
+
+	routes = parseExtraRoute([]podENITypes.Route{{Dst: "10.0.0.0/8"}}, 0, 1000)
+	assert.Equal(t, &rpc.Route{Dst: "10.0.0.0/8"}, routes[0])
+}
+
+func Test_verifyDualStack(t *testing.T) {
+	pod := &types.PodInfo{Namespace: "default", Name: "foo"}
+	v4 := types.IPSet{IPv4: net.ParseIP("192.168.0.1")}
+	dual := types.IPSet{IPv4: net.ParseIP("192.168.0.1"), IPv6: net.ParseIP("fd00::1")}
+
+	n := &networkService{ipFamily: &types.IPFamily{IPv4: true, IPv6: true}}
+	reply := &rpc.AllocIPReply{IPv4: true, IPv6: true}
+	assert.NoError(t, n.verifyDualStack(pod, dual, reply))
+	assert.ErrorIs(t, n.verifyDualStack(pod, v4, reply), ErrPartialDualStack)
+	assert.True(t, reply.IPv6)
+
+	n.allowPartialDualStack = true
+	assert.NoError(t, n.verifyDualStack(pod, v4, reply))
+	assert.True(t, reply.IPv4)
+	assert.False(t, reply.IPv6)
+
+	n = &networkService{ipFamily: &types.IPFamily{IPv4: true}}
+	assert.NoError(t, n.verifyDualStack(pod, v4, &rpc.AllocIPReply{IPv4: true}))
+}
+
+func Test_networkService_gcHistory(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	n := &networkService{k8s: &fakeK8s{}, resourceDB: db, eipStickTime: time.Minute, maxIPStickDuration: time.Minute, podHistory: newPodHistory(time.Hour)}
+	n.setResourceManagers(map[string]ResourceManager{
+		types.ResourceTypeENIIP: &gcRecordManager{},
+		types.ResourceTypeEIP:   &gcRecordManager{},
+	})
+	assert.NoError(t, db.Put("default/foo", types.PodResources{
+		PodInfo: &types.PodInfo{Namespace: "default", Name: "foo"},
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1", ENIID: "eni-1", IPv4: "192.168.0.1"},
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.2", ENIID: "eni-1", IPv4: "192.168.0.2"},
+			{Type: types.ResourceTypeEIP, ID: "eip-1", ExtraEipInfo: &types.ExtraEipInfo{AssociateENI: "eni-1", AssociateENIIP: net.ParseIP("192.168.0.1")}},
+		},
+	}))
+	assert.NoError(t, db.Put("default/bar", types.PodResources{
+		PodInfo:     &types.PodInfo{Namespace: "default", Name: "bar", IPStickTime: time.Hour},
+		Resources:   []types.ResourceItem{{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.3", ENIID: "eni-1", IPv4: "192.168.0.3"}},
+		AllocatedAt: time.Now().Add(-time.Hour),
+	}))
+
+	_, err := n.gc()
+	assert.NoError(t, err)
+	actions := func(podKey string) []string {
+		var ret []string
+		for _, entry := range n.podHistory.Get(podKey) {
+			ret = append(ret, entry.Action+" "+strings.Join(entry.Resources, " "))
+		}
+		return ret
+	}
+	assert.Equal(t, []string{"gc_pod_deleted (eniIp)mac-1.192.168.0.2[192.168.0.2]"}, actions("default/foo"))
+	assert.Equal(t, []string{"gc_ip_stick_expired (eniIp)mac-1.192.168.0.3[192.168.0.3]"}, actions("default/bar"))
+
+	obj, err := db.Get("default/foo")
+	assert.NoError(t, err)
+	kept := obj.(types.PodResources)
+	kept.EIPStickUntil = time.Now().Add(-time.Second)
+	assert.NoError(t, db.Put("default/foo", kept))
+	_, err = n.gc()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"gc_pod_deleted (eniIp)mac-1.192.168.0.2[192.168.0.2]",
+		"gc_eip_stick_expired (eip)eip-1",
+		"gc_eip_stick_expired (eniIp)mac-1.192.168.0.1[192.168.0.1]",
+	}, actions("default/foo"))
+}
+
+func Test_setDefaultTrunkMinENI(t *testing.T) {
+	cfg := &daemon.Config{EnableENITrunking: true}
+	assert.NoError(t, setDefault(cfg))
+	assert.Equal(t, 1, cfg.MinENI)
+
+	cfg = &daemon.Config{EnableENITrunking: true, MinENI: 3}
+	assert.NoError(t, setDefault(cfg))
+	assert.Equal(t, 3, cfg.MinENI)
+
+	cfg = &daemon.Config{EnableENITrunking: true, WaitTrunkENI: true}
+	assert.NoError(t, setDefault(cfg))
+	assert.Equal(t, 0, cfg.MinENI)
+}
+
+func Test_checkExtraRoutes(t *testing.T) {
+	serviceCIDR := &types.IPNetSet{}
+	serviceCIDR.SetIPNet("172.21.0.0/20")
+	pod := &types.PodInfo{Namespace: "default", Name: "foo"}
+	overlap := []*rpc.Route{{Dst: "10.0.0.0/8"}, {Dst: "172.16.0.0/12"}}
+
+	n := &networkService{k8s: &fakeK8s{serviceCIDR: serviceCIDR}}
+	assert.NoError(t, n.checkExtraRoutes(pod, overlap))
+
+	n.validateExtraRoutes = true
+	assert.Error(t, n.checkExtraRoutes(pod, overlap))
+	assert.Error(t, n.checkExtraRoutes(pod, []*rpc.Route{{Dst: "172.21.1.0/24"}}))
+	assert.NoError(t, n.checkExtraRoutes(pod, []*rpc.Route{{Dst: "10.0.0.0/8"}, {Dst: "172.21.16.0/20"}}))
+}
+
+func Test_vpcExtraRoutes(t *testing.T) {
+	nodeCIDR := &types.IPNetSet{}
+	nodeCIDR.SetIPNet("10.0.1.0/24")
+	serviceCIDR := &types.IPNetSet{}
+	serviceCIDR.SetIPNet("172.21.0.0/20")
+
+	routes, err := vpcExtraRoutes([]string{"192.168.0.0/16", "10.1.0.1/16"}, nodeCIDR, serviceCIDR)
+	assert.NoError(t, err)
+	assert.Equal(t, []*rpc.Route{{Dst: "192.168.0.0/16"}, {Dst: "10.1.0.0/16"}}, routes)
+
+	routes, err = vpcExtraRoutes(nil, nodeCIDR, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, routes)
+
+	for _, dst := range []string{"10.0.0.0/8", "10.0.1.128/25", "172.21.1.0/24", "foo", "fd00::/64"} {
+		_, err = vpcExtraRoutes([]string{dst}, nodeCIDR, serviceCIDR)
+		assert.Error(t, err, dst)
+	}
+}
+
+type fakePatchK8s struct {
+	Kubernetes
+	failures int
+	patched  string
+}
+
+func (f *fakePatchK8s) PatchPodIPInfo(info *types.PodInfo, ips string) error {
+	if f.failures > 0 {
+		f.failures--
+		return fmt.Errorf("patch failed")
+	}
+	f.patched = ips
+	return nil
+}
+
+func Test_patchPodIPInfo(t *testing.T) {
+	pod := &types.PodInfo{Namespace: "default", Name: "foo"}
+
+	k := &fakePatchK8s{failures: 1}
+	n := &networkService{k8s: k, patchPodIPRetries: 1}
+	n.patchPodIPInfo(pod, "192.168.0.1")
+	assert.Equal(t, "192.168.0.1", k.patched)
+
+	k = &fakePatchK8s{failures: 2}
+	n = &networkService{k8s: k, patchPodIPRetries: 1}
+	n.patchPodIPInfo(pod, "192.168.0.1")
+	assert.Equal(t, "", k.patched)
+	assert.Equal(t, 0, k.failures)
+}
+
+type fakeGetPodK8s struct {
+	Kubernetes
+	err   error
+	calls int
+}
+
+func (f *fakeGetPodK8s) GetPod(namespace, name string) (*types.PodInfo, error) {
+	f.calls++
+	if f.err != nil && f.calls <= 2 {
+		return nil, f.err
+	}
+	return &types.PodInfo{Namespace: namespace, Name: name}, nil
+}
+
+func Test_getPod(t *testing.T) {
+	k := &fakeGetPodK8s{err: fmt.Errorf("connection refused")}
+	n := &networkService{k8s: k}
+	pod, err := n.getPod(context.Background(), "default", "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", pod.Name)
+	assert.Equal(t, 3, k.calls)
+
+	k = &fakeGetPodK8s{err: fmt.Errorf("connection refused")}
+	n = &networkService{k8s: k, getPodRetries: 1}
+	_, err = n.getPod(context.Background(), "default", "foo")
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 2, k.calls)
+
+	// not found fail fast
+	k = &fakeGetPodK8s{err: k8sErr.NewNotFound(corev1.Resource("pods"), "foo")}
+	n = &networkService{k8s: k}
+	_, err = n.getPod(context.Background(), "default", "foo")
+	assert.True(t, k8sErr.IsNotFound(err))
+	assert.Equal(t, 1, k.calls)
+}
+
+func Test_retryStartupK8S(t *testing.T) {
+	calls := 0
+	err := retryStartupK8S("foo", 1, func() error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("connection refused")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	calls = 0
+	err = retryStartupK8S("foo", 1, func() error {
+		calls++
+		return fmt.Errorf("connection refused")
+	})
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, 2, calls)
+
+	// not found fail fast
+	calls = 0
+	err = retryStartupK8S("foo", 1, func() error {
+		calls++
+		return k8sErr.NewNotFound(corev1.Resource("configmaps"), "kubeadm-config")
+	})
+	assert.True(t, k8sErr.IsNotFound(err))
+	assert.Equal(t, 1, calls)
+}
+
+func Test_duplicateContainerIDs(t *testing.T) {
+	containerID := func(s string) *string {
+		return &s
+	}
+	podResList := []interface{}{
+		types.PodResources{PodInfo: &types.PodInfo{Namespace: "default", Name: "a"}, ContainerID: containerID("c1")},
+		types.PodResources{PodInfo: &types.PodInfo{Namespace: "default", Name: "b"}, ContainerID: containerID("c1")},
+		types.PodResources{PodInfo: &types.PodInfo{Namespace: "default", Name: "c"}, ContainerID: containerID("c2")},
+		types.PodResources{PodInfo: &types.PodInfo{Namespace: "default", Name: "d"}},
+	}
+	duplicated := duplicateContainerIDs(podResList)
+	assert.Len(t, duplicated, 1)
+	assert.Len(t, duplicated["c1"], 2)
+}
+
+type fakeCounterMgr struct {
+	ResourceManager
+	free int
+}
+
+func (f *fakeCounterMgr) Free() int {
+	return f.free
+}
+
+func Test_checkIPReserve(t *testing.T) {
+	pod := &types.PodInfo{Namespace: "default", Name: "foo"}
+	n := &networkService{eniIPResMgr: &fakeCounterMgr{free: 3}}
+	assert.NoError(t, n.checkIPReserve(pod, 1))
+
+	n.reserveIPsForCritical = 2
+	assert.NoError(t, n.checkIPReserve(pod, 1))
+	err := n.checkIPReserve(pod, 2)
+	assert.ErrorIs(t, err, ErrIPReserved)
+	assert.Equal(t, "ip_reserved", rollbackReason(err))
+
+	pod.Critical = true
+	assert.NoError(t, n.checkIPReserve(pod, 2))
+}
+
+type fakeCapacityMgr struct {
+	fakeCounterMgr
+	creatable int
+	capacity  int
+}
+
+func (f *fakeCapacityMgr) Capacity() (int, int) {
+	return f.creatable, f.capacity
+}
+
+func Test_networkService_SetLogLevel(t *testing.T) {
+	n := &networkService{resourceDB: storage.NewMemoryStorage()}
+	defer logger.DefaultLogger.SetLevel(logger.DefaultLogger.GetLevel())
+	assert.NoError(t, logger.SetLevel("info"))
+
+	reply, err := n.SetLogLevel(context.Background(), &rpc.SetLogLevelRequest{Level: "debug"})
+	assert.NoError(t, err)
+	assert.Equal(t, "info", reply.Previous)
+	assert.Contains(t, n.Trace(), tracing.MapKeyValueEntry{Key: tracingKeyLogLevel, Value: "debug"})
+
+	_, err = n.SetLogLevel(context.Background(), &rpc.SetLogLevelRequest{Level: "verbose"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "debug", logger.DefaultLogger.GetLevel().String())
+}
+
+func Test_networkService_GetAllocatableCapacity(t *testing.T) {
+	mgr := &fakeCapacityMgr{fakeCounterMgr: fakeCounterMgr{free: 3}, creatable: 2, capacity: 10}
+	n := &networkService{daemonMode: daemonModeENIMultiIP, eniIPResMgr: mgr}
+	reply, err := n.GetAllocatableCapacity(context.Background(), &rpc.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), reply.Free)
+	assert.Equal(t, int32(10), reply.Capacity)
+	assert.True(t, reply.CanGrow)
+	assert.Equal(t, rpc.CapacityLimit_CapacityLimitNone, reply.Limit)
+
+	mgr.creatable = 0
+	reply, err = n.GetAllocatableCapacity(context.Background(), &rpc.Empty{})
+	assert.NoError(t, err)
+	assert.False(t, reply.CanGrow)
+	assert.Equal(t, rpc.CapacityLimit_CapacityLimitPoolCapacity, reply.Limit)
+
+	n.reserveIPsForCritical = 3
+	reply, err = n.GetAllocatableCapacity(context.Background(), &rpc.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, rpc.CapacityLimit_CapacityLimitReserved, reply.Limit)
+
+	n.daemonMode = daemonModeVPC
+	reply, err = n.GetAllocatableCapacity(context.Background(), &rpc.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, rpc.CapacityLimit_CapacityLimitUnsupported, reply.Limit)
+
+	n.readOnly = true
+	reply, err = n.GetAllocatableCapacity(context.Background(), &rpc.Empty{})
+	assert.NoError(t, err)
+	assert.Equal(t, rpc.CapacityLimit_CapacityLimitReadOnly, reply.Limit)
+}
+
+type fakeNetworkTypeK8s struct {
+	Kubernetes
+	podNetworkType string
+	hostNetwork    bool
+}
+
+func (f *fakeNetworkTypeK8s) GetPod(namespace, name string) (*types.PodInfo, error) {
+	return &types.PodInfo{Namespace: namespace, Name: name, PodNetworkType: f.podNetworkType, HostNetwork: f.hostNetwork}, nil
+}
+
+func (f *fakeNetworkTypeK8s) GetNodeName() string {
+	return "node"
+}
+
+func Test_networkService_AllocIP_unsupportedNetworkType(t *testing.T) {
+	n := &networkService{
+		k8s:           &fakeNetworkTypeK8s{podNetworkType: podNetworkTypeVPCENI},
+		daemonMode:    daemonModeENIMultiIP,
+		ipFamily:      types.NewIPFamilyFromIPStack(types.IPStackIPv4),
+		resourceDB:    storage.NewMemoryStorage(),
+		allocFailures: newAllocFailureLog(defaultAllocFailureLogSize),
+		podHistory:    newPodHistory(time.Hour),
+	}
+	reply, err := n.AllocIP(context.Background(), &rpc.AllocIPRequest{K8SPodNamespace: "default", K8SPodName: "foo"})
+	assert.NoError(t, err)
+	assert.False(t, reply.Success)
+	assert.Equal(t, rpc.Error_ErrUnsupportedNetworkType, reply.Error)
+	// the rejected pod is not recorded as allocated
+	assert.Empty(t, n.podHistory.Get("default/foo"))
+
+	failures := n.allocFailures.List()
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "default/foo", failures[0].Pod)
+	assert.Equal(t, "unsupported_network_type", failures[0].Reason)
+}
+
+// fakeVSwitchK8s return the pod requesting ip from the vswitch by annotation
+type fakeVSwitchK8s struct {
+	fakeNetworkTypeK8s
+	vSwitchID string
+}
+
+func (f *fakeVSwitchK8s) GetPod(namespace, name string) (*types.PodInfo, error) {
+	pod, err := f.fakeNetworkTypeK8s.GetPod(namespace, name)
+	if err != nil {
+		return nil, err
+	}
+	pod.VSwitchID = f.vSwitchID
+	return pod, nil
+}
+
+func Test_networkService_AllocIP_vSwitchNotConfigured(t *testing.T) {
+	n := &networkService{
+		k8s:           &fakeVSwitchK8s{fakeNetworkTypeK8s: fakeNetworkTypeK8s{podNetworkType: podNetworkTypeENIMultiIP}, vSwitchID: "vsw-2"},
+		daemonMode:    daemonModeENIMultiIP,
+		ipFamily:      types.NewIPFamilyFromIPStack(types.IPStackIPv4),
+		resourceDB:    storage.NewMemoryStorage(),
+		allocFailures: newAllocFailureLog(defaultAllocFailureLogSize),
+		vSwitches:     sets.NewString("vsw-1"),
+	}
+	_, err := n.AllocIP(context.Background(), &rpc.AllocIPRequest{K8SPodNamespace: "default", K8SPodName: "foo"})
+	assert.ErrorContains(t, err, "vswitch vsw-2 requested by pod is not configured")
+
+	// the failure is seen by the rollback
+	failures := n.allocFailures.List()
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "default/foo", failures[0].Pod)
+}
+
+func Test_networkService_AllocIP_hostNetwork(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	n := &networkService{
+		k8s:           &fakeNetworkTypeK8s{podNetworkType: podNetworkTypeENIMultiIP, hostNetwork: true},
+		daemonMode:    daemonModeENIMultiIP,
+		ipFamily:      types.NewIPFamilyFromIPStack(types.IPStackIPv4),
+		resourceDB:    db,
+		allocFailures: newAllocFailureLog(defaultAllocFailureLogSize),
+		podHistory:    newPodHistory(time.Hour),
+	}
+	reply, err := n.AllocIP(context.Background(), &rpc.AllocIPRequest{K8SPodNamespace: "default", K8SPodName: "foo"})
+	assert.NoError(t, err)
+	assert.False(t, reply.Success)
+	assert.Equal(t, rpc.Error_ErrHostNetworkPod, reply.Error)
+	assert.Empty(t, reply.NetConfs)
+
+	// nothing is allocated or recorded for the host network pod
+	list, err := db.List()
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+	assert.Empty(t, n.allocFailures.List())
+	assert.Empty(t, n.podHistory.Get("default/foo"))
+}
+
+func Test_mappingMismatchKind(t *testing.T) {
+	assert.Equal(t, mismatchKindPodBind, mappingMismatchKind(&tracing.PodMapping{Name: "foo", PodBindResID: "a"}))
+	assert.Equal(t, mismatchKindLocal, mappingMismatchKind(&tracing.PodMapping{RemoteResID: "a"}))
+	assert.Equal(t, mismatchKindRemote, mappingMismatchKind(&tracing.PodMapping{LocalResID: "a"}))
+	assert.Equal(t, mismatchKindPodBind, mappingMismatchKind(&tracing.PodMapping{Name: "foo", PodBindResID: "b", LocalResID: "a", RemoteResID: "a"}))
+}
+
+func Test_setDefaultEniCapRatio(t *testing.T) {
+	cfg := &daemon.Config{}
+	assert.NoError(t, setDefault(cfg))
+	assert.Equal(t, float64(1), cfg.EniCapRatio)
+
+	cfg = &daemon.Config{EniCapRatio: 10}
+	assert.NoError(t, setDefault(cfg))
+	assert.Equal(t, float64(defaultMaxEniCapRatio), cfg.EniCapRatio)
+
+	cfg = &daemon.Config{EniCapRatio: 3, MaxEniCapRatio: 4}
+	assert.NoError(t, setDefault(cfg))
+	assert.Equal(t, float64(3), cfg.EniCapRatio)
+
+	assert.Error(t, validateConfig(&daemon.Config{EniCapRatio: -1}))
+	assert.Error(t, validateConfig(&daemon.Config{MaxEniCapRatio: 0.5}))
+}
+
+func Test_autoSizePool(t *testing.T) {
+	cfg := &daemon.Config{EniCapRatio: 1, MaxPoolSize: 5}
+	limit := &aliyun.Limits{Adapters: 4, IPv4PerAdapter: 10}
+
+	maxENI, maxPoolSize := autoSizePool(cfg, daemonModeENIMultiIP, limit)
+	assert.Equal(t, 3, maxENI)
+	assert.Equal(t, 27, maxPoolSize)
+
+	maxENI, maxPoolSize = autoSizePool(cfg, daemonModeENIOnly, limit)
+	assert.Equal(t, 3, maxENI)
+	assert.Equal(t, 3, maxPoolSize)
+
+	_, maxPoolSize = autoSizePool(cfg, daemonModeVPC, limit)
+	assert.Equal(t, 5, maxPoolSize)
+
+	maxENI, maxPoolSize = autoSizePool(&daemon.Config{EniCapRatio: 0.1}, daemonModeENIMultiIP, limit)
+	assert.Equal(t, 0, maxENI)
+	assert.Equal(t, 0, maxPoolSize)
+}
+
+func Test_allocContextByNetworkType(t *testing.T) {
+	n := &networkService{
+		maxAllocLatency:       time.Second,
+		maxAllocLatencyByType: map[string]time.Duration{podNetworkTypeVPCENI: time.Minute},
+	}
+	ctx, cancel := n.allocContext(context.Background(), podNetworkTypeVPCENI)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.True(t, time.Until(deadline) > time.Second)
+
+	ctx, cancel = n.allocContext(context.Background(), podNetworkTypeENIMultiIP)
+	defer cancel()
+	deadline, ok = ctx.Deadline()
+	assert.True(t, ok)
+	assert.True(t, time.Until(deadline) <= time.Second)
+
+	assert.Error(t, validateConfig(&daemon.Config{MaxAllocLatencySecondsByNetworkType: map[string]int{"foo": 1}}))
+	assert.Error(t, validateConfig(&daemon.Config{MaxAllocLatencySecondsByNetworkType: map[string]int{podNetworkTypeVPCENI: -1}}))
+}
+
+type conditionRecorderK8s struct {
+	eventRecorderK8s
+	conditions map[corev1.NodeConditionType]corev1.ConditionStatus
+}
+
+func (f *conditionRecorderK8s) SetNodeCondition(conditionType corev1.NodeConditionType, status corev1.ConditionStatus, reason, message string) error {
+	f.conditions[conditionType] = status
+	return nil
+}
+
+func Test_reportCNICheck(t *testing.T) {
+	assert.False(t, cniCheckDegraded(0, 0, 0.5))
+	assert.False(t, cniCheckDegraded(10, 10, 0))
+	assert.False(t, cniCheckDegraded(10, 5, 0.5))
+	assert.True(t, cniCheckDegraded(10, 6, 0.5))
+
+	k8s := &conditionRecorderK8s{conditions: map[corev1.NodeConditionType]corev1.ConditionStatus{}}
+	n := &networkService{k8s: k8s, cniCheckFailureRatio: 0.5}
+	n.reportCNICheck(10, 1)
+	assert.Equal(t, corev1.ConditionTrue, k8s.conditions[types.NodeConditionPodNetworkHealthy])
+	assert.Empty(t, k8s.events)
+
+	// event only on becoming degraded
+	n.reportCNICheck(10, 8)
+	n.reportCNICheck(10, 9)
+	assert.Equal(t, corev1.ConditionFalse, k8s.conditions[types.NodeConditionPodNetworkHealthy])
+	assert.Equal(t, []string{"PodNetworkDegraded"}, k8s.events)
+
+	n.reportCNICheck(10, 0)
+	assert.Equal(t, corev1.ConditionTrue, k8s.conditions[types.NodeConditionPodNetworkHealthy])
+
+	assert.Error(t, validateConfig(&daemon.Config{CNICheckFailureRatio: 1.5}))
+}
+
+func Test_listPendingPods(t *testing.T) {
+	n := &networkService{}
+	n.pendingPods.Store("default/b", time.Now())
+	n.pendingPods.Store("default/a", time.Now().Add(-time.Minute))
+	pods := n.listPendingPods()
+	assert.Len(t, pods, 2)
+	assert.Equal(t, "default/a", pods[0].key)
+	assert.Equal(t, "default/b", pods[1].key)
+}
+
+func Test_networkService_maxPendingPods(t *testing.T) {
+	n := &networkService{maxPendingPods: 1, resourceDB: storage.NewMemoryStorage(), allocFailures: newAllocFailureLog(defaultAllocFailureLogSize)}
+	n.pendingPods.Store("default/a", time.Now())
+	_, err := n.AllocIP(context.Background(), &rpc.AllocIPRequest{K8SPodNamespace: "default", K8SPodName: "b"})
+	assert.Equal(t, ErrTooBusy, err)
+	assert.Equal(t, 1, n.pendingCount())
+
+	// the rejected pod is in the alloc failure log
+	failures := n.allocFailures.List()
+	assert.Len(t, failures, 1)
+	assert.Equal(t, "default/b", failures[0].Pod)
+	assert.Equal(t, "too_busy", failures[0].Reason)
+
+	trace := n.Trace()
+	assert.Contains(t, trace, tracing.MapKeyValueEntry{Key: tracingKeyPendingPodsCount, Value: "1"})
+	assert.Contains(t, trace, tracing.MapKeyValueEntry{Key: tracingKeyMaxPendingPods, Value: "1"})
+}
+
+func Test_podResourcesSchemaVersion(t *testing.T) {
+	data, err := marshalPodResources(types.PodResources{PodInfo: &types.PodInfo{Name: "foo"}})
+	assert.NoError(t, err)
+	obj, err := unmarshalPodResources(data)
+	assert.NoError(t, err)
+	assert.Equal(t, types.PodResourcesSchemaVersion, obj.(types.PodResources).SchemaVersion)
+	assert.Equal(t, "foo", obj.(types.PodResources).PodInfo.Name)
+
+	// stored by old version
+	obj, err = unmarshalPodResources([]byte(`{"PodInfo":{"Name":"foo"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", obj.(types.PodResources).PodInfo.Name)
+
+	obj, err = unmarshalPodResources([]byte(`{"SchemaVersion":99,"PodInfo":{"Name":"foo"}}`))
+	assert.ErrorIs(t, err, storage.ErrQuarantine)
+	assert.Equal(t, "foo", obj.(types.PodResources).PodInfo.Name)
+}
+
+func Test_listQuarantinedPodResources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod.db")
+	newer := types.PodResources{
+		PodInfo:       &types.PodInfo{Namespace: "default", Name: "foo"},
+		Resources:     []types.ResourceItem{{Type: types.ResourceTypeENIIP, ID: "00:00:00:00:00:01.192.168.0.10"}},
+		SchemaVersion: types.PodResourcesSchemaVersion + 1,
+	}
+	db, err := storage.NewDiskStorage(resDBName, path, json.Marshal, unmarshalPodResources)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put("default/foo", newer))
+	assert.NoError(t, db.(*storage.DiskStorage).Close())
+
+	// the record from newer version is moved out, but the resources of it are still listed
+	db, err = storage.NewDiskStorage(resDBName, path, json.Marshal, unmarshalPodResources)
+	assert.NoError(t, err)
+	list, err := db.List()
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+	list, err = listQuarantinedPodResources(db)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(list))
+	assert.Equal(t, newer.Resources, list[0].(types.PodResources).Resources)
+	assert.NoError(t, db.(*storage.DiskStorage).Close())
+
+	list, err = listQuarantinedPodResources(storage.NewMemoryStorage())
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func Test_splitResourcesByTypes(t *testing.T) {
+	res := types.PodResources{Resources: []types.ResourceItem{
+		{Type: types.ResourceTypeENI, ID: "eni-1"},
+		{Type: types.ResourceTypeEIP, ID: "eip-1"},
+	}}
+
+	release, keep, err := splitResourcesByTypes(res, []string{types.ResourceTypeEIP})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ResourceItem{{Type: types.ResourceTypeEIP, ID: "eip-1"}}, release)
+	assert.Equal(t, []types.ResourceItem{{Type: types.ResourceTypeENI, ID: "eni-1"}}, keep)
+
+	release, keep, err = splitResourcesByTypes(res, []string{types.ResourceTypeENI, types.ResourceTypeEIP})
+	assert.NoError(t, err)
+	assert.Len(t, release, 2)
+	assert.Empty(t, keep)
+
+	_, _, err = splitResourcesByTypes(res, []string{types.ResourceTypeENI})
+	assert.Error(t, err)
+	_, _, err = splitResourcesByTypes(res, []string{"foo"})
+	assert.Error(t, err)
+
+	// no eip to depend on
+	_, _, err = splitResourcesByTypes(types.PodResources{Resources: res.Resources[:1]}, []string{types.ResourceTypeENI})
+	assert.NoError(t, err)
+}
+
+func Test_networkService_containerIDMismatched(t *testing.T) {
+	pod := &types.PodInfo{Name: "foo", Namespace: "default"}
+	ctx := &networkContext{Context: context.Background(), pod: pod}
+	n := &networkService{containerIDMismatchThreshold: 3}
+
+	n.containerIDMismatched(ctx, "GetIPInfo", "c1", "c2")
+	n.containerIDMismatched(ctx, "ReleaseIP", "c1", "c2")
+	assert.Equal(t, 2, n.containerIDMismatches.counts["default/foo"])
+
+	// reset by the request matched
+	n.containerIDMatched(pod)
+	n.containerIDMismatched(ctx, "GetIPInfo", "c1", "c2")
+	assert.Equal(t, 1, n.containerIDMismatches.counts["default/foo"])
+
+	// reported and reset on reaching the threshold
+	n.containerIDMismatched(ctx, "GetIPInfo", "c1", "c2")
+	n.containerIDMismatched(ctx, "GetIPInfo", "c1", "c2")
+	_, ok := n.containerIDMismatches.counts["default/foo"]
+	assert.False(t, ok)
+
+	n.containerIDMismatchThreshold = 0
+	n.containerIDMismatched(ctx, "GetIPInfo", "c1", "c2")
+	assert.Empty(t, n.containerIDMismatches.counts)
+}
+
+func Test_networkService_ExecuteJSON(t *testing.T) {
+	n := &networkService{daemonMode: daemonModeVPC, podHistory: newPodHistory(time.Hour)}
+	n.pendingPods.Store("default/a", time.Now())
+	n.podHistory.Add("default/a", podHistoryAlloc, []types.ResourceItem{{Type: types.ResourceTypeVeth, ID: "veth-1"}})
+	execute := func(cmd string, args ...string) string {
+		message := make(chan string, 10)
+		go n.Execute(cmd, args, message)
+		var out string
+		for m := range message {
+			out += m
+		}
+		return out
+	}
+
+	assert.JSONEq(t, `{"Mapping":null}`, execute(commandMapping, commandArgJSON))
+	assert.JSONEq(t, `[]`, execute(commandFailures, commandArgJSON))
+
+	var pending []map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(execute(commandPending, commandArgJSON)), &pending))
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "default/a", pending[0]["Pod"])
+
+	assert.JSONEq(t, `["default/a"]`, execute(commandHistory, commandArgJSON))
+	var history map[string][]podHistoryEntry
+	assert.NoError(t, json.Unmarshal([]byte(execute(commandHistory, "default/a", "default/b", commandArgJSON)), &history))
+	assert.Equal(t, []string{"(veth)veth-1"}, history["default/a"][0].Resources)
+	assert.Empty(t, history["default/b"])
+
+	// text output without the json arg
+	assert.Contains(t, execute(commandPending), "1 pending pods")
+}
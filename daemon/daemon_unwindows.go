@@ -3,6 +3,13 @@
 
 package daemon
 
+import "github.com/AliyunContainerService/terway/pkg/link"
+
 func preStartResourceManager(daemonMode string, k8s Kubernetes) error {
 	return nil
 }
+
+// orphanRoutesDeleter return the func delete the ip rules and routes of the eni deleted
+func orphanRoutesDeleter() func(rulePriority, tableMin, tableMax int, liveMACs []string) ([]string, error) {
+	return link.DeleteOrphanRoutes
+}
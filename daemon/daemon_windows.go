@@ -61,3 +61,8 @@ func preStartResourceManager(daemonMode string, k8s Kubernetes) error {
 
 	return nil
 }
+
+// orphanRoutesDeleter return nil, the eni routes are not set by policy routing on windows
+func orphanRoutesDeleter() func(rulePriority, tableMin, tableMax int, liveMACs []string) ([]string, error) {
+	return nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sync"
+	"time"
 
 	"github.com/AliyunContainerService/terway/pkg/aliyun"
 	"github.com/AliyunContainerService/terway/pkg/ipam"
@@ -11,23 +13,82 @@ import (
 	"github.com/AliyunContainerService/terway/pkg/tracing"
 
 	"github.com/AliyunContainerService/terway/types"
+	corev1 "k8s.io/api/core/v1"
 )
 
 var eipLog = logger.DefaultLogger
 
+const eipRollbackTimeout = 2 * time.Minute
+
+const (
+	eipPoolName             = "eip"
+	tracingKeyMaxPoolSize   = "max_pool_size"
+	tracingKeyAllowEipRob   = "allow_eip_rob"
+	tracingKeyEIPCount      = "eip_count"
+	tracingKeyEIPPending    = "eip_pending"
+	eventReasonEIPPoolLimit = "EIPPoolLimitExceeded"
+)
+
 // eip resource manager for pod public ip address
 type eipResourceManager struct {
 	ecs         ipam.API
 	k8s         Kubernetes
 	allowEipRob bool
+
+	// maxPoolSize max count of eip created by terway on the node, 0 for unlimited
+	maxPoolSize int
+	// bandwidthPackageID the bandwidth package new eips added to, unless specified by pod
+	bandwidthPackageID string
+
+	lock sync.Mutex
+	// allocated eip created by terway and held by pod
+	allocated map[string]struct{}
+	// pending eip being created
+	pending int
 }
 
-func newEipResourceManager(e ipam.API, k Kubernetes, allowEipRob bool) ResourceManager {
-	return &eipResourceManager{
-		ecs:         e,
-		k8s:         k,
-		allowEipRob: allowEipRob,
+func newEipResourceManager(e ipam.API, k Kubernetes, allowEipRob bool, maxPoolSize int, bandwidthPackageID string, localResource map[string]resourceManagerInitItem) ResourceManager {
+	mgr := &eipResourceManager{
+		ecs:                e,
+		k8s:                k,
+		allowEipRob:        allowEipRob,
+		maxPoolSize:        maxPoolSize,
+		bandwidthPackageID: bandwidthPackageID,
+		allocated:          make(map[string]struct{}),
 	}
+	for id, res := range localResource {
+		if res.item.ExtraEipInfo != nil && res.item.ExtraEipInfo.Delete {
+			mgr.allocated[id] = struct{}{}
+		}
+	}
+	_ = tracing.Register(tracing.ResourceTypeResourcePool, eipPoolName, mgr)
+	return mgr
+}
+
+// reserve take a slot for creating eip, return the func to release the slot
+func (e *eipResourceManager) reserve() (func(eipID string), error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.maxPoolSize > 0 && len(e.allocated)+e.pending >= e.maxPoolSize {
+		msg := fmt.Sprintf("eip count on node exceed max eip pool size %d", e.maxPoolSize)
+		e.k8s.RecordNodeEvent(corev1.EventTypeWarning, eventReasonEIPPoolLimit, msg)
+		return nil, fmt.Errorf("%s", msg)
+	}
+	e.pending++
+	return func(eipID string) {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		e.pending--
+		if eipID != "" {
+			e.allocated[eipID] = struct{}{}
+		}
+	}, nil
+}
+
+func (e *eipResourceManager) forget(eipID string) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.allocated, eipID)
 }
 
 func (e *eipResourceManager) Allocate(context *networkContext, prefer string) (types.NetworkResource, error) {
@@ -63,8 +124,23 @@ func (e *eipResourceManager) Allocate(context *networkContext, prefer string) (t
 		eipLog.Infof("eip id empty pod")
 		eipID = prefer
 	}
+	created := ""
+	if eipID == "" {
+		// new eip will be created
+		done, err := e.reserve()
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			done(created)
+		}()
+	}
+	bandwidthPackageID := context.pod.EipInfo.PodEipBandwidthPackageID
+	if bandwidthPackageID == "" {
+		bandwidthPackageID = e.bandwidthPackageID
+	}
 	eipInfo, err := e.ecs.AllocateEipAddress(ctx, context.pod.EipInfo.PodEipBandWidth, context.pod.EipInfo.PodEipChargeType,
-		eipID, eniID, eniIP, e.allowEipRob, context.pod.EipInfo.PodEipISP, context.pod.EipInfo.PodEipBandwidthPackageID, context.pod.EipInfo.PodEipPoolID)
+		eipID, eniID, eniIP, e.allowEipRob, context.pod.EipInfo.PodEipISP, bandwidthPackageID, context.pod.EipInfo.PodEipPoolID)
 	if err != nil {
 		return nil, fmt.Errorf("error allocate eip info: %w", err)
 	}
@@ -73,23 +149,41 @@ func (e *eipResourceManager) Allocate(context *networkContext, prefer string) (t
 	if context.pod.EipInfo.PodEipID == "" {
 		eipInfo.Delete = true
 	}
+
+	// wait eip associated, so cni will not config the pod network before eip is ready
+	err = e.ecs.WaitEipAssociated(ctx, eipInfo.ID, eniID)
+	if err != nil {
+		e.rollback(eipInfo, eniID, eniIP)
+		return nil, fmt.Errorf("timeout waiting eip %s associate to %s %s: %w", eipInfo.ID, eniID, eniIP, err)
+	}
+
 	context.pod.EipInfo.PodEipIP = eipInfo.Address.String()
 	err = e.k8s.PatchEipInfo(context.pod)
 	if err != nil {
-		var err1 error
-		if eipInfo.Delete {
-			err1 = e.ecs.ReleaseEipAddress(ctx, eipInfo.ID, eniID, eniIP)
-		} else {
-			err1 = e.ecs.UnassociateEipAddress(ctx, eipInfo.ID, eniID, eniIP.String())
-		}
-		if err1 != nil {
-			eipLog.Errorf("error rollback eip: %v", err1)
-		}
+		e.rollback(eipInfo, eniID, eniIP)
 		return nil, fmt.Errorf("error patch pod info: %w", err)
 	}
+	if eipInfo.Delete {
+		created = eipInfo.ID
+	}
 	return eipInfo, nil
 }
 
+// rollback release or unassociate the eip, use a new ctx as the AllocIP ctx may already exceeded
+func (e *eipResourceManager) rollback(eipInfo *types.EIP, eniID string, eniIP net.IP) {
+	ctx, cancel := context.WithTimeout(context.Background(), eipRollbackTimeout)
+	defer cancel()
+	var err error
+	if eipInfo.Delete {
+		err = e.ecs.ReleaseEipAddress(ctx, eipInfo.ID, eniID, eniIP)
+	} else {
+		err = e.ecs.UnassociateEipAddress(ctx, eipInfo.ID, eniID, eniIP.String())
+	}
+	if err != nil {
+		eipLog.Errorf("error rollback eip: %v", err)
+	}
+}
+
 func (e *eipResourceManager) Release(context *networkContext, resItem types.ResourceItem) error {
 	if resItem.ExtraEipInfo == nil {
 		return nil
@@ -102,6 +196,7 @@ func (e *eipResourceManager) Release(context *networkContext, resItem types.Reso
 		if err != nil {
 			return err
 		}
+		e.forget(resItem.ID)
 	} else {
 		err := e.ecs.UnassociateEipAddress(ctx, resItem.ID, resItem.ExtraEipInfo.AssociateENI, resItem.ExtraEipInfo.AssociateENIIP.String())
 		if err != nil {
@@ -111,7 +206,8 @@ func (e *eipResourceManager) Release(context *networkContext, resItem types.Reso
 	return nil
 }
 
-func (e *eipResourceManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) error {
+func (e *eipResourceManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) (int, error) {
+	released := 0
 	for expireRes, expireItem := range expireResSet {
 		if expireItem.ExtraEipInfo == nil {
 			continue
@@ -120,16 +216,18 @@ func (e *eipResourceManager) GarbageCollection(inUseResSet map[string]types.Reso
 		if expireItem.ExtraEipInfo.Delete {
 			err := e.ecs.ReleaseEipAddress(context.Background(), expireRes, expireItem.ExtraEipInfo.AssociateENI, expireItem.ExtraEipInfo.AssociateENIIP)
 			if err != nil {
-				return err
+				return released, &resourceGCError{resID: expireRes, err: err}
 			}
+			e.forget(expireRes)
 		} else {
 			err := e.ecs.UnassociateEipAddress(context.Background(), expireRes, expireItem.ExtraEipInfo.AssociateENI, expireItem.ExtraEipInfo.AssociateENIIP.String())
 			if err != nil {
-				return err
+				return released, &resourceGCError{resID: expireRes, err: err}
 			}
 		}
+		released++
 	}
-	return nil
+	return released, nil
 }
 
 func (e *eipResourceManager) Stat(context *networkContext, resID string) (types.NetworkResource, error) {
@@ -139,3 +237,25 @@ func (e *eipResourceManager) Stat(context *networkContext, resID string) (types.
 func (e *eipResourceManager) GetResourceMapping() (tracing.ResourcePoolStats, error) {
 	return nil, fmt.Errorf("eip resource manager store network resource")
 }
+
+func (e *eipResourceManager) Config() []tracing.MapKeyValueEntry {
+	return []tracing.MapKeyValueEntry{
+		{Key: tracingKeyName, Value: eipPoolName},
+		{Key: tracingKeyMaxPoolSize, Value: fmt.Sprint(e.maxPoolSize)},
+		{Key: tracingKeyAllowEipRob, Value: fmt.Sprint(e.allowEipRob)},
+	}
+}
+
+func (e *eipResourceManager) Trace() []tracing.MapKeyValueEntry {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return []tracing.MapKeyValueEntry{
+		{Key: tracingKeyEIPCount, Value: fmt.Sprint(len(e.allocated))},
+		{Key: tracingKeyEIPPending, Value: fmt.Sprint(e.pending)},
+	}
+}
+
+func (e *eipResourceManager) Execute(cmd string, _ []string, message chan<- string) {
+	message <- "can't recognize command\n"
+	close(message)
+}
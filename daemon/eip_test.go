@@ -0,0 +1,127 @@
+package daemon
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/pkg/tracing"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_eipResourceManager_reserve(t *testing.T) {
+	k8s := &eventRecorderK8s{}
+	mgr := newEipResourceManager(nil, k8s, false, 2, "", map[string]resourceManagerInitItem{
+		"eip-1": {item: types.ResourceItem{Type: types.ResourceTypeEIP, ID: "eip-1", ExtraEipInfo: &types.ExtraEipInfo{Delete: true}}},
+		"eip-2": {item: types.ResourceItem{Type: types.ResourceTypeEIP, ID: "eip-2", ExtraEipInfo: &types.ExtraEipInfo{Delete: false}}},
+	}).(*eipResourceManager)
+	defer tracing.Unregister(tracing.ResourceTypeResourcePool, eipPoolName)
+
+	done, err := mgr.reserve()
+	assert.NoError(t, err)
+
+	_, err = mgr.reserve()
+	assert.Error(t, err)
+	assert.Equal(t, []string{eventReasonEIPPoolLimit}, k8s.events)
+
+	// failed to create, slot is released
+	done("")
+	done, err = mgr.reserve()
+	assert.NoError(t, err)
+	done("eip-3")
+	assert.Equal(t, []tracing.MapKeyValueEntry{
+		{Key: tracingKeyEIPCount, Value: "2"},
+		{Key: tracingKeyEIPPending, Value: "0"},
+	}, mgr.Trace())
+
+	mgr.forget("eip-1")
+	_, err = mgr.reserve()
+	assert.NoError(t, err)
+}
+
+func Test_eipInStickTime(t *testing.T) {
+	res := types.PodResources{
+		PodInfo: &types.PodInfo{Namespace: "default", Name: "foo"},
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "00:00:00:00:00:01.192.168.0.1"},
+			{Type: types.ResourceTypeEIP, ID: "eip-1"},
+		},
+	}
+
+	n := &networkService{}
+	assert.False(t, n.eipInStickTime("default/foo", &res))
+	assert.True(t, res.EIPStickUntil.IsZero())
+
+	n.eipStickTime = time.Minute
+	assert.True(t, n.eipInStickTime("default/foo", &res))
+	assert.False(t, res.EIPStickUntil.IsZero())
+	assert.False(t, n.eipInStickTime("default/bar", &types.PodResources{Resources: res.Resources[:1]}))
+
+	res.EIPStickUntil = time.Now().Add(-time.Second)
+	assert.False(t, n.eipInStickTime("default/foo", &res))
+
+	// the metadata of the record is kept with the eip
+	containerID := "c1"
+	res.ContainerID = &containerID
+	kept := stickEIPResources(res)
+	assert.Equal(t, []types.ResourceItem{{Type: types.ResourceTypeEIP, ID: "eip-1"}}, kept.Resources)
+	assert.Equal(t, res.PodInfo, kept.PodInfo)
+	assert.Equal(t, res.ContainerID, kept.ContainerID)
+	assert.Equal(t, res.EIPStickUntil, kept.EIPStickUntil)
+}
+
+type gcRecordManager struct {
+	ResourceManager
+	inUse  map[string]types.ResourceItem
+	expire map[string]types.ResourceItem
+}
+
+func (m *gcRecordManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) (int, error) {
+	m.inUse, m.expire = inUseResSet, expireResSet
+	return len(expireResSet), nil
+}
+
+func Test_networkService_gcStickEIP(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	eniIPMgr, eipMgr := &gcRecordManager{}, &gcRecordManager{}
+	n := &networkService{k8s: &fakeK8s{}, resourceDB: db, eipStickTime: time.Minute}
+	n.setResourceManagers(map[string]ResourceManager{
+		types.ResourceTypeENIIP: eniIPMgr,
+		types.ResourceTypeEIP:   eipMgr,
+	})
+	res := types.PodResources{
+		PodInfo: &types.PodInfo{Namespace: "default", Name: "foo"},
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1", ENIID: "eni-1", IPv4: "192.168.0.1"},
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.2", ENIID: "eni-1", IPv4: "192.168.0.2"},
+			{Type: types.ResourceTypeEIP, ID: "eip-1", ExtraEipInfo: &types.ExtraEipInfo{AssociateENI: "eni-1", AssociateENIIP: net.ParseIP("192.168.0.1")}},
+		},
+	}
+	assert.NoError(t, db.Put("default/foo", res))
+
+	_, err := n.gc()
+	assert.NoError(t, err)
+	// the eniip associated with the sticky eip is kept in use
+	assert.Contains(t, eipMgr.inUse, "eip-1")
+	assert.Contains(t, eniIPMgr.inUse, "mac-1.192.168.0.1")
+	assert.NotContains(t, eniIPMgr.expire, "mac-1.192.168.0.1")
+	assert.Contains(t, eniIPMgr.expire, "mac-1.192.168.0.2")
+
+	obj, err := db.Get("default/foo")
+	assert.NoError(t, err)
+	kept := obj.(types.PodResources)
+	assert.Equal(t, []types.ResourceItem{res.Resources[0], res.Resources[2]}, kept.Resources)
+	// the stick time is stored in the record, so that it is not reset by restart
+	assert.False(t, kept.EIPStickUntil.IsZero())
+
+	// both released after the stick time
+	kept.EIPStickUntil = time.Now().Add(-time.Second)
+	assert.NoError(t, db.Put("default/foo", kept))
+	_, err = n.gc()
+	assert.NoError(t, err)
+	assert.Contains(t, eipMgr.expire, "eip-1")
+	assert.Contains(t, eniIPMgr.expire, "mac-1.192.168.0.1")
+}
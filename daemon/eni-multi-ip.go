@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"sort"
 	"strings"
 	"sync"
@@ -47,6 +48,12 @@ var eniIPLog = logger.DefaultLogger
 const (
 	maxEniOperating = 3
 	maxIPBacklog    = 10
+
+	// maxReservedIPRetry times a request is put back to the backlog when ecs assigned a reserved ip for it
+	maxReservedIPRetry = 3
+
+	// ipPerPrefix ip count of the /28 ipv4 prefix
+	ipPerPrefix = 16
 )
 
 const (
@@ -66,21 +73,25 @@ const (
 const timeFormat = "2006-01-02 15:04:05"
 
 type eniIPFactory struct {
-	name         string
-	enableTrunk  bool
-	trunkOnEni   string
-	eniFactory   *eniFactory
-	enis         []*ENI
-	maxENI       chan struct{}
-	eniMaxIP     int
-	eniOperChan  chan struct{}
-	ipResultChan chan *ENIIP
+	name        string
+	enableTrunk bool
+	trunkOnEni  string
+	eniFactory  *eniFactory
+	enis        []*ENI
+	maxENI      chan struct{}
+	eniMaxIP    int
+	eniOperChan chan struct{}
 	sync.RWMutex
 	// metrics
 	metricENICount            prometheus.Gauge
 	disableSecurityGroupCheck bool
 
 	ipFamily *types.IPFamily
+
+	enablePrefixDelegation bool
+
+	// reservedIPs ips should not be handed out to pod
+	reservedIPs map[string]struct{}
 }
 
 // ENIIP the secondary ip of eni
@@ -89,55 +100,461 @@ type ENIIP struct {
 	err error
 }
 
+// ipRequest the request of an ip on eni, the result is sent back to the channel of the caller
+type ipRequest struct {
+	// retry the times the request retried for reserved ip
+	retry  int
+	result chan<- *ENIIP
+}
+
 // ENI to hold ENI's secondary config
 type ENI struct {
 	lock sync.Mutex
 	*types.ENI
-	ips       []*ENIIP
-	pending   int
-	ipBacklog chan struct{}
+	ips     []*ENIIP
+	pending int
+	// ipBacklog the pending requests
+	ipBacklog chan ipRequest
 	ecs       ipam.API
 	done      chan struct{}
 	// Unix timestamp to mark when this ENI can allocate Pod IP.
 	ipAllocInhibitExpireAt time.Time
+
+	// prefixDelegation allocate ip from the delegated ipv4 prefix
+	prefixDelegation bool
+	prefixes         []*net.IPNet
+	// prefixFree ips carved from the prefixes but not hold by pool
+	prefixFree []net.IP
+	// dualStack the ip carved from prefix is paired with a secondary ipv6
+	dualStack bool
+
+	// reservedIPs ips should not be handed out to pod
+	reservedIPs map[string]struct{}
+
+	// initRequests the requests satisfied by the ips created with the eni
+	initRequests []ipRequest
 }
 
 func (e *ENI) getIPCountLocked() int {
 	return e.pending + len(e.ips)
 }
 
+// canAssignLocked return true if one more ip can be requested on the eni. a delegated prefix takes only
+// one slot of the secondary ip limit of eni, so the ips carved from prefixes are counted by prefix
+func (e *ENI) canAssignLocked(maxIP int) bool {
+	if !e.prefixDelegation {
+		return e.getIPCountLocked() < maxIP
+	}
+	lack := e.pending + 1 - len(e.prefixFree)
+	if lack <= 0 {
+		return true
+	}
+	slots := len(e.prefixes) + (lack+ipPerPrefix-1)/ipPerPrefix
+	for _, ip := range e.ips {
+		if ip.Prefix == nil {
+			slots++
+		}
+	}
+	return slots <= maxIP
+}
+
+// prefixOfLocked return the delegated prefix which contains the ip
+func (e *ENI) prefixOfLocked(ip net.IP) *net.IPNet {
+	for _, prefix := range e.prefixes {
+		if prefix.Contains(ip) {
+			return prefix
+		}
+	}
+	return nil
+}
+
+// prefixHostsLocked return the ips of the prefix could be handed out to pod
+func (e *ENI) prefixHostsLocked(prefix *net.IPNet) []net.IP {
+	var hosts []net.IP
+	for _, ip := range terwayIP.IPNetHosts(prefix) {
+		if e.isReserved(ip) {
+			continue
+		}
+		hosts = append(hosts, ip)
+	}
+	return hosts
+}
+
+// releasePrefixLocked remove the prefix from eni if all the ips of it are free, return true if removed
+func (e *ENI) releasePrefixLocked(prefix *net.IPNet) bool {
+	var free, rest []net.IP
+	for _, ip := range e.prefixFree {
+		if prefix.Contains(ip) {
+			free = append(free, ip)
+		} else {
+			rest = append(rest, ip)
+		}
+	}
+	if len(free) < len(e.prefixHostsLocked(prefix)) {
+		return false
+	}
+	e.prefixFree = rest
+	for i, p := range e.prefixes {
+		if p.String() == prefix.String() {
+			e.prefixes = append(e.prefixes[:i], e.prefixes[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// restorePrefixLocked put the prefix failed to unassign back to eni with all ips free
+func (e *ENI) restorePrefixLocked(prefix *net.IPNet) {
+	e.prefixes = append(e.prefixes, prefix)
+	e.prefixFree = append(e.prefixFree, e.prefixHostsLocked(prefix)...)
+}
+
+// allocateFromPrefix carve ips from the delegated prefixes, new prefixes is assigned when free ips is not enough
+func (e *ENI) allocateFromPrefix(count int) ([]*types.ENIIP, error) {
+	e.lock.Lock()
+	lack := count - len(e.prefixFree)
+	e.lock.Unlock()
+
+	if lack > 0 {
+		prefixes, err := e.ecs.AssignIPv4PrefixForENI(context.Background(), e.ENI.ID, e.ENI.MAC, (lack+ipPerPrefix-1)/ipPerPrefix)
+		e.lock.Lock()
+		for _, prefix := range prefixes {
+			e.prefixes = append(e.prefixes, prefix)
+			// the prefix is inside the vswitch cidr, so every ip of it is a host address for pod
+			e.prefixFree = append(e.prefixFree, e.prefixHostsLocked(prefix)...)
+		}
+		e.lock.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	e.lock.Lock()
+	if len(e.prefixFree) < count {
+		e.lock.Unlock()
+		return nil, fmt.Errorf("not enough ip in prefix, want %d got %d", count, len(e.prefixFree))
+	}
+	var result []*types.ENIIP
+	for _, ip := range e.prefixFree[:count] {
+		result = append(result, &types.ENIIP{
+			ENI:    e.ENI,
+			IPSet:  types.IPSet{IPv4: ip},
+			Prefix: e.prefixOfLocked(ip),
+		})
+	}
+	e.prefixFree = e.prefixFree[count:]
+	e.lock.Unlock()
+	if !e.dualStack {
+		return result, nil
+	}
+
+	// the prefix is ipv4 only, never hand out ip with only one family in dual stack
+	ipv6s, err := e.ecs.AssignIPv6ForENI(context.Background(), e.ENI.ID, e.ENI.MAC, count)
+	if err != nil {
+		e.lock.Lock()
+		for _, ip := range result {
+			e.prefixFree = append(e.prefixFree, ip.IPSet.IPv4)
+		}
+		e.lock.Unlock()
+		return nil, fmt.Errorf("error assign ipv6 for the ips carved from prefix, %w", err)
+	}
+	for i, ip := range result {
+		ip.IPSet.IPv6 = ipv6s[i]
+	}
+	return result, nil
+}
+
+// restoreIPs rebuild the ips of the eni from the ips on it and the ones allocated to pod, the ips carved
+// from prefix not in use are kept in prefixFree instead of pool. the reserved ips not used by pod are returned
+func (e *ENI) restoreIPs(ipv4s, ipv6s []net.IP, ipFamily *types.IPFamily, allocatedResources map[string]resourceManagerInitItem, holder pool.ResourceHolder) []types.IPSet {
+	// ipv4s are the secondary ips, the ips carved from prefix are ipv4 only, never merge them with the secondary ipv6
+	var carved []net.IP
+	for _, prefix := range e.prefixes {
+		carved = append(carved, terwayIP.IPNetHosts(prefix)...)
+	}
+	inUse := make(map[string]struct{})
+	var reserved []types.IPSet
+	if ipFamily.IPv4 && !ipFamily.IPv6 {
+		for _, ip := range ipv4s {
+			eniIP := &types.ENIIP{
+				ENI:   e.ENI,
+				IPSet: types.IPSet{IPv4: ip},
+			}
+			res, ok := allocatedResources[eniIP.GetResourceID()]
+			if e.isReserved(ip) {
+				if !ok {
+					reserved = append(reserved, eniIP.IPSet)
+					continue
+				}
+				eniIPLog.Warnf("reserved ip %s is used by pod %s/%s", ip, res.podInfo.Namespace, res.podInfo.Name)
+			}
+
+			e.ips = append(e.ips, &ENIIP{
+				ENIIP: eniIP,
+			})
+			if !ok {
+				holder.AddIdle(eniIP)
+			} else {
+				holder.AddInuse(eniIP, podInfoKey(res.podInfo.Namespace, res.podInfo.Name))
+			}
+		}
+		for _, ip := range carved {
+			eniIP := &types.ENIIP{
+				ENI:    e.ENI,
+				IPSet:  types.IPSet{IPv4: ip},
+				Prefix: e.prefixOfLocked(ip),
+			}
+			res, ok := allocatedResources[eniIP.GetResourceID()]
+			if !ok {
+				continue
+			}
+			if e.isReserved(ip) {
+				eniIPLog.Warnf("reserved ip %s is used by pod %s/%s", ip, res.podInfo.Namespace, res.podInfo.Name)
+			}
+			e.ips = append(e.ips, &ENIIP{
+				ENIIP: eniIP,
+			})
+			holder.AddInuse(eniIP, podInfoKey(res.podInfo.Namespace, res.podInfo.Name))
+			inUse[ip.String()] = struct{}{}
+		}
+	} else {
+		v4Map := terwayIP.ToIPMap(ipv4s)
+		v6Map := terwayIP.ToIPMap(ipv6s)
+
+		// put all local res in
+		for id, res := range allocatedResources {
+			if res.item.ENIMAC != e.MAC {
+				continue
+			}
+			ipSet := types.IPSet{}
+			ipSet.SetIP(res.item.IPv4).SetIP(res.item.IPv6)
+			eniIP := &types.ENIIP{
+				ENI:    e.ENI,
+				IPSet:  ipSet,
+				Prefix: e.prefixOfLocked(ipSet.IPv4),
+			}
+
+			e.ips = append(e.ips, &ENIIP{
+				ENIIP: eniIP,
+			})
+			holder.AddInuse(eniIP, podInfoKey(res.podInfo.Namespace, res.podInfo.Name))
+
+			if ipSet.IPv4 != nil {
+				delete(v4Map, ipSet.IPv4.String())
+				inUse[ipSet.IPv4.String()] = struct{}{}
+			}
+			if ipSet.IPv6 != nil {
+				delete(v6Map, ipSet.IPv6.String())
+			}
+			delete(allocatedResources, id)
+		}
+		var v4List, v6List []net.IP
+		for _, v4 := range v4Map {
+			v4List = append(v4List, v4)
+		}
+		for _, v6 := range v6Map {
+			v6List = append(v6List, v6)
+		}
+		for _, unUsed := range types.MergeIPs(v4List, v6List) {
+			if e.isReserved(unUsed.IPv4) || e.isReserved(unUsed.IPv6) {
+				reserved = append(reserved, unUsed)
+				continue
+			}
+			eniIP := &types.ENIIP{
+				ENI:   e.ENI,
+				IPSet: unUsed,
+			}
+			e.ips = append(e.ips, &ENIIP{
+				ENIIP: eniIP,
+			})
+
+			if ipFamily.IPv4 && ipFamily.IPv6 && (unUsed.IPv6 == nil || unUsed.IPv4 == nil) {
+				holder.AddInvalid(eniIP)
+			} else {
+				holder.AddIdle(eniIP)
+			}
+		}
+	}
+
+	// the reserved ips of prefix are never handed out, they are unassigned with the prefix
+	for _, prefix := range e.prefixes {
+		for _, ip := range e.prefixHostsLocked(prefix) {
+			if _, ok := inUse[ip.String()]; !ok {
+				e.prefixFree = append(e.prefixFree, ip)
+			}
+		}
+	}
+	return reserved
+}
+
+func (f *eniIPFactory) isReserved(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	_, ok := f.reservedIPs[ip.String()]
+	return ok
+}
+
+func (e *ENI) isReserved(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	_, ok := e.reservedIPs[ip.String()]
+	return ok
+}
+
+// releaseReservedIPs unassign the reserved ips from eni and return the others
+func (e *ENI) releaseReservedIPs(ips []types.IPSet) []types.IPSet {
+	if len(e.reservedIPs) == 0 {
+		return ips
+	}
+	var kept []types.IPSet
+	var v4s, v6s []net.IP
+	for _, ip := range ips {
+		if !e.isReserved(ip.IPv4) && !e.isReserved(ip.IPv6) {
+			kept = append(kept, ip)
+			continue
+		}
+		eniIPLog.Warnf("ip %s is reserved, release it from eni %s", ip.String(), e.ENI.ID)
+		if ip.IPv4 != nil {
+			v4s = append(v4s, ip.IPv4)
+		}
+		if ip.IPv6 != nil {
+			v6s = append(v6s, ip.IPv6)
+		}
+	}
+	if len(v4s) > 0 || len(v6s) > 0 {
+		err := e.ecs.UnAssignIPsForENI(context.Background(), e.ENI.ID, e.ENI.MAC, v4s, v6s)
+		if err != nil {
+			eniIPLog.Errorf("error release reserved ips %v %v from eni %s, %v", v4s, v6s, e.ENI.ID, err)
+		}
+	}
+	return kept
+}
+
+// shortfall fail the requests not satisfied when ecs assigned fewer ips than requested,
+// the ips assigned are still used by the other requests
+func (e *ENI) shortfall(requests []ipRequest, assigned int) {
+	if assigned >= len(requests) {
+		return
+	}
+	msg := fmt.Sprintf("only %d of %d ips assigned for eni %s", assigned, len(requests), e.ENI.ID)
+	eniIPLog.Warn(msg)
+	metric.ENIIPFactoryIPAllocCount.WithLabelValues(e.MAC, metric.ENIIPAllocActionShortfall).Add(float64(len(requests) - assigned))
+	_ = tracing.RecordNodeEvent(corev1.EventTypeWarning, "ENIIPShortfall", msg)
+	for _, req := range requests[assigned:] {
+		req.result <- &ENIIP{
+			ENIIP: &types.ENIIP{
+				ENI: e.ENI,
+			},
+			err: errors.Errorf("error assign ip for ENI: %s", msg),
+		}
+	}
+}
+
+// requestMore put the requests back to the backlog, to allocate other ips instead of the reserved ones.
+// the request exceeds maxReservedIPRetry is failed.
+func (e *ENI) requestMore(requests []ipRequest) {
+	for _, req := range requests {
+		req.retry++
+		if req.retry <= maxReservedIPRetry {
+			select {
+			case e.ipBacklog <- req:
+				continue
+			default:
+			}
+		}
+		metric.ENIIPFactoryIPAllocCount.WithLabelValues(e.MAC, metric.ENIIPAllocActionFail).Inc()
+		req.result <- &ENIIP{
+			ENIIP: &types.ENIIP{
+				ENI: e.ENI,
+			},
+			err: errors.Errorf("error assign ip for ENI: only reserved ip assigned after %d retries", req.retry-1),
+		}
+	}
+}
+
+// deliver hand out the ips to the requests, the requests retried most are satisfied first, the requests
+// left for the reserved ips released retry with other ips, and the others fail
+func (e *ENI) deliver(requests []ipRequest, assigned int, kept []types.IPSet) {
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].retry > requests[j].retry
+	})
+	for i, ip := range kept {
+		if i >= len(requests) {
+			break
+		}
+		requests[i].result <- &ENIIP{
+			ENIIP: &types.ENIIP{
+				ENI:   e.ENI,
+				IPSet: ip,
+			},
+			err: nil,
+		}
+	}
+	if len(kept) < assigned && len(kept) < len(requests) {
+		end := assigned
+		if end > len(requests) {
+			end = len(requests)
+		}
+		e.requestMore(requests[len(kept):end])
+	}
+	e.shortfall(requests, assigned)
+}
+
 // eni ip allocator
-func (e *ENI) allocateWorker(resultChan chan<- *ENIIP) {
+func (e *ENI) allocateWorker() {
 	for {
-		toAllocate := 0
+		var requests []ipRequest
 		select {
 		case <-e.done:
 			return
-		case <-e.ipBacklog:
-			toAllocate = 1
+		case req := <-e.ipBacklog:
+			requests = append(requests, req)
 		}
 		// wait 300ms for aggregation the cni request
 		time.Sleep(300 * time.Millisecond)
 	popAll:
 		for {
 			select {
-			case <-e.ipBacklog:
-				toAllocate++
+			case req := <-e.ipBacklog:
+				requests = append(requests, req)
 			default:
 				break popAll
 			}
-			if toAllocate >= maxIPBacklog {
+			if len(requests) >= maxIPBacklog {
 				break
 			}
 		}
+		toAllocate := len(requests)
 		eniIPLog.Debugf("allocate %v ips for eni", toAllocate)
+		if e.prefixDelegation {
+			ips, err := e.allocateFromPrefix(toAllocate)
+			if err == nil {
+				metric.ENIIPFactoryIPAllocCount.WithLabelValues(e.MAC, metric.ENIIPAllocActionSucceed).Add(float64(toAllocate))
+				for i, ip := range ips {
+					requests[i].result <- &ENIIP{
+						ENIIP: ip,
+						err:   nil,
+					}
+				}
+				continue
+			}
+			eniIPLog.Warnf("error allocate ips from prefix for eni %s, fall back to secondary ip: %v", e.ENI.ID, err)
+			// the request is rejected, eg: the instance type not support prefix
+			if apiErr.ErrStatusCodeAssert(http.StatusBadRequest, err) || apiErr.ErrStatusCodeAssert(http.StatusForbidden, err) {
+				e.lock.Lock()
+				e.prefixDelegation = false
+				e.lock.Unlock()
+			}
+		}
 		v4, v6, err := e.ecs.AssignNIPsForENI(context.Background(), e.ENI.ID, e.ENI.MAC, toAllocate)
 		eniIPLog.Debugf("allocated ips for eni: eni = %+v, v4 = %+v,v6 = %+v, err = %v", e.ENI, v4, v6, err)
 		if err != nil {
 			eniIPLog.Errorf("error allocate ips for eni: %v", err)
 			metric.ENIIPFactoryIPAllocCount.WithLabelValues(e.MAC, metric.ENIIPAllocActionFail).Add(float64(toAllocate))
-			for i := 0; i < toAllocate; i++ {
-				resultChan <- &ENIIP{
+			for _, req := range requests {
+				req.result <- &ENIIP{
 					ENIIP: &types.ENIIP{
 						ENI: e.ENI,
 					},
@@ -145,16 +562,9 @@ func (e *ENI) allocateWorker(resultChan chan<- *ENIIP) {
 				}
 			}
 		} else {
-			metric.ENIIPFactoryIPAllocCount.WithLabelValues(e.MAC, metric.ENIIPAllocActionSucceed).Add(float64(toAllocate))
-			for _, ip := range types.MergeIPs(v4, v6) {
-				resultChan <- &ENIIP{
-					ENIIP: &types.ENIIP{
-						ENI:   e.ENI,
-						IPSet: ip,
-					},
-					err: nil,
-				}
-			}
+			ips := types.MergeIPs(v4, v6)
+			metric.ENIIPFactoryIPAllocCount.WithLabelValues(e.MAC, metric.ENIIPAllocActionSucceed).Add(float64(len(ips)))
+			e.deliver(requests, len(ips), e.releaseReservedIPs(ips))
 		}
 	}
 }
@@ -213,7 +623,19 @@ func (f *eniIPFactory) getEnis(ctx *AllocCtx) ([]*ENI, error) {
 
 }
 
-func (f *eniIPFactory) submit(ctx *AllocCtx) error {
+// submit request an ip on the existing enis, only the enis on vSwitch are considered if vSwitch is not empty,
+// the result is sent to resultChan
+func (f *eniIPFactory) submit(ctx *AllocCtx, vSwitch string, resultChan chan<- *ENIIP) error {
+	if vSwitch == "" {
+		return f.submitMatch(ctx, nil, resultChan)
+	}
+	return f.submitMatch(ctx, func(eni *types.ENI) bool {
+		return eni.VSwitchID == vSwitch
+	}, resultChan)
+}
+
+// submitMatch request an ip on the existing enis, only the enis matched are considered if match is not nil
+func (f *eniIPFactory) submitMatch(ctx *AllocCtx, match func(eni *types.ENI) bool, resultChan chan<- *ENIIP) error {
 	f.Lock()
 	defer f.Unlock()
 	var enis []*ENI
@@ -221,6 +643,10 @@ func (f *eniIPFactory) submit(ctx *AllocCtx) error {
 	for _, eni := range enis {
 		eniIPLog.Infof("check existing eni: %+v", eni)
 		eni.lock.Lock()
+		if match != nil && (eni.ENI == nil || !match(eni.ENI)) {
+			eni.lock.Unlock()
+			continue
+		}
 		now := time.Now()
 		if eni.ENI != nil {
 			eniIPLog.Infof("check if the current eni is in the time window for IP allocation inhibition: "+
@@ -239,9 +665,9 @@ func (f *eniIPFactory) submit(ctx *AllocCtx) error {
 
 		eniIPLog.Debugf("check if the current eni will reach eni IP quota with new pending IP added: "+
 			"eni = %+v, eni.pending = %d, len(eni.ips) = %d, eni.MaxIPs = %d", eni, eni.pending, len(eni.ips), f.eniMaxIP)
-		if eni.getIPCountLocked() < f.eniMaxIP {
+		if eni.canAssignLocked(f.eniMaxIP) {
 			select {
-			case eni.ipBacklog <- struct{}{}:
+			case eni.ipBacklog <- ipRequest{result: resultChan}:
 			default:
 				eni.lock.Unlock()
 				continue
@@ -256,8 +682,8 @@ func (f *eniIPFactory) submit(ctx *AllocCtx) error {
 	return errors.Errorf("trigger ENIIP throttle, max operating concurrent: %v", maxIPBacklog)
 }
 
-func (f *eniIPFactory) popResult() (ip *types.ENIIP, err error) {
-	result := <-f.ipResultChan
+func (f *eniIPFactory) popResult(resultChan <-chan *ENIIP) (ip *types.ENIIP, err error) {
+	result := <-resultChan
 	eniIPLog.Debugf("pop result from resultChan: %+v", result)
 	if result.ENIIP == nil || result.err != nil {
 		// There are two error cases:
@@ -300,12 +726,19 @@ func (f *eniIPFactory) popResult() (ip *types.ENIIP, err error) {
 }
 
 func (f *eniIPFactory) Create(count int) ([]types.NetworkResource, error) {
+	return f.CreateOnVSwitch(count, "")
+}
+
+// CreateOnVSwitch create ips on the enis of vSwitch, any eni is used if vSwitch is empty
+func (f *eniIPFactory) CreateOnVSwitch(count int, vSwitch string) ([]types.NetworkResource, error) {
 	ctx := &AllocCtx{}
 	var (
 		ipResult []types.NetworkResource
 		err      error
 		waiting  int
 	)
+	// each call has its own channel, so the ips on vSwitch never go to others
+	resultChan := make(chan *ENIIP, count)
 	defer func() {
 		if len(ipResult) == 0 {
 			eniIPLog.Debugf("create result: %v, error: %v", ipResult, err)
@@ -318,7 +751,7 @@ func (f *eniIPFactory) Create(count int) ([]types.NetworkResource, error) {
 
 	// find for available ENIs and submit for ip allocation
 	for ; waiting < count; waiting++ {
-		err = f.submit(ctx)
+		err = f.submit(ctx, vSwitch, resultChan)
 		if err != nil {
 			break
 		}
@@ -335,7 +768,7 @@ func (f *eniIPFactory) Create(count int) ([]types.NetworkResource, error) {
 	}
 	if initENIIPCount > 0 {
 		eniIPLog.Debugf("create eni async, ip count: %+v", initENIIPCount)
-		_, err = f.createENIAsync(initENIIPCount)
+		_, err = f.createENIAsync(initENIIPCount, vSwitch, resultChan)
 		if err == nil {
 			waiting += initENIIPCount
 		} else {
@@ -348,9 +781,39 @@ func (f *eniIPFactory) Create(count int) ([]types.NetworkResource, error) {
 		return ipResult, errors.Errorf("error submit ip create request: %v,%s", err, ctx.String())
 	}
 
-	var ip *types.ENIIP
+	ipResult, err = f.popResults(waiting, resultChan)
+	return ipResult, err
+}
+
+// CreateOnExistingENI create ips only on the existing enis matched, no eni is created
+func (f *eniIPFactory) CreateOnExistingENI(count int, match func(eni *types.ENI) bool) ([]types.NetworkResource, error) {
+	ctx := &AllocCtx{}
+	var (
+		err     error
+		waiting int
+	)
+	resultChan := make(chan *ENIIP, count)
+	for ; waiting < count; waiting++ {
+		err = f.submitMatch(ctx, match, resultChan)
+		if err != nil {
+			break
+		}
+	}
+	if waiting == 0 {
+		return nil, errors.Errorf("no matched eni has capacity: %v,%s", err, ctx.String())
+	}
+	return f.popResults(waiting, resultChan)
+}
+
+// popResults receive the allocate results of waiting ips submitted
+func (f *eniIPFactory) popResults(waiting int, resultChan <-chan *ENIIP) ([]types.NetworkResource, error) {
+	var (
+		ipResult []types.NetworkResource
+		ip       *types.ENIIP
+		err      error
+	)
 	for ; waiting > 0; waiting-- { // receive allocate result
-		ip, err = f.popResult()
+		ip, err = f.popResult(resultChan)
 		if err != nil {
 			eniIPLog.Errorf("error allocate ip address: %+v", err)
 		} else {
@@ -444,6 +907,48 @@ func (f *eniIPFactory) Dispose(res types.NetworkResource) (err error) {
 		return fmt.Errorf("ip to be release is primary ip of ENI")
 	}
 
+	// ip carved from prefix can not be unassigned alone, keep it for next allocation,
+	// the prefix is unassigned once all the ips of it are free
+	eni.lock.Lock()
+	if eni.prefixOfLocked(ip.IPSet.IPv4) != nil {
+		if ip.IPSet.IPv6 != nil {
+			// the ipv6 paired is a secondary ip
+			eni.lock.Unlock()
+			err = f.eniFactory.ecs.UnAssignIPsForENI(context.Background(), ip.ENI.ID, ip.ENI.MAC, nil, []net.IP{ip.IPSet.IPv6})
+			if err != nil {
+				return fmt.Errorf("error unassign ipv6 of eniip, %v", err)
+			}
+			eni.lock.Lock()
+		}
+		for i, e := range eni.ips {
+			if e.IPSet.IPv4.Equal(eniip.IPSet.IPv4) {
+				eni.ips[len(eni.ips)-1], eni.ips[i] = eni.ips[i], eni.ips[len(eni.ips)-1]
+				eni.ips = eni.ips[:len(eni.ips)-1]
+				break
+			}
+		}
+		if !eni.isReserved(ip.IPSet.IPv4) {
+			eni.prefixFree = append(eni.prefixFree, ip.IPSet.IPv4)
+		}
+		prefix := eni.prefixOfLocked(ip.IPSet.IPv4)
+		released := eni.releasePrefixLocked(prefix)
+		eni.lock.Unlock()
+		metric.ENIIPFactoryIPCount.WithLabelValues(f.name, eni.MAC, fmt.Sprint(f.eniMaxIP)).Dec()
+		if !released {
+			return nil
+		}
+		err = f.eniFactory.ecs.UnAssignIPv4PrefixForENI(context.Background(), ip.ENI.ID, ip.ENI.MAC, []*net.IPNet{prefix})
+		if err != nil {
+			// keep the prefix for next allocation
+			eni.lock.Lock()
+			eni.restorePrefixLocked(prefix)
+			eni.lock.Unlock()
+			eniIPLog.Warnf("error unassign free prefix %s of eni %s: %v", prefix, eni.ID, err)
+		}
+		return nil
+	}
+	eni.lock.Unlock()
+
 	var v4, v6 []net.IP
 	if ip.IPSet.IPv4 != nil {
 		v4 = append(v4, ip.IPSet.IPv4)
@@ -468,6 +973,46 @@ func (f *eniIPFactory) Dispose(res types.NetworkResource) (err error) {
 	return nil
 }
 
+// releaseIPv6 unassign the ipv6 of the eniip, which is replaced by released of ipv4 only on the eni
+func (f *eniIPFactory) releaseIPv6(eniIP, released *types.ENIIP) error {
+	err := f.eniFactory.ecs.UnAssignIPsForENI(context.Background(), eniIP.ENI.ID, eniIP.ENI.MAC, nil, []net.IP{eniIP.IPSet.IPv6})
+	if err != nil {
+		return fmt.Errorf("error unassign ipv6 %s, %w", eniIP.IPSet.IPv6, err)
+	}
+	f.replaceIP(eniIP, released)
+	return nil
+}
+
+// assignIPv6 assign new ipv6 to the eni for the eniip of ipv4 only, the eniip paired is returned
+func (f *eniIPFactory) assignIPv6(ctx context.Context, eniIP *types.ENIIP) (*types.ENIIP, error) {
+	ipv6s, err := f.eniFactory.ecs.AssignIPv6ForENI(ctx, eniIP.ENI.ID, eniIP.ENI.MAC, 1)
+	if err != nil {
+		return nil, err
+	}
+	paired := &types.ENIIP{ENI: eniIP.ENI, IPSet: types.IPSet{IPv4: eniIP.IPSet.IPv4, IPv6: ipv6s[0]}, Prefix: eniIP.Prefix}
+	f.replaceIP(eniIP, paired)
+	return paired, nil
+}
+
+// replaceIP replace the eniip old on its eni with ip
+func (f *eniIPFactory) replaceIP(old, ip *types.ENIIP) {
+	f.RLock()
+	defer f.RUnlock()
+	for _, eni := range f.enis {
+		if eni.ID != old.ENI.ID {
+			continue
+		}
+		eni.lock.Lock()
+		for i, e := range eni.ips {
+			if e.IPSet.String() == old.IPSet.String() {
+				eni.ips[i] = &ENIIP{ENIIP: ip}
+				break
+			}
+		}
+		eni.lock.Unlock()
+	}
+}
+
 // Check resource in remote
 func (f *eniIPFactory) Check(res types.NetworkResource) error {
 	eniIP, ok := res.(*types.ENIIP)
@@ -475,6 +1020,11 @@ func (f *eniIPFactory) Check(res types.NetworkResource) error {
 		return fmt.Errorf("unsupported type %T", res)
 	}
 
+	// the reserved ip must not go back to pool, report it as gone so that it is disposed
+	if f.isReserved(eniIP.IPSet.IPv4) || f.isReserved(eniIP.IPSet.IPv6) {
+		return fmt.Errorf("ip %s is reserved, %w", eniIP.IPSet.String(), apiErr.ErrNotFound)
+	}
+
 	ipv4, ipv6, err := f.eniFactory.ecs.GetENIIPs(context.Background(), eniIP.ENI.MAC)
 	if err != nil {
 		return err
@@ -484,6 +1034,20 @@ func (f *eniIPFactory) Check(res types.NetworkResource) error {
 		ipv4, ipv6 = dropPrimaryIP(eniIP.ENI, ipv4, ipv6)
 	}
 
+	if eniIP.Prefix != nil {
+		// the ip carved from prefix is not a secondary ip, it is present as long as the prefix is
+		prefixes, err := f.eniFactory.ecs.GetENIIPv4Prefixes(context.Background(), eniIP.ENI.MAC)
+		if err != nil {
+			return err
+		}
+		ipv4 = nil
+		for _, prefix := range prefixes {
+			if prefix.String() == eniIP.Prefix.String() {
+				ipv4 = []net.IP{eniIP.IPSet.IPv4}
+			}
+		}
+	}
+
 	if eniIP.IPSet.IPv4 != nil {
 		if !terwayIP.IPsIntersect([]net.IP{eniIP.IPSet.IPv4}, ipv4) {
 			return apiErr.ErrNotFound
@@ -530,6 +1094,16 @@ func (f *eniIPFactory) ListResource() (map[string]types.NetworkResource, error)
 			}
 			return nil, err
 		}
+		prefixes, err := f.eniFactory.ecs.GetENIIPv4Prefixes(ctx, mac)
+		if err != nil {
+			if errors.Is(err, apiErr.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		for _, prefix := range prefixes {
+			ipv4s = append(ipv4s, terwayIP.IPNetHosts(prefix)...)
+		}
 		ipv4Set := terwayIP.ToIPMap(ipv4s)
 		ipv6Set := terwayIP.ToIPMap(ipv6s)
 
@@ -577,12 +1151,12 @@ func (f *eniIPFactory) Reconcile() {
 	}
 }
 
-func (f *eniIPFactory) initialENI(eni *ENI, ipCount int) {
+func (f *eniIPFactory) initialENI(eni *ENI, ipCount int, vSwitch string) {
 	if utils.IsWindowsOS() {
 		// NB(thxCode): create eni with one more IP in windows at initialization.
 		ipCount++
 	}
-	rawEni, err := f.eniFactory.CreateWithIPCount(ipCount, false)
+	rawEni, err := f.eniFactory.CreateWithIPCountOnVSwitch(context.Background(), ipCount, false, vSwitch)
 	var ipv4s []net.IP
 	var ipv6s []net.IP
 	// eni operate finished
@@ -604,26 +1178,20 @@ func (f *eniIPFactory) initialENI(eni *ENI, ipCount int) {
 		} else {
 			ipv4s, ipv6s, err = f.eniFactory.ecs.GetENIIPs(context.Background(), eni.MAC)
 			if err != nil {
-				eniIPLog.Errorf("error get eni secondary address: %+v, rollback it", err)
-				errDispose := f.eniFactory.Dispose(rawEni[0])
-				if errDispose != nil {
-					eniIPLog.Errorf("rollback %+v failed", rawEni)
-				}
-				<-f.maxENI
+				err = fmt.Errorf("error get eni secondary address: %w", err)
 			}
-			if f.ipFamily.IPv4 && f.ipFamily.IPv6 {
-				if len(ipv4s) != len(ipv6s) {
-					eniIPLog.Errorf("error get eni secondary address: ipv4 ipv6 length is not equal, rollback it")
-					errDispose := f.eniFactory.Dispose(rawEni[0])
-					if errDispose != nil {
-						eniIPLog.Errorf("rollback %+v failed", rawEni)
-					}
-					<-f.maxENI
+			// never hand out ip with only one family in dual stack
+			if err == nil && f.ipFamily.IPv4 && f.ipFamily.IPv6 && len(ipv4s) != len(ipv6s) {
+				err = fmt.Errorf("error get eni secondary address: ipv4 count %d not equal to ipv6 count %d", len(ipv4s), len(ipv6s))
+			}
+			if err == nil {
+				err = f.setupENICompartment(eni.ENI)
+				if err != nil {
+					err = fmt.Errorf("error setup eni compartment after initialization: %w", err)
 				}
 			}
-			err = f.setupENICompartment(eni.ENI)
 			if err != nil {
-				eniIPLog.Errorf("error setup eni compartment after initialization: %v", err)
+				eniIPLog.Errorf("%v, rollback it", err)
 				errDispose := f.eniFactory.Dispose(rawEni[0])
 				if errDispose != nil {
 					eniIPLog.Errorf("rollback %+v failed", rawEni)
@@ -638,8 +1206,18 @@ func (f *eniIPFactory) initialENI(eni *ENI, ipCount int) {
 	if err != nil {
 		eni.lock.Lock()
 		//failed all pending on this initial eni
-		for i := 0; i < eni.pending; i++ {
-			f.ipResultChan <- &ENIIP{
+		requests := eni.initRequests
+	drain:
+		for {
+			select {
+			case req := <-eni.ipBacklog:
+				requests = append(requests, req)
+			default:
+				break drain
+			}
+		}
+		for _, req := range requests {
+			req.result <- &ENIIP{
 				ENIIP: &types.ENIIP{
 					ENI: nil,
 				},
@@ -665,38 +1243,40 @@ func (f *eniIPFactory) initialENI(eni *ENI, ipCount int) {
 		return
 	}
 
-	eni.lock.Lock()
 	if utils.IsWindowsOS() {
 		// NB(thxCode): don't assign the primary IP of the assistant eni.
 		ipv4s, ipv6s = dropPrimaryIP(eni.ENI, ipv4s, ipv6s)
 	}
+	ips := types.MergeIPs(ipv4s, ipv6s)
+	kept := eni.releaseReservedIPs(ips)
+
+	eni.lock.Lock()
 	eniIPLog.Infof("allocate status on async eni: %+v, pending: %v, ips: %v, backlog: %v",
 		eni, eni.pending, ipv4s, len(eni.ipBacklog))
-
-	for _, ipSet := range types.MergeIPs(ipv4s, ipv6s) {
-		eniIP := &types.ENIIP{
-			ENI:   eni.ENI,
-			IPSet: ipSet,
-		}
-
-		f.ipResultChan <- &ENIIP{
-			ENIIP: eniIP,
-			err:   nil,
-		}
-	}
-
 	eni.lock.Unlock()
-	go eni.allocateWorker(f.ipResultChan)
+
+	eni.deliver(eni.initRequests, len(ips), kept)
+	eni.initRequests = nil
+	go eni.allocateWorker()
 }
 
-func (f *eniIPFactory) createENIAsync(initIPs int) (*ENI, error) {
+func (f *eniIPFactory) createENIAsync(initIPs int, vSwitch string, resultChan chan<- *ENIIP) (*ENI, error) {
+	initRequests := make([]ipRequest, initIPs)
+	for i := range initRequests {
+		initRequests[i].result = resultChan
+	}
 	eni := &ENI{
-		ENI:       nil,
-		ips:       make([]*ENIIP, 0),
-		pending:   initIPs,
-		ipBacklog: make(chan struct{}, maxIPBacklog),
-		ecs:       f.eniFactory.ecs,
-		done:      make(chan struct{}, 1),
+		ENI:          nil,
+		ips:          make([]*ENIIP, 0),
+		pending:      initIPs,
+		ipBacklog:    make(chan ipRequest, maxIPBacklog),
+		initRequests: initRequests,
+		ecs:          f.eniFactory.ecs,
+		done:         make(chan struct{}, 1),
+
+		prefixDelegation: f.enablePrefixDelegation,
+		dualStack:        f.ipFamily.IPv4 && f.ipFamily.IPv6,
+		reservedIPs:      f.reservedIPs,
 	}
 	select {
 	case f.maxENI <- struct{}{}:
@@ -706,7 +1286,7 @@ func (f *eniIPFactory) createENIAsync(initIPs int) (*ENI, error) {
 			<-f.maxENI
 			return nil, fmt.Errorf("trigger ENI throttle, max operating concurrent: %v", maxEniOperating)
 		}
-		go f.initialENI(eni, eni.pending)
+		go f.initialENI(eni, eni.pending, vSwitch)
 	default:
 		return nil, fmt.Errorf("max ENI exceeded")
 	}
@@ -752,6 +1332,17 @@ func (f *eniIPFactory) Trace() []tracing.MapKeyValueEntry {
 			Value: strings.Join(secIPs, " "),
 		})
 
+		if len(v.prefixes) > 0 {
+			var prefixes []string
+			for _, prefix := range v.prefixes {
+				prefixes = append(prefixes, prefix.String())
+			}
+			trace = append(trace, tracing.MapKeyValueEntry{
+				Key:   fmt.Sprintf("eni/%s/prefixes", v.MAC),
+				Value: strings.Join(prefixes, " "),
+			})
+		}
+
 		trace = append(trace, tracing.MapKeyValueEntry{
 			Key:   fmt.Sprintf("eni/%s/ip_alloc_inhibit_expire_at", v.MAC),
 			Value: v.ipAllocInhibitExpireAt.Format(timeFormat),
@@ -845,6 +1436,9 @@ func (f *eniIPFactory) checkAccount(message chan<- string) {
 type eniIPResourceManager struct {
 	trunkENI *types.ENI
 	pool     pool.ObjectPool
+	factory  *eniIPFactory
+	// dualStack the eniip of ipv4 only, whose ipv6 released by ReleaseIPv6, is paired with new ipv6 on allocation
+	dualStack bool
 }
 
 func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kubernetes, allocatedResources map[string]resourceManagerInitItem, ipFamily *types.IPFamily) (ResourceManager, error) {
@@ -852,16 +1446,21 @@ func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kub
 	if err != nil {
 		return nil, fmt.Errorf("error get ENI factory for eniip factory, %w", err)
 	}
+	eniFactory.dualStack = ipFamily.IPv6
 
 	factory := &eniIPFactory{
-		name:         factoryNameENIIP,
-		eniFactory:   eniFactory,
-		enableTrunk:  poolConfig.EnableENITrunking,
-		enis:         []*ENI{},
-		eniOperChan:  make(chan struct{}, maxEniOperating),
-		ipResultChan: make(chan *ENIIP, maxIPBacklog),
-		ipFamily:     ipFamily,
+		name:        factoryNameENIIP,
+		eniFactory:  eniFactory,
+		enableTrunk: poolConfig.EnableENITrunking,
+		enis:        []*ENI{},
+		eniOperChan: make(chan struct{}, maxEniOperating),
+		ipFamily:    ipFamily,
+		reservedIPs: make(map[string]struct{}),
+	}
+	for _, ip := range poolConfig.ReservedIPs {
+		factory.reservedIPs[net.ParseIP(ip).String()] = struct{}{}
 	}
+	factory.enablePrefixDelegation = poolConfig.EnablePrefixDelegation
 	var capacity, maxEni, memberENIPod, adapters int
 
 	if !poolConfig.DisableDevicePlugin {
@@ -891,6 +1490,9 @@ func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kub
 			memberENIPod = 0
 		}
 
+		eniIPLog.Infof("eniip capacity %d, max eni %d, adapters %d, eni_cap_ratio %v, eni_cap_shift %d",
+			capacity, maxEni, limit.Adapters, poolConfig.EniCapRatio, poolConfig.EniCapShift)
+
 		factory.maxENI = make(chan struct{}, maxEni)
 
 		if poolConfig.MinENI != 0 {
@@ -958,7 +1560,7 @@ func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kub
 					}
 				}
 				if factory.trunkOnEni == "" && len(enis) < adapters-1 {
-					trunkENIRes, err := factory.eniFactory.CreateWithIPCount(1, true)
+					trunkENIRes, err := factory.eniFactory.CreateWithIPCount(context.Background(), 1, true)
 					if err != nil {
 						return errors.Wrapf(err, "error init trunk eni")
 					}
@@ -968,11 +1570,30 @@ func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kub
 				}
 			}
 
-			for _, eni := range enis {
-				ipv4s, ipv6s, err := ecs.GetENIIPs(ctx, eni.MAC)
+			// fetch the ips of enis from metadata in parallel, the pool is restored in order after
+			type eniAddrs struct {
+				ipv4s, ipv6s []net.IP
+				prefixes     []*net.IPNet
+			}
+			addrs := make([]eniAddrs, len(enis))
+			err = restoreParallel(len(enis), poolConfig.RestoreParallelism, func(i int) error {
+				ipv4s, ipv6s, err := ecs.GetENIIPs(ctx, enis[i].MAC)
 				if err != nil {
 					return fmt.Errorf("error get ENI's ip on pool init, %w", err)
 				}
+				prefixes, err := ecs.GetENIIPv4Prefixes(ctx, enis[i].MAC)
+				if err != nil {
+					return fmt.Errorf("error get ENI's prefix on pool init, %w", err)
+				}
+				addrs[i] = eniAddrs{ipv4s: ipv4s, ipv6s: ipv6s, prefixes: prefixes}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for i, eni := range enis {
+				ipv4s, ipv6s, prefixes := addrs[i].ipv4s, addrs[i].ipv6s, addrs[i].prefixes
 				err = factory.setupENICompartment(eni)
 				if err != nil {
 					// NB(thxCode): an unbinding eni stuck and then block starting,
@@ -990,84 +1611,22 @@ func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kub
 					ENI:       eni,
 					ips:       []*ENIIP{},
 					ecs:       ecs,
-					ipBacklog: make(chan struct{}, maxIPBacklog),
+					ipBacklog: make(chan ipRequest, maxIPBacklog),
 					done:      make(chan struct{}, 1),
+
+					prefixDelegation: factory.enablePrefixDelegation,
+					prefixes:         prefixes,
+					dualStack:        ipFamily.IPv4 && ipFamily.IPv6,
+					reservedIPs:      factory.reservedIPs,
 				}
 				factory.enis = append(factory.enis, poolENI)
 				factory.metricENICount.Inc()
-				if ipFamily.IPv4 && !ipFamily.IPv6 {
-					for _, ip := range ipv4s {
-						eniIP := &types.ENIIP{
-							ENI:   eni,
-							IPSet: types.IPSet{IPv4: ip},
-						}
-						res, ok := allocatedResources[eniIP.GetResourceID()]
-
-						poolENI.ips = append(poolENI.ips, &ENIIP{
-							ENIIP: eniIP,
-						})
-						metric.ENIIPFactoryIPCount.WithLabelValues(factory.name, poolENI.MAC, fmt.Sprint(maxEni)).Inc()
-
-						if !ok {
-							holder.AddIdle(eniIP)
-						} else {
-							holder.AddInuse(eniIP, podInfoKey(res.podInfo.Namespace, res.podInfo.Name))
-						}
-					}
-				} else {
-					v4Map := terwayIP.ToIPMap(ipv4s)
-					v6Map := terwayIP.ToIPMap(ipv6s)
-
-					// put all local res in
-					for id, res := range allocatedResources {
-						if res.item.ENIMAC != eni.MAC {
-							continue
-						}
-						ipSet := types.IPSet{}
-						eniIP := &types.ENIIP{
-							ENI:   eni,
-							IPSet: *ipSet.SetIP(res.item.IPv4).SetIP(res.item.IPv6),
-						}
-
-						poolENI.ips = append(poolENI.ips, &ENIIP{
-							ENIIP: eniIP,
-						})
-						metric.ENIIPFactoryIPCount.WithLabelValues(factory.name, poolENI.MAC, fmt.Sprint(maxEni)).Inc()
-
-						holder.AddInuse(eniIP, podInfoKey(res.podInfo.Namespace, res.podInfo.Name))
-
-						if ipSet.IPv4 != nil {
-							delete(v4Map, ipSet.IPv4.String())
-						}
-						if ipSet.IPv6 != nil {
-							delete(v6Map, ipSet.IPv6.String())
-						}
-						delete(allocatedResources, id)
-					}
-					var v4List, v6List []net.IP
-					for _, v4 := range v4Map {
-						v4List = append(v4List, v4)
-					}
-					for _, v6 := range v6Map {
-						v6List = append(v6List, v6)
-					}
-					for _, unUsed := range types.MergeIPs(v4List, v6List) {
-						eniIP := &types.ENIIP{
-							ENI:   eni,
-							IPSet: unUsed,
-						}
-						poolENI.ips = append(poolENI.ips, &ENIIP{
-							ENIIP: eniIP,
-						})
-						metric.ENIIPFactoryIPCount.WithLabelValues(factory.name, poolENI.MAC, fmt.Sprint(maxEni)).Inc()
-
-						if ipFamily.IPv4 && ipFamily.IPv6 && (unUsed.IPv6 == nil || unUsed.IPv4 == nil) {
-							holder.AddInvalid(eniIP)
-						} else {
-							holder.AddIdle(eniIP)
-						}
-					}
-				}
+				// reserved ips not used by pod are released instead of put into pool,
+				// the ones used are released when the pod releases them
+				reserved := poolENI.restoreIPs(ipv4s, ipv6s, ipFamily, allocatedResources, holder)
+				metric.ENIIPFactoryIPCount.WithLabelValues(factory.name, poolENI.MAC, fmt.Sprint(maxEni)).Add(float64(len(poolENI.ips)))
+
+				poolENI.releaseReservedIPs(reserved)
 
 				eniIPLog.Debugf("init factory's exist ENI: %+v", poolENI)
 				select {
@@ -1075,7 +1634,7 @@ func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kub
 				default:
 					eniIPLog.Warnf("exist enis already over eni limits, maxENI config will not be available")
 				}
-				go poolENI.allocateWorker(factory.ipResultChan)
+				go poolENI.allocateWorker()
 			}
 			return nil
 		},
@@ -1085,8 +1644,10 @@ func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kub
 		return nil, err
 	}
 	mgr := &eniIPResourceManager{
-		trunkENI: trunkENI,
-		pool:     p,
+		trunkENI:  trunkENI,
+		pool:      p,
+		factory:   factory,
+		dualStack: ipFamily.IPv4 && ipFamily.IPv6,
 	}
 
 	//init device plugin for ENI
@@ -1107,7 +1668,155 @@ func newENIIPResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, k8s Kub
 }
 
 func (m *eniIPResourceManager) Allocate(ctx *networkContext, prefer string) (types.NetworkResource, error) {
-	return m.pool.Acquire(ctx, prefer, podInfoKey(ctx.pod.Namespace, ctx.pod.Name))
+	res, err := m.acquire(ctx, prefer)
+	if err != nil {
+		return nil, err
+	}
+	return m.pairIPv6(ctx, res)
+}
+
+// acquire acquire the eniip from pool matching the request of pod
+func (m *eniIPResourceManager) acquire(ctx *networkContext, prefer string) (types.NetworkResource, error) {
+	vSwitch, eniIndex := ctx.pod.VSwitchID, ctx.pod.ENIIndex
+	match := func(eni *types.ENI) bool {
+		return (vSwitch == "" || eni.VSwitchID == vSwitch) && (eniIndex == 0 || eni.DeviceIndex == eniIndex)
+	}
+	if ctx.pod.PreferPreviousIP && ctx.previousIP != "" {
+		res, err := m.acquirePreviousIP(ctx, prefer, match)
+		if err == nil {
+			return res, nil
+		}
+		ctx.Log().Infof("previous ip %s is not free, fallback to any ip: %v", ctx.previousIP, err)
+	}
+	if ctx.subCIDR != nil {
+		return m.acquireInSubCIDR(ctx, prefer, match)
+	}
+	if ctx.eniAffinity != "" {
+		res, err := m.acquireOnENI(ctx, prefer, match)
+		if err == nil {
+			return res, nil
+		}
+		ctx.Log().Infof("eni %s has no eniip available, fallback to other enis: %v", ctx.eniAffinity, err)
+	}
+	if vSwitch == "" && eniIndex == 0 {
+		return m.pool.Acquire(ctx, prefer, podInfoKey(ctx.pod.Namespace, ctx.pod.Name))
+	}
+	res, err := m.pool.AcquireMatch(ctx, prefer, podInfoKey(ctx.pod.Namespace, ctx.pod.Name), func(res types.NetworkResource) bool {
+		eniIP, ok := res.(*types.ENIIP)
+		return ok && eniIP.ENI != nil && match(eniIP.ENI)
+	}, m.createMatch(vSwitch, eniIndex, match))
+	if err != nil {
+		if eniIndex != 0 {
+			return nil, fmt.Errorf("error allocate eniip from eni at device index %d: %w", eniIndex, err)
+		}
+		return nil, fmt.Errorf("error allocate eniip from vswitch %s: %w", vSwitch, err)
+	}
+	return res, nil
+}
+
+// createMatch return the func create eniip on the vswitch or the eni at device index
+func (m *eniIPResourceManager) createMatch(vSwitch string, eniIndex int, match func(eni *types.ENI) bool) func() ([]types.NetworkResource, error) {
+	return func() ([]types.NetworkResource, error) {
+		if eniIndex == 0 {
+			return m.factory.CreateOnVSwitch(1, vSwitch)
+		}
+		// the device index of new eni is decided by ecs, only the attached eni can be used
+		return m.factory.CreateOnExistingENI(1, match)
+	}
+}
+
+// acquireInSubCIDR acquire the eniip with ipv4 in the sub cidr of pod. the ip assigned out of the cidr is left
+// idle in pool for other pods, and the assignment is retried for at most ctx.subCIDRRetries times
+func (m *eniIPResourceManager) acquireInSubCIDR(ctx *networkContext, prefer string, match func(eni *types.ENI) bool) (types.NetworkResource, error) {
+	inSubCIDR := func(res types.NetworkResource) bool {
+		eniIP, ok := res.(*types.ENIIP)
+		return ok && eniIP.ENI != nil && match(eniIP.ENI) && eniIP.IPSet.IPv4 != nil && ctx.subCIDR.Contains(eniIP.IPSet.IPv4)
+	}
+	create := m.createMatch(ctx.pod.VSwitchID, ctx.pod.ENIIndex, match)
+	var err error
+	for i := 0; i <= ctx.subCIDRRetries; i++ {
+		var res types.NetworkResource
+		res, err = m.pool.AcquireMatch(ctx, prefer, podInfoKey(ctx.pod.Namespace, ctx.pod.Name), inSubCIDR, create)
+		if err == nil {
+			return res, nil
+		}
+		if errors.Is(err, pool.ErrNoAvailableResource) || errors.Is(err, pool.ErrContextDone) {
+			break
+		}
+		ctx.Log().Infof("eniip not in sub cidr %s, retry: %v", ctx.subCIDR, err)
+	}
+	return nil, fmt.Errorf("error allocate eniip in sub cidr %s: %w", ctx.subCIDR, err)
+}
+
+// acquireOnENI acquire the eniip on the eni of ctx.eniAffinity, the ip is assigned to the eni if no idle one
+func (m *eniIPResourceManager) acquireOnENI(ctx *networkContext, prefer string, match func(eni *types.ENI) bool) (types.NetworkResource, error) {
+	onENI := func(eni *types.ENI) bool {
+		return eni.MAC == ctx.eniAffinity && match(eni)
+	}
+	return m.pool.AcquireMatch(ctx, prefer, podInfoKey(ctx.pod.Namespace, ctx.pod.Name), func(res types.NetworkResource) bool {
+		eniIP, ok := res.(*types.ENIIP)
+		return ok && eniIP.ENI != nil && onENI(eniIP.ENI)
+	}, func() ([]types.NetworkResource, error) {
+		return m.factory.CreateOnExistingENI(1, onENI)
+	})
+}
+
+// acquirePreviousIP acquire the idle eniip has the previous ip of pod, the ip is matched by address
+// so it is found even if the resource id changed, e.g. ipv6 enabled
+func (m *eniIPResourceManager) acquirePreviousIP(ctx *networkContext, prefer string, match func(eni *types.ENI) bool) (types.NetworkResource, error) {
+	return m.pool.AcquireMatch(ctx, prefer, podInfoKey(ctx.pod.Namespace, ctx.pod.Name), func(res types.NetworkResource) bool {
+		eniIP, ok := res.(*types.ENIIP)
+		return ok && eniIP.ENI != nil && eniIP.IPSet.IPv4 != nil &&
+			eniIP.IPSet.IPv4.String() == ctx.previousIP && match(eniIP.ENI)
+	}, nil)
+}
+
+// pairIPv6 assign new ipv6 to the eniip of ipv4 only in dual stack, the eniip is released back to pool on failure
+func (m *eniIPResourceManager) pairIPv6(ctx *networkContext, res types.NetworkResource) (types.NetworkResource, error) {
+	eniIP, ok := res.(*types.ENIIP)
+	if !ok || !m.dualStack || eniIP.IPSet.IPv4 == nil || eniIP.IPSet.IPv6 != nil {
+		return res, nil
+	}
+	paired, err := m.factory.assignIPv6(ctx, eniIP)
+	if err == nil {
+		err = m.pool.Replace(eniIP.GetResourceID(), paired)
+	}
+	if err != nil {
+		if releaseErr := m.pool.Release(eniIP.GetResourceID()); releaseErr != nil {
+			ctx.Log().Warnf("error release eniip %s not paired: %v", eniIP.GetResourceID(), releaseErr)
+		}
+		return nil, fmt.Errorf("error pair ipv4 %s with ipv6: %w", eniIP.IPSet.IPv4, err)
+	}
+	ctx.Log().Infof("ipv4 %s paired with new ipv6 %s", paired.IPSet.IPv4, paired.IPSet.IPv6)
+	return paired, nil
+}
+
+// ReleaseIPv6 release the ipv6 of the eniip in use while the ipv4 is kept in use,
+// the item of the eniip with ipv4 only is returned
+func (m *eniIPResourceManager) ReleaseIPv6(resItem types.ResourceItem) (types.ResourceItem, error) {
+	res, err := m.pool.Stat(resItem.ID)
+	if err != nil {
+		return resItem, err
+	}
+	eniIP := res.(*types.ENIIP)
+	if eniIP.IPSet.IPv4 == nil || eniIP.IPSet.IPv6 == nil {
+		return resItem, nil
+	}
+	released := &types.ENIIP{ENI: eniIP.ENI, IPSet: types.IPSet{IPv4: eniIP.IPSet.IPv4}, Prefix: eniIP.Prefix}
+	// replaced first, the eniip not in use is not released
+	err = m.pool.Replace(resItem.ID, released)
+	if err != nil {
+		return resItem, err
+	}
+	err = m.factory.releaseIPv6(eniIP, released)
+	if err != nil {
+		_ = m.pool.Replace(released.GetResourceID(), eniIP)
+		return resItem, err
+	}
+	item := resItem
+	item.ID = released.GetResourceID()
+	item.IPv6 = ""
+	return item, nil
 }
 
 func (m *eniIPResourceManager) Release(context *networkContext, resItem types.ResourceItem) error {
@@ -1117,22 +1826,44 @@ func (m *eniIPResourceManager) Release(context *networkContext, resItem types.Re
 	return m.pool.Release(resItem.ID)
 }
 
-func (m *eniIPResourceManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) error {
+func (m *eniIPResourceManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) (int, error) {
+	released := 0
 	for expireRes, expireItem := range expireResSet {
 		if _, err := m.pool.Stat(expireRes); err == nil {
 			err = m.Release(nil, expireItem)
 			if err != nil {
-				return err
+				return released, &resourceGCError{resID: expireRes, err: err}
 			}
+			released++
 		}
 	}
-	return nil
+	return released, nil
 }
 
 func (m *eniIPResourceManager) Stat(context *networkContext, resID string) (types.NetworkResource, error) {
 	return m.pool.Stat(resID)
 }
 
+func (m *eniIPResourceManager) Free() int {
+	return m.pool.Free()
+}
+
+func (m *eniIPResourceManager) Capacity() (creatable, capacity int) {
+	return m.pool.Capacity()
+}
+
+func (m *eniIPResourceManager) Warm(ctx context.Context, idle int) (int, error) {
+	return m.pool.Warm(ctx, idle)
+}
+
+func (m *eniIPResourceManager) Drain(ctx context.Context) (int, error) {
+	return m.pool.Drain(ctx)
+}
+
+func (m *eniIPResourceManager) Resize(minIdle, maxIdle int) error {
+	return m.pool.Resize(minIdle, maxIdle)
+}
+
 func (m *eniIPResourceManager) GetResourceMapping() (tracing.ResourcePoolStats, error) {
 	return m.pool.GetResourceMapping()
 }
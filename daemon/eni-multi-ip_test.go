@@ -0,0 +1,567 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	apiErr "github.com/AliyunContainerService/terway/pkg/aliyun/client/errors"
+	terwayIP "github.com/AliyunContainerService/terway/pkg/ip"
+	"github.com/AliyunContainerService/terway/pkg/ipam"
+	"github.com/AliyunContainerService/terway/pkg/pool"
+	"github.com/AliyunContainerService/terway/types"
+	"github.com/AliyunContainerService/terway/types/daemon"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_eniIPFactory_submitOnVSwitch(t *testing.T) {
+	newENI := func(id, vsw string) *ENI {
+		return &ENI{
+			ENI:       &types.ENI{ID: id, VSwitchID: vsw},
+			ipBacklog: make(chan ipRequest, maxIPBacklog),
+		}
+	}
+	f := &eniIPFactory{
+		eniFactory: &eniFactory{
+			switches:               []string{"vsw-a", "vsw-b"},
+			vswitchSelectionPolicy: types.VSwitchSelectionPolicyRandom,
+		},
+		enis:     []*ENI{newENI("eni-a", "vsw-a"), newENI("eni-b", "vsw-b")},
+		eniMaxIP: 10,
+	}
+	resultChan := make(chan *ENIIP, 3)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, f.submit(&AllocCtx{}, "vsw-b", resultChan))
+	}
+	for _, eni := range f.enis {
+		if eni.VSwitchID == "vsw-b" {
+			assert.Equal(t, 3, eni.pending)
+		} else {
+			assert.Equal(t, 0, eni.pending)
+		}
+	}
+	assert.Error(t, f.submit(&AllocCtx{}, "vsw-c", resultChan))
+}
+
+func Test_eniIPFactory_CreateOnExistingENI(t *testing.T) {
+	f := &eniIPFactory{
+		eniFactory: &eniFactory{
+			switches:               []string{"vsw-a"},
+			vswitchSelectionPolicy: types.VSwitchSelectionPolicyRandom,
+		},
+		enis: []*ENI{
+			{ENI: &types.ENI{ID: "eni-a", VSwitchID: "vsw-a", DeviceIndex: 1}, ipBacklog: make(chan ipRequest, maxIPBacklog)},
+			{ENI: &types.ENI{ID: "eni-b", VSwitchID: "vsw-a", DeviceIndex: 2}, ipBacklog: make(chan ipRequest, maxIPBacklog)},
+		},
+		eniMaxIP: 10,
+	}
+	_, err := f.CreateOnExistingENI(1, func(eni *types.ENI) bool {
+		return eni.DeviceIndex == 3
+	})
+	assert.Error(t, err)
+
+	assert.NoError(t, f.submitMatch(&AllocCtx{}, func(eni *types.ENI) bool {
+		return eni.DeviceIndex == 2
+	}, make(chan *ENIIP, 1)))
+	assert.Equal(t, 0, f.enis[0].pending)
+	assert.Equal(t, 1, f.enis[1].pending)
+}
+
+func Test_ENI_shortfall(t *testing.T) {
+	e := &ENI{ENI: &types.ENI{ID: "eni-a", MAC: "00:00:00:00:00:01"}}
+	resultChan := make(chan *ENIIP, maxIPBacklog)
+	requests := []ipRequest{{result: resultChan}, {result: resultChan}, {result: resultChan}}
+	e.shortfall(requests, 3)
+	assert.Equal(t, 0, len(resultChan))
+
+	// only the requests not satisfied fail
+	e.shortfall(requests, 1)
+	assert.Equal(t, 2, len(resultChan))
+	for i := 0; i < 2; i++ {
+		result := <-resultChan
+		assert.Error(t, result.err)
+		assert.Equal(t, "eni-a", result.ENI.ID)
+	}
+}
+
+func Test_ENI_canAssignLocked(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("192.168.2.0/28")
+	e := &ENI{ENI: &types.ENI{ID: "eni-a"}, pending: 2}
+	assert.True(t, e.canAssignLocked(3))
+	assert.False(t, e.canAssignLocked(2))
+
+	// the ips carved from a prefix take one slot
+	e = &ENI{ENI: &types.ENI{ID: "eni-a"}, prefixDelegation: true, prefixes: []*net.IPNet{prefix}}
+	for i := 0; i < ipPerPrefix; i++ {
+		e.ips = append(e.ips, &ENIIP{ENIIP: &types.ENIIP{Prefix: prefix}})
+	}
+	assert.True(t, e.canAssignLocked(2))
+	assert.False(t, e.canAssignLocked(1))
+
+	// free ips of the prefix are assigned without a new prefix
+	e.prefixFree = []net.IP{net.ParseIP("192.168.2.15")}
+	assert.True(t, e.canAssignLocked(1))
+
+	// secondary ips allocated before the prefix delegation take one slot each
+	e.prefixFree = nil
+	e.ips = append(e.ips, &ENIIP{ENIIP: &types.ENIIP{}})
+	assert.True(t, e.canAssignLocked(3))
+	assert.False(t, e.canAssignLocked(2))
+}
+
+func Test_ENI_requestMore(t *testing.T) {
+	e := &ENI{ENI: &types.ENI{ID: "eni-a", MAC: "00:00:00:00:00:01"}, ipBacklog: make(chan ipRequest, maxIPBacklog)}
+	resultChan := make(chan *ENIIP, maxIPBacklog)
+
+	e.requestMore([]ipRequest{{retry: 0, result: resultChan}, {retry: maxReservedIPRetry - 1, result: resultChan}})
+	assert.Equal(t, 0, len(resultChan))
+	assert.Equal(t, 1, (<-e.ipBacklog).retry)
+	assert.Equal(t, maxReservedIPRetry, (<-e.ipBacklog).retry)
+
+	// the request used up the retries fails instead of going back to the backlog
+	e.requestMore([]ipRequest{{retry: maxReservedIPRetry, result: resultChan}})
+	assert.Equal(t, 0, len(e.ipBacklog))
+	assert.Equal(t, 1, len(resultChan))
+	assert.Error(t, (<-resultChan).err)
+}
+
+func Test_ENI_deliver(t *testing.T) {
+	e := &ENI{ENI: &types.ENI{ID: "eni-a", MAC: "00:00:00:00:00:01"}, ipBacklog: make(chan ipRequest, maxIPBacklog)}
+	// the results go to the channel of each request, never to the others
+	first := make(chan *ENIIP, 1)
+	second := make(chan *ENIIP, 1)
+	third := make(chan *ENIIP, 1)
+	ips := []types.IPSet{{IPv4: net.ParseIP("192.168.0.2")}}
+
+	e.deliver([]ipRequest{{result: first}, {retry: 1, result: second}, {result: third}}, 2, ips)
+	result := <-second
+	assert.NoError(t, result.err)
+	assert.Equal(t, "192.168.0.2", result.IPSet.IPv4.String())
+
+	// the request left for the reserved ip retry, the one without ip assigned fails
+	req := <-e.ipBacklog
+	assert.Equal(t, 1, req.retry)
+	req.result <- &ENIIP{}
+	assert.Equal(t, 1, len(first))
+	assert.Error(t, (<-third).err)
+}
+
+func Test_eniIPFactory_CheckReserved(t *testing.T) {
+	f := &eniIPFactory{reservedIPs: map[string]struct{}{"192.168.0.10": {}}}
+	eniIP := &types.ENIIP{
+		ENI:   &types.ENI{ID: "eni-a", MAC: "00:00:00:00:00:01"},
+		IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.10")},
+	}
+	// the reserved ip is reported as gone, so that pool disposes it on release
+	assert.ErrorIs(t, f.Check(eniIP), apiErr.ErrNotFound)
+}
+
+type fakePrefixAPI struct {
+	ipam.API
+	prefixes   []*net.IPNet
+	ipv6s      []net.IP
+	ipv6Err    error
+	unassigned []string
+}
+
+func (f *fakePrefixAPI) AssignIPv4PrefixForENI(ctx context.Context, eniID, mac string, count int) ([]*net.IPNet, error) {
+	prefixes := f.prefixes[:count]
+	f.prefixes = f.prefixes[count:]
+	return prefixes, nil
+}
+
+func (f *fakePrefixAPI) AssignIPv6ForENI(ctx context.Context, eniID, mac string, count int) ([]net.IP, error) {
+	if f.ipv6Err != nil {
+		return nil, f.ipv6Err
+	}
+	ipv6s := f.ipv6s[:count]
+	f.ipv6s = f.ipv6s[count:]
+	return ipv6s, nil
+}
+
+func (f *fakePrefixAPI) UnAssignIPv4PrefixForENI(ctx context.Context, eniID, mac string, prefixes []*net.IPNet) error {
+	for _, prefix := range prefixes {
+		f.unassigned = append(f.unassigned, prefix.String())
+	}
+	return nil
+}
+
+func Test_eniIPFactory_DisposePrefix(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("192.168.1.0/28")
+	eniInfo := &types.ENI{ID: "eni-a", MAC: "mac-a", PrimaryIP: types.IPSet{IPv4: net.ParseIP("192.168.0.1")}}
+	eni := &ENI{ENI: eniInfo, prefixes: []*net.IPNet{prefix}}
+	eni.ips = append(eni.ips, &ENIIP{ENIIP: &types.ENIIP{ENI: eniInfo, IPSet: eniInfo.PrimaryIP}})
+	for i, ip := range terwayIP.IPNetHosts(prefix) {
+		if i < 2 {
+			eni.ips = append(eni.ips, &ENIIP{ENIIP: &types.ENIIP{ENI: eniInfo, IPSet: types.IPSet{IPv4: ip}, Prefix: prefix}})
+			continue
+		}
+		eni.prefixFree = append(eni.prefixFree, ip)
+	}
+	api := &fakePrefixAPI{}
+	f := &eniIPFactory{eniFactory: &eniFactory{ecs: api}, enis: []*ENI{eni}}
+
+	// other ip of the prefix is in use
+	assert.NoError(t, f.Dispose(eni.ips[1].ENIIP))
+	assert.Empty(t, api.unassigned)
+	assert.Len(t, eni.prefixes, 1)
+	assert.Len(t, eni.prefixFree, 15)
+
+	// all the ips of the prefix are free
+	assert.NoError(t, f.Dispose(eni.ips[1].ENIIP))
+	assert.Equal(t, []string{"192.168.1.0/28"}, api.unassigned)
+	assert.Empty(t, eni.prefixes)
+	assert.Empty(t, eni.prefixFree)
+	assert.Len(t, eni.ips, 1)
+}
+
+func Test_ENI_allocateFromPrefix_dualStack(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("192.168.1.0/28")
+	api := &fakePrefixAPI{
+		prefixes: []*net.IPNet{prefix},
+		ipv6s:    []net.IP{net.ParseIP("fd00::1"), net.ParseIP("fd00::2")},
+	}
+	eni := &ENI{ENI: &types.ENI{ID: "eni-a", MAC: "mac-a"}, ecs: api, prefixDelegation: true, dualStack: true}
+
+	// the ips carved are paired with ipv6
+	ips, err := eni.allocateFromPrefix(2)
+	assert.NoError(t, err)
+	assert.Len(t, ips, 2)
+	for i, ip := range ips {
+		assert.True(t, prefix.Contains(ip.IPSet.IPv4))
+		assert.Equal(t, fmt.Sprintf("fd00::%d", i+1), ip.IPSet.IPv6.String())
+		assert.Equal(t, prefix, ip.Prefix)
+	}
+	assert.Len(t, eni.prefixFree, 14)
+
+	// the ips carved are kept on failure
+	api.ipv6Err = fmt.Errorf("assign ipv6 failed")
+	_, err = eni.allocateFromPrefix(1)
+	assert.Error(t, err)
+	assert.Len(t, eni.prefixFree, 14)
+}
+
+type fakeResourceHolder struct {
+	idle    []types.NetworkResource
+	invalid []types.NetworkResource
+	inUse   map[string]string
+}
+
+func (f *fakeResourceHolder) AddIdle(resource types.NetworkResource) {
+	f.idle = append(f.idle, resource)
+}
+
+func (f *fakeResourceHolder) AddInvalid(resource types.NetworkResource) {
+	f.invalid = append(f.invalid, resource)
+}
+
+func (f *fakeResourceHolder) AddInuse(resource types.NetworkResource, idempotentKey string) {
+	if f.inUse == nil {
+		f.inUse = make(map[string]string)
+	}
+	f.inUse[resource.GetResourceID()] = idempotentKey
+}
+
+func Test_ENI_restoreIPs(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("192.168.1.0/28")
+	eniInfo := &types.ENI{ID: "eni-a", MAC: "mac-a"}
+	ipv4s := []net.IP{net.ParseIP("192.168.0.2"), net.ParseIP("192.168.0.3")}
+	allocated := map[string]resourceManagerInitItem{
+		"mac-a.192.168.1.1": {
+			item:    types.ResourceItem{ID: "mac-a.192.168.1.1", ENIMAC: "mac-a", IPv4: "192.168.1.1"},
+			podInfo: &types.PodInfo{Name: "foo", Namespace: "default"},
+		},
+	}
+	eni := &ENI{
+		ENI:         eniInfo,
+		prefixes:    []*net.IPNet{prefix},
+		reservedIPs: map[string]struct{}{"192.168.0.3": {}, "192.168.1.2": {}},
+	}
+	holder := &fakeResourceHolder{}
+
+	reserved := eni.restoreIPs(ipv4s, nil, &types.IPFamily{IPv4: true}, allocated, holder)
+	assert.Equal(t, []types.IPSet{{IPv4: net.ParseIP("192.168.0.3")}}, reserved)
+	assert.Len(t, holder.idle, 1)
+	assert.Equal(t, "mac-a.192.168.0.2", holder.idle[0].GetResourceID())
+	assert.Equal(t, map[string]string{"mac-a.192.168.1.1": "default/foo"}, holder.inUse)
+	assert.Len(t, eni.ips, 2)
+	assert.Equal(t, prefix, eni.ips[1].Prefix)
+	// the prefix ips not in use are free for allocation, reserved one is left out
+	assert.Len(t, eni.prefixFree, 14)
+	for _, ip := range eni.prefixFree {
+		assert.NotEqual(t, "192.168.1.1", ip.String())
+		assert.NotEqual(t, "192.168.1.2", ip.String())
+	}
+}
+
+func Test_ENI_restoreIPs_dualStack(t *testing.T) {
+	_, prefix, _ := net.ParseCIDR("192.168.1.0/28")
+	eniInfo := &types.ENI{ID: "eni-a", MAC: "mac-a"}
+	ipv4s := []net.IP{net.ParseIP("192.168.0.2")}
+	ipv6s := []net.IP{net.ParseIP("fd00::2"), net.ParseIP("fd00::11")}
+	allocated := map[string]resourceManagerInitItem{
+		"mac-a.192.168.1.1-fd00::11": {
+			item:    types.ResourceItem{ID: "mac-a.192.168.1.1-fd00::11", ENIMAC: "mac-a", IPv4: "192.168.1.1", IPv6: "fd00::11"},
+			podInfo: &types.PodInfo{Name: "foo", Namespace: "default"},
+		},
+	}
+	eni := &ENI{ENI: eniInfo, prefixes: []*net.IPNet{prefix}, dualStack: true}
+	holder := &fakeResourceHolder{}
+
+	reserved := eni.restoreIPs(ipv4s, ipv6s, &types.IPFamily{IPv4: true, IPv6: true}, allocated, holder)
+	assert.Empty(t, reserved)
+	// the ips carved from prefix are not merged with the secondary ipv6
+	assert.Empty(t, holder.invalid)
+	assert.Len(t, holder.idle, 1)
+	assert.Equal(t, "mac-a.192.168.0.2-fd00::2", holder.idle[0].GetResourceID())
+	assert.Equal(t, map[string]string{"mac-a.192.168.1.1-fd00::11": "default/foo"}, holder.inUse)
+	assert.Len(t, eni.prefixFree, 15)
+}
+
+// fakeSubCIDRPool hand out the created in order, the one not matched is left idle
+type fakeSubCIDRPool struct {
+	fakeMatchPool
+	created []types.NetworkResource
+	creates int
+}
+
+func (f *fakeSubCIDRPool) AcquireMatch(ctx context.Context, resID, idempotentKey string, match func(types.NetworkResource) bool, create func() ([]types.NetworkResource, error)) (types.NetworkResource, error) {
+	res, err := f.fakeMatchPool.AcquireMatch(ctx, resID, idempotentKey, match, nil)
+	if err == nil {
+		return res, nil
+	}
+	if len(f.created) == 0 {
+		return nil, pool.ErrNoAvailableResource
+	}
+	f.creates++
+	res, f.created = f.created[0], f.created[1:]
+	if !match(res) {
+		f.idle = append(f.idle, res)
+		return nil, fmt.Errorf("resource %s created not match", res.GetResourceID())
+	}
+	return res, nil
+}
+
+func Test_eniIPResourceManager_acquireOnENI(t *testing.T) {
+	eni1 := &types.ENI{ID: "eni-1", MAC: "00:00:00:00:00:01", VSwitchID: "vsw-1"}
+	eni2 := &types.ENI{ID: "eni-2", MAC: "00:00:00:00:00:02", VSwitchID: "vsw-1"}
+	ip1 := &types.ENIIP{ENI: eni1, IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.1")}}
+	ip2 := &types.ENIIP{ENI: eni2, IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.2")}}
+	p := &fakeSubCIDRPool{fakeMatchPool: fakeMatchPool{idle: []types.NetworkResource{ip2, ip1}}}
+	m := &eniIPResourceManager{pool: p}
+	ctx := &networkContext{
+		Context:     context.Background(),
+		pod:         &types.PodInfo{Name: "foo", Namespace: "default", VSwitchID: "vsw-1"},
+		eniAffinity: eni1.MAC,
+	}
+
+	res, err := m.Allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, ip1, res)
+	assert.Equal(t, eni1.MAC, singleENIOf(ip1, []*types.ENIIP{res.(*types.ENIIP)}))
+
+	// fallback to other enis
+	p.idle = []types.NetworkResource{ip2}
+	res, err = m.Allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, ip2, res)
+	assert.Equal(t, "", singleENIOf(ip1, []*types.ENIIP{res.(*types.ENIIP)}))
+}
+
+func Test_eniIPResourceManager_acquireInSubCIDR(t *testing.T) {
+	eni := &types.ENI{ID: "eni-1", MAC: "00:00:00:00:00:01", VSwitchID: "vsw-1"}
+	newENIIP := func(ip string) *types.ENIIP {
+		return &types.ENIIP{ENI: eni, IPSet: types.IPSet{IPv4: net.ParseIP(ip)}}
+	}
+	_, subCIDR, _ := net.ParseCIDR("192.168.1.0/24")
+	p := &fakeSubCIDRPool{
+		fakeMatchPool: fakeMatchPool{idle: []types.NetworkResource{newENIIP("192.168.0.10")}},
+		created:       []types.NetworkResource{newENIIP("192.168.0.11"), newENIIP("192.168.1.12")},
+	}
+	m := &eniIPResourceManager{pool: p}
+	ctx := &networkContext{
+		Context:        context.Background(),
+		pod:            &types.PodInfo{Name: "foo", Namespace: "default"},
+		subCIDR:        subCIDR,
+		subCIDRRetries: 3,
+	}
+
+	res, err := m.Allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.1.12", res.(*types.ENIIP).IPSet.IPv4.String())
+	assert.Equal(t, 2, p.creates)
+	assert.Len(t, p.idle, 2)
+
+	// bounded by the retries
+	p.created = []types.NetworkResource{newENIIP("192.168.0.13"), newENIIP("192.168.0.14"), newENIIP("192.168.1.15")}
+	p.creates = 0
+	ctx.subCIDRRetries = 1
+	_, err = m.Allocate(ctx, "")
+	assert.Error(t, err)
+	assert.Equal(t, 2, p.creates)
+
+	n := &networkService{}
+	n.podSubCIDRs, err = parsePodSubCIDRs([]daemon.PodSubCIDR{{Selector: "zone=dmz", CIDR: "192.168.1.0/24"}})
+	assert.NoError(t, err)
+	assert.Equal(t, subCIDR, n.podSubCIDR(&types.PodInfo{Labels: map[string]string{"zone": "dmz"}}))
+	assert.Nil(t, n.podSubCIDR(&types.PodInfo{}))
+
+	for _, cfg := range []daemon.PodSubCIDR{
+		{Selector: "", CIDR: "192.168.1.0/24"},
+		{Selector: "zone=dmz", CIDR: "192.168.1.0"},
+		{Selector: "zone=dmz", CIDR: "fd00::/64"},
+	} {
+		_, err = parsePodSubCIDRs([]daemon.PodSubCIDR{cfg})
+		assert.Error(t, err, cfg)
+	}
+}
+
+func Test_eniIPResourceManager_acquirePreviousIP(t *testing.T) {
+	eni := &types.ENI{ID: "eni-1", MAC: "00:00:00:00:00:01", VSwitchID: "vsw-1"}
+	eniIP := &types.ENIIP{ENI: eni, IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.10")}}
+	m := &eniIPResourceManager{pool: &fakeMatchPool{idle: []types.NetworkResource{
+		&types.ENIIP{ENI: eni, IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.11")}},
+		eniIP,
+	}}}
+	ctx := &networkContext{
+		Context:    context.Background(),
+		pod:        &types.PodInfo{Name: "foo", Namespace: "default"},
+		previousIP: "192.168.0.10",
+	}
+	matchAll := func(*types.ENI) bool { return true }
+
+	// matched by address, regardless of the resource id
+	res, err := m.acquirePreviousIP(ctx, "00:00:00:00:00:01.192.168.0.10", matchAll)
+	assert.NoError(t, err)
+	assert.Equal(t, eniIP, res)
+
+	_, err = m.acquirePreviousIP(ctx, "", func(eni *types.ENI) bool { return eni.VSwitchID == "vsw-2" })
+	assert.Error(t, err)
+
+	ctx.previousIP = "192.168.0.12"
+	_, err = m.acquirePreviousIP(ctx, "", matchAll)
+	assert.Error(t, err)
+}
+
+// fakeInUsePool hold the resources in use by id
+type fakeInUsePool struct {
+	pool.ObjectPool
+	inUse    map[string]types.NetworkResource
+	released []string
+}
+
+func (f *fakeInUsePool) Stat(resID string) (types.NetworkResource, error) {
+	res, ok := f.inUse[resID]
+	if !ok {
+		return nil, pool.ErrInvalidState
+	}
+	return res, nil
+}
+
+func (f *fakeInUsePool) Replace(resID string, res types.NetworkResource) error {
+	if _, ok := f.inUse[resID]; !ok {
+		return pool.ErrInvalidState
+	}
+	delete(f.inUse, resID)
+	f.inUse[res.GetResourceID()] = res
+	return nil
+}
+
+func (f *fakeInUsePool) Release(resID string) error {
+	delete(f.inUse, resID)
+	f.released = append(f.released, resID)
+	return nil
+}
+
+type fakeIPv6API struct {
+	ipam.API
+	assigned   []net.IP
+	unassigned []string
+	err        error
+}
+
+func (f *fakeIPv6API) AssignIPv6ForENI(ctx context.Context, eniID, mac string, count int) ([]net.IP, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	ip := f.assigned[0]
+	f.assigned = f.assigned[1:]
+	return []net.IP{ip}, nil
+}
+
+func (f *fakeIPv6API) UnAssignIPsForENI(ctx context.Context, eniID, mac string, ipv4s []net.IP, ipv6s []net.IP) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, ip := range ipv6s {
+		f.unassigned = append(f.unassigned, ip.String())
+	}
+	return nil
+}
+
+func Test_eniIPResourceManager_ReleaseIPv6(t *testing.T) {
+	eniInfo := &types.ENI{ID: "eni-1", MAC: "mac-1"}
+	eniIP := &types.ENIIP{ENI: eniInfo, IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.1"), IPv6: net.ParseIP("fd00::1")}}
+	eni := &ENI{ENI: eniInfo}
+	eni.ips = append(eni.ips, &ENIIP{ENIIP: eniIP})
+	api := &fakeIPv6API{assigned: []net.IP{net.ParseIP("fd00::2")}}
+	p := &fakeInUsePool{inUse: map[string]types.NetworkResource{eniIP.GetResourceID(): eniIP}}
+	m := &eniIPResourceManager{
+		pool:      p,
+		factory:   &eniIPFactory{eniFactory: &eniFactory{ecs: api}, enis: []*ENI{eni}},
+		dualStack: true,
+	}
+	item := eniIP.ToResItems()[0]
+
+	released, err := m.ReleaseIPv6(item)
+	assert.NoError(t, err)
+	assert.Equal(t, "mac-1.192.168.0.1", released.ID)
+	assert.Equal(t, "192.168.0.1", released.IPv4)
+	assert.Empty(t, released.IPv6)
+	assert.Equal(t, []string{"fd00::1"}, api.unassigned)
+	assert.Contains(t, p.inUse, "mac-1.192.168.0.1")
+	assert.Nil(t, eni.ips[0].IPSet.IPv6)
+
+	// ipv4 only is left as it is
+	again, err := m.ReleaseIPv6(released)
+	assert.NoError(t, err)
+	assert.Equal(t, released, again)
+	assert.Len(t, api.unassigned, 1)
+
+	// the ipv4 only is paired with new ipv6 on allocation
+	ctx := &networkContext{Context: context.Background(), pod: &types.PodInfo{Name: "foo", Namespace: "default"}}
+	paired, err := m.pairIPv6(ctx, p.inUse["mac-1.192.168.0.1"])
+	assert.NoError(t, err)
+	assert.Equal(t, "fd00::2", paired.(*types.ENIIP).IPSet.IPv6.String())
+	assert.Contains(t, p.inUse, "mac-1.192.168.0.1-fd00::2")
+	assert.Equal(t, "fd00::2", eni.ips[0].IPSet.IPv6.String())
+}
+
+func Test_eniIPResourceManager_ReleaseIPv6_failed(t *testing.T) {
+	eniInfo := &types.ENI{ID: "eni-1", MAC: "mac-1"}
+	eniIP := &types.ENIIP{ENI: eniInfo, IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.1"), IPv6: net.ParseIP("fd00::1")}}
+	api := &fakeIPv6API{err: fmt.Errorf("unassign failed")}
+	p := &fakeInUsePool{inUse: map[string]types.NetworkResource{eniIP.GetResourceID(): eniIP}}
+	m := &eniIPResourceManager{
+		pool:      p,
+		factory:   &eniIPFactory{eniFactory: &eniFactory{ecs: api}},
+		dualStack: true,
+	}
+	item := eniIP.ToResItems()[0]
+
+	// the eniip in use is restored
+	released, err := m.ReleaseIPv6(item)
+	assert.Error(t, err)
+	assert.Equal(t, item, released)
+	assert.Equal(t, eniIP, p.inUse[item.ID])
+
+	// the eniip not paired is released back to pool
+	v4Only := &types.ENIIP{ENI: eniInfo, IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.2")}}
+	p.inUse[v4Only.GetResourceID()] = v4Only
+	ctx := &networkContext{Context: context.Background(), pod: &types.PodInfo{Name: "foo", Namespace: "default"}}
+	_, err = m.pairIPv6(ctx, v4Only)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"mac-1.192.168.0.2"}, p.released)
+}
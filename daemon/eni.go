@@ -11,8 +11,11 @@ import (
 
 	"github.com/AliyunContainerService/terway/deviceplugin"
 	"github.com/AliyunContainerService/terway/pkg/aliyun"
+	apiErr "github.com/AliyunContainerService/terway/pkg/aliyun/client/errors"
 	"github.com/AliyunContainerService/terway/pkg/ipam"
+	"github.com/AliyunContainerService/terway/pkg/link"
 	"github.com/AliyunContainerService/terway/pkg/logger"
+	"github.com/AliyunContainerService/terway/pkg/metric"
 	"github.com/AliyunContainerService/terway/pkg/pool"
 	"github.com/AliyunContainerService/terway/pkg/tracing"
 	"github.com/AliyunContainerService/terway/types"
@@ -20,14 +23,31 @@ import (
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/flowcontrol"
 )
 
 var eniLog = logger.DefaultLogger
 
+// ErrENINotAttached is returned when the eni held by pod is no longer attached to this instance
+var ErrENINotAttached = errors.New("eni of pod is not attached to this instance")
+
+// errENIDeviceNotReady the eni is attached in ecs, but the device not appears in kernel in time
+var errENIDeviceNotReady = errors.New("eni device not ready")
+
 const (
 	// vSwitchIPCntTimeout is the duration for the vswitchIPCntMap content's effectiveness
 	vSwitchIPCntTimeout = 10 * time.Minute
 
+	// defaultENIAttachPollInterval the interval checking the device of eni attached appears in kernel
+	defaultENIAttachPollInterval = 500 * time.Millisecond
+
+	// eniTagsMaxCount the max count of tags on eni
+	eniTagsMaxCount = 20
+
+	// trunkENIIdempotentKey the key trunk eni is acquired with from pool while it's detached or recreated
+	trunkENIIdempotentKey = "trunk-eni"
+
 	typeNameENI    = "eni"
 	poolNameENI    = "eni"
 	factoryNameENI = "eni"
@@ -38,9 +58,26 @@ const (
 )
 
 type eniResourceManager struct {
-	pool     pool.ObjectPool
-	ecs      ipam.API
-	trunkENI *types.ENI
+	pool    pool.ObjectPool
+	ecs     ipam.API
+	k8s     Kubernetes
+	factory *eniFactory
+	// idleRetain keep the released eni in pool for the duration, so it can be reused by burst pods
+	idleRetain time.Duration
+	// tagWithPod tag the eni with the pod using it
+	tagWithPod bool
+	// podLabelTags the label keys of pod copied to the tags of eni, maxPodLabelTags limit the count within the tags of eni
+	podLabelTags    []string
+	maxPodLabelTags int
+	// capPolicy the eni_cap_policy of node, used for the pod not override it
+	capPolicy types.ENICapPolicy
+
+	trunkLock sync.Mutex
+	trunkENI  *types.ENI
+	// trunkIdleDetach detach the trunk eni no trunk pod used for the duration, 0 for never
+	trunkIdleDetach time.Duration
+	// trunkIdleSince the time no trunk pod found on node, zero if there are
+	trunkIdleSince time.Time
 }
 
 func newENIResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, allocatedResources map[string]resourceManagerInitItem, ipFamily *types.IPFamily, k8s Kubernetes) (ResourceManager, error) {
@@ -49,6 +86,7 @@ func newENIResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, allocated
 	if err != nil {
 		return nil, errors.Wrapf(err, "error create ENI factory")
 	}
+	factory.dualStack = ipFamily.IPv6
 
 	_ = tracing.Register(tracing.ResourceTypeFactory, factoryNameENI, factory)
 
@@ -70,6 +108,9 @@ func newENIResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, allocated
 			poolConfig.MaxPoolSize = capacity
 		}
 
+		eniLog.Infof("eni capacity %d, adapters %d, eni_cap_ratio %v, eni_cap_shift %d",
+			capacity, limit.Adapters, poolConfig.EniCapRatio, poolConfig.EniCapShift)
+
 		if poolConfig.MinENI != 0 {
 			poolConfig.MinPoolSize = poolConfig.MinENI
 		}
@@ -124,7 +165,7 @@ func newENIResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, allocated
 					}
 				}
 				if factory.trunkOnEni == "" && len(enis) < capacity-1 {
-					trunkENIRes, err := factory.CreateWithIPCount(1, true)
+					trunkENIRes, err := factory.CreateWithIPCount(context.Background(), 1, true)
 					if err != nil {
 						return errors.Wrapf(err, "error init trunk eni")
 					}
@@ -133,14 +174,23 @@ func newENIResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, allocated
 					enis = append(enis, trunkENI)
 				}
 			}
-			for _, e := range enis {
-				if ipFamily.IPv6 {
-					_, ipv6, err := ecs.GetENIIPs(ctx, e.MAC)
-					if err != nil || len(ipv6) == 0 {
+			if ipFamily.IPv6 {
+				err = restoreParallel(len(enis), poolConfig.RestoreParallelism, func(i int) error {
+					_, ipv6, err := ecs.GetENIIPs(ctx, enis[i].MAC)
+					if err != nil {
 						return errors.Wrapf(err, "error get eni ip")
 					}
-					e.PrimaryIP.IPv6 = ipv6[0]
+					if len(ipv6) == 0 {
+						return fmt.Errorf("error get eni ip, no ipv6 on eni %s", enis[i].ID)
+					}
+					enis[i].PrimaryIP.IPv6 = ipv6[0]
+					return nil
+				})
+				if err != nil {
+					return err
 				}
+			}
+			for _, e := range enis {
 				if item, ok := allocatedResources[e.GetResourceID()]; ok {
 					holder.AddInuse(e, podInfoKey(item.podInfo.Namespace, item.podInfo.Name))
 				} else {
@@ -156,9 +206,21 @@ func newENIResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, allocated
 		return nil, err
 	}
 	mgr := &eniResourceManager{
-		pool:     p,
-		ecs:      ecs,
-		trunkENI: trunkENI,
+		pool:       p,
+		ecs:        ecs,
+		k8s:        k8s,
+		trunkENI:   trunkENI,
+		factory:    factory,
+		idleRetain: poolConfig.ENIIdleRetain,
+		tagWithPod: poolConfig.TagENIWithPod,
+		capPolicy:  poolConfig.ENICapPolicy,
+
+		podLabelTags:    poolConfig.PodLabelENITags,
+		maxPodLabelTags: maxPodLabelTags(poolConfig),
+	}
+	// the trunk eni created by others is never detached by daemon
+	if poolConfig.EnableENITrunking && !poolConfig.WaitTrunkENI && memberLimit > 0 {
+		mgr.trunkIdleDetach = poolConfig.TrunkENIIdleDetach
 	}
 
 	if poolConfig.DisableDevicePlugin {
@@ -191,36 +253,329 @@ func newENIResourceManager(poolConfig *types.PoolConfig, ecs ipam.API, allocated
 }
 
 func (m *eniResourceManager) Allocate(ctx *networkContext, prefer string) (types.NetworkResource, error) {
-	return m.pool.Acquire(ctx, prefer, podInfoKey(ctx.pod.Namespace, ctx.pod.Name))
+	res, err := m.allocate(ctx, prefer)
+	if err != nil {
+		return nil, err
+	}
+	if eni, ok := res.(*types.ENI); ok {
+		m.tagPod(ctx, eni.ID, ctx.pod)
+	}
+	return res, nil
+}
+
+func (m *eniResourceManager) allocate(ctx *networkContext, prefer string) (types.NetworkResource, error) {
+	policy := m.podCapPolicy(ctx.pod)
+	if ctx.pod.VSwitchID == "" && policy == types.ENICapPolicyDefault {
+		res, err := m.pool.Acquire(ctx, prefer, podInfoKey(ctx.pod.Namespace, ctx.pod.Name))
+		if err != nil {
+			return nil, err
+		}
+		return m.checkReused(prefer, res)
+	}
+	vSwitch := ctx.pod.VSwitchID
+	match := func(trunk *bool) func(res types.NetworkResource) bool {
+		return func(res types.NetworkResource) bool {
+			eni, ok := res.(*types.ENI)
+			if !ok || (trunk != nil && eni.Trunk != *trunk) {
+				return false
+			}
+			return vSwitch == "" || eni.VSwitchID == vSwitch
+		}
+	}
+	create := func() ([]types.NetworkResource, error) {
+		if vSwitch == "" {
+			return m.factory.CreateWithIPCount(ctx, 1, false)
+		}
+		return m.factory.CreateWithIPCountOnVSwitch(ctx, 1, false, vSwitch)
+	}
+	trunk, secondary := true, false
+	key := podInfoKey(ctx.pod.Namespace, ctx.pod.Name)
+
+	var res types.NetworkResource
+	var err error
+	switch policy {
+	case types.ENICapPolicyPreferTrunk:
+		// the trunk eni is never created for pod, fall back to the secondary eni if it is not idle
+		res, err = m.pool.AcquireMatch(ctx, prefer, key, match(&trunk), nil)
+		if err != nil {
+			ctx.Log().Debugf("trunk eni not available, fall back to secondary eni: %v", err)
+			res, err = m.pool.AcquireMatch(ctx, prefer, key, match(&secondary), create)
+		}
+	case types.ENICapPolicyPreferSecondary:
+		res, err = m.pool.AcquireMatch(ctx, prefer, key, match(&secondary), create)
+		if err != nil {
+			ctx.Log().Debugf("secondary eni not available, fall back to trunk eni: %v", err)
+			res, err = m.pool.AcquireMatch(ctx, prefer, key, match(&trunk), nil)
+		}
+	default:
+		res, err = m.pool.AcquireMatch(ctx, prefer, key, match(nil), create)
+	}
+	if err != nil {
+		if vSwitch == "" {
+			return nil, fmt.Errorf("error allocate eni: %w", err)
+		}
+		return nil, fmt.Errorf("error allocate eni from vswitch %s: %w", vSwitch, err)
+	}
+	return m.checkReused(prefer, res)
+}
+
+// podCapPolicy return the eni cap policy of pod, fall back to the policy of node if not override by pod
+func (m *eniResourceManager) podCapPolicy(pod *types.PodInfo) types.ENICapPolicy {
+	if pod != nil && pod.ENICapPolicy != types.ENICapPolicyDefault {
+		return pod.ENICapPolicy
+	}
+	return m.capPolicy
+}
+
+// tagPod tag the eni with the pod using it, the failure is only logged as the tags are for debugging
+func (m *eniResourceManager) tagPod(ctx context.Context, eniID string, pod *types.PodInfo) {
+	if pod == nil {
+		return
+	}
+	tags := m.podLabelTagsOf(pod)
+	if m.tagWithPod {
+		tags[types.TagKubernetesPodNamespace] = truncateTagValue(pod.Namespace)
+		tags[types.TagKubernetesPodName] = truncateTagValue(pod.Name)
+	}
+	if len(tags) == 0 {
+		return
+	}
+	err := m.ecs.TagNetworkInterface(ctx, eniID, tags)
+	if err != nil {
+		eniLog.Warnf("error tag eni %s with pod %s: %v", eniID, podInfoKey(pod.Namespace, pod.Name), err)
+	}
+}
+
+// podLabelTagsOf return the tags copied from the labels of pod, the ones exceed the tag count limit of eni are dropped
+func (m *eniResourceManager) podLabelTagsOf(pod *types.PodInfo) map[string]string {
+	tags := make(map[string]string)
+	for _, key := range m.podLabelTags {
+		value, ok := pod.Labels[key]
+		if !ok {
+			continue
+		}
+		if len(tags) >= m.maxPodLabelTags {
+			eniLog.Warnf("drop label %s of pod %s from eni tags, exceed the tag count limit %d of eni", key, podInfoKey(pod.Namespace, pod.Name), eniTagsMaxCount)
+			continue
+		}
+		tags[key] = truncateTagValue(value)
+	}
+	return tags
+}
+
+// maxPodLabelTags the count of pod labels can be tagged on eni besides the tags created with and the pod tags
+func maxPodLabelTags(poolConfig *types.PoolConfig) int {
+	count := eniTagsMaxCount - len(poolConfig.ENITags) - 1
+	if poolConfig.TagENIWithPod {
+		count -= 2
+	}
+	if count < 0 {
+		return 0
+	}
+	return count
+}
+
+// eniIDOf return the id of the eni resource, which is keyed by mac in pool. the record stored by old version
+// has no eni id, it is looked up from pool
+func (m *eniResourceManager) eniIDOf(resItem types.ResourceItem) string {
+	if resItem.ENIID != "" {
+		return resItem.ENIID
+	}
+	res, err := m.pool.Stat(resItem.ID)
+	if err != nil {
+		return ""
+	}
+	eni, ok := res.(*types.ENI)
+	if !ok {
+		return ""
+	}
+	return eni.ID
+}
+
+// untagPod remove the pod tags from the eni released
+func (m *eniResourceManager) untagPod(eniID string) {
+	keys := append([]string(nil), m.podLabelTags...)
+	if m.tagWithPod {
+		keys = append(keys, types.TagKubernetesPodNamespace, types.TagKubernetesPodName)
+	}
+	if len(keys) == 0 {
+		return
+	}
+	err := m.ecs.UntagNetworkInterface(context.Background(), eniID, keys)
+	if err != nil {
+		eniLog.Warnf("error untag pod of eni %s: %v", eniID, err)
+	}
+}
+
+// truncateTagValue keep the value within the length limit of ecs tag
+func truncateTagValue(value string) string {
+	runes := []rune(value)
+	if len(runes) > tagValueMaxLength {
+		return string(runes[:tagValueMaxLength])
+	}
+	return value
+}
+
+// checkReused check the eni reused by pod is still attached, which may be grabbed by other instance on node replacement.
+// the eni not attached is removed from pool, it should not be handed out again as idle
+func (m *eniResourceManager) checkReused(prefer string, res types.NetworkResource) (types.NetworkResource, error) {
+	if prefer == "" || res.GetResourceID() != prefer {
+		return res, nil
+	}
+	err := m.factory.Check(res)
+	if !errors.Is(err, apiErr.ErrNotFound) {
+		return res, nil
+	}
+	eniLog.Warnf("eni %s held by pod is not attached, remove it from pool", prefer)
+	if err = m.pool.Remove(prefer); err != nil {
+		eniLog.Warnf("error remove eni %s from pool: %v", prefer, err)
+	}
+	return nil, errors.Wrapf(ErrENINotAttached, "eni %s", prefer)
 }
 
 func (m *eniResourceManager) Release(context *networkContext, resItem types.ResourceItem) error {
-	if context != nil && context.pod != nil {
-		return m.pool.ReleaseWithReservation(resItem.ID, context.pod.IPStickTime)
+	if eniID := m.eniIDOf(resItem); eniID != "" {
+		m.untagPod(eniID)
+	}
+	// the reserved eni is kept in pool even above max idle, the capacity is bounded by max eni
+	reservation := m.idleRetain
+	if context != nil && context.pod != nil && context.pod.IPStickTime > reservation {
+		reservation = context.pod.IPStickTime
 	}
-	return m.pool.Release(resItem.ID)
+	return m.pool.ReleaseWithReservation(resItem.ID, reservation)
 }
 
-func (m *eniResourceManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) error {
+func (m *eniResourceManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) (int, error) {
+	released := 0
 	for expireRes, expireItem := range expireResSet {
 		if _, err := m.pool.Stat(expireRes); err == nil {
 			err = m.Release(nil, expireItem)
 			if err != nil {
-				return err
+				return released, &resourceGCError{resID: expireRes, err: err}
 			}
+			released++
 		}
 	}
-	return nil
+	return released, nil
 }
 
 func (m *eniResourceManager) Stat(context *networkContext, resID string) (types.NetworkResource, error) {
 	return m.pool.Stat(resID)
 }
 
+func (m *eniResourceManager) Free() int {
+	return m.pool.Free()
+}
+
+func (m *eniResourceManager) Capacity() (creatable, capacity int) {
+	return m.pool.Capacity()
+}
+
+func (m *eniResourceManager) Warm(ctx context.Context, idle int) (int, error) {
+	return m.pool.Warm(ctx, idle)
+}
+
+func (m *eniResourceManager) Drain(ctx context.Context) (int, error) {
+	return m.pool.Drain(ctx)
+}
+
+func (m *eniResourceManager) Resize(minIdle, maxIdle int) error {
+	return m.pool.Resize(minIdle, maxIdle)
+}
+
 func (m *eniResourceManager) GetResourceMapping() (tracing.ResourcePoolStats, error) {
 	return m.pool.GetResourceMapping()
 }
 
+// getTrunkENI return the trunk eni of node, nil if not created or detached for idle
+func (m *eniResourceManager) getTrunkENI() *types.ENI {
+	m.trunkLock.Lock()
+	defer m.trunkLock.Unlock()
+	return m.trunkENI
+}
+
+// checkIdleTrunk detach the trunk eni if there is no trunk pod on node for the idle duration
+func (m *eniResourceManager) checkIdleTrunk(trunkPods int) {
+	if m.trunkIdleDetach <= 0 {
+		return
+	}
+	m.trunkLock.Lock()
+	defer m.trunkLock.Unlock()
+	if m.trunkENI == nil || trunkPods > 0 {
+		m.trunkIdleSince = time.Time{}
+		return
+	}
+	if m.trunkIdleSince.IsZero() {
+		m.trunkIdleSince = time.Now()
+		return
+	}
+	if time.Since(m.trunkIdleSince) < m.trunkIdleDetach {
+		return
+	}
+	err := m.detachTrunkLocked()
+	if err != nil {
+		eniLog.Warnf("error detach idle trunk eni: %v", err)
+	}
+}
+
+func (m *eniResourceManager) detachTrunkLocked() error {
+	trunk := m.trunkENI
+	// the eni is keyed by mac in pool
+	resID := trunk.GetResourceID()
+	_, err := m.pool.AcquireMatch(context.Background(), resID, trunkENIIdempotentKey, func(res types.NetworkResource) bool {
+		return res.GetResourceID() == resID
+	}, nil)
+	if err != nil {
+		return errors.Wrapf(err, "error acquire trunk eni %s from pool", trunk.ID)
+	}
+	err = m.ecs.FreeENI(context.Background(), trunk.ID, m.factory.instanceID)
+	if err != nil {
+		_ = m.pool.Release(resID)
+		return errors.Wrapf(err, "error free trunk eni %s", trunk.ID)
+	}
+	_ = m.pool.Remove(resID)
+	m.trunkENI, m.trunkIdleSince = nil, time.Time{}
+	m.factory.trunkOnEni = ""
+
+	msg := fmt.Sprintf("trunk eni %s is detached, no trunk pod for %v", trunk.ID, m.trunkIdleDetach)
+	eniLog.Info(msg)
+	_ = tracing.RecordNodeEvent(corev1.EventTypeNormal, "TrunkENIDetached", msg)
+	return m.k8s.PatchTrunkInfo("")
+}
+
+// ensureTrunk recreate the trunk eni detached for idle, as a trunk pod arrived
+func (m *eniResourceManager) ensureTrunk(ctx context.Context) error {
+	if m.trunkIdleDetach <= 0 {
+		return nil
+	}
+	m.trunkLock.Lock()
+	defer m.trunkLock.Unlock()
+	m.trunkIdleSince = time.Time{}
+	if m.trunkENI != nil {
+		return nil
+	}
+	res, err := m.pool.AcquireMatch(ctx, "", trunkENIIdempotentKey, func(res types.NetworkResource) bool {
+		eni, ok := res.(*types.ENI)
+		return ok && eni.Trunk
+	}, func() ([]types.NetworkResource, error) {
+		return m.factory.CreateWithIPCount(ctx, 1, true)
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error recreate trunk eni")
+	}
+	trunk := res.(*types.ENI)
+	trunk.Trunk = true
+	// kept idle in pool as the trunk eni found on startup
+	_ = m.pool.Release(trunk.GetResourceID())
+	m.trunkENI = trunk
+	m.factory.trunkOnEni = trunk.ID
+
+	msg := fmt.Sprintf("trunk eni %s is recreated for trunk pod", trunk.ID)
+	eniLog.Info(msg)
+	_ = tracing.RecordNodeEvent(corev1.EventTypeNormal, "TrunkENIRecreated", msg)
+	return m.k8s.PatchTrunkInfo(trunk.ID)
+}
+
 // MapSorter is a slice container for sorting
 type MapSorter []Item
 
@@ -253,6 +608,8 @@ type eniFactory struct {
 	enableTrunk               bool
 	trunkOnEni                string
 	switches                  []string
+	fallbackSwitches          []string
+	zone                      string
 	eniTags                   map[string]string
 	securityGroups            []string
 	instanceID                string
@@ -261,6 +618,18 @@ type eniFactory struct {
 	tsExpireAt                time.Time
 	vswitchSelectionPolicy    string
 	disableSecurityGroupCheck bool
+	// vSwitchWeights weight of the vswitches for weighted round robin selection
+	vSwitchWeights map[string]int
+	// dualStack prefer the vswitches with ipv6 cidr, vSwitchIPv6 cache whether the vswitch has ipv6 cidr
+	dualStack         bool
+	vSwitchIPv6       map[string]bool
+	noDualStackWarned bool
+	// createLimiter rate limit of eni creation, nil for unlimited
+	createLimiter flowcontrol.RateLimiter
+	// attachTimeout wait for the device of eni created appears in kernel, 0 for not wait
+	attachTimeout      time.Duration
+	attachPollInterval time.Duration
+	deviceNumber       func(mac string) (int32, error)
 	sync.RWMutex
 }
 
@@ -272,16 +641,36 @@ func newENIFactory(poolConfig *types.PoolConfig, ecs ipam.API) (*eniFactory, err
 		}
 		poolConfig.SecurityGroups = securityGroups
 	}
+	var createLimiter flowcontrol.RateLimiter
+	if poolConfig.ENICreateQPS > 0 {
+		burst := poolConfig.ENICreateBurst
+		if burst <= 0 {
+			burst = 1
+		}
+		createLimiter = flowcontrol.NewTokenBucketRateLimiter(poolConfig.ENICreateQPS, burst)
+	}
+	attachPollInterval := poolConfig.ENIAttachPollInterval
+	if attachPollInterval <= 0 {
+		attachPollInterval = defaultENIAttachPollInterval
+	}
 	return &eniFactory{
 		name:                      factoryNameENI,
+		createLimiter:             createLimiter,
+		attachTimeout:             poolConfig.ENIAttachTimeout,
+		attachPollInterval:        attachPollInterval,
+		deviceNumber:              link.GetDeviceNumber,
 		switches:                  poolConfig.VSwitch,
+		fallbackSwitches:          poolConfig.FallbackVSwitch,
+		zone:                      poolConfig.Zone,
 		eniTags:                   poolConfig.ENITags,
 		securityGroups:            poolConfig.SecurityGroups,
 		enableTrunk:               poolConfig.EnableENITrunking,
 		instanceID:                poolConfig.InstanceID,
 		ecs:                       ecs,
 		vswitchIPCntMap:           make(map[string]int),
+		vSwitchIPv6:               make(map[string]bool),
 		vswitchSelectionPolicy:    poolConfig.VSwitchSelectionPolicy,
+		vSwitchWeights:            poolConfig.VSwitchWeights,
 		disableSecurityGroupCheck: poolConfig.DisableSecurityGroupCheck,
 	}, nil
 }
@@ -294,7 +683,7 @@ func (f *eniFactory) GetVSwitches() ([]string, error) {
 	// If there is ONLY ONE vswitch, then there is no need for ordering per switches' available IP counts,
 	// return the slice with only this vswitch.
 	if vswCnt == 1 {
-		return f.switches, nil
+		return f.preferDualStack(f.switches), nil
 	}
 
 	if f.vswitchSelectionPolicy == types.VSwitchSelectionPolicyRandom {
@@ -302,7 +691,11 @@ func (f *eniFactory) GetVSwitches() ([]string, error) {
 		copy(vSwitches, f.switches)
 		rand.Seed(time.Now().UnixNano())
 		rand.Shuffle(vswCnt, func(i, j int) { vSwitches[i], vSwitches[j] = vSwitches[j], vSwitches[i] })
-		return vSwitches, nil
+		return f.preferDualStack(vSwitches), nil
+	}
+
+	if f.vswitchSelectionPolicy == types.VSwitchSelectionPolicyWeightedRoundRobin {
+		return f.preferDualStack(weightedShuffle(f.switches, f.vSwitchWeights)), nil
 	}
 
 	if f.vswitchSelectionPolicy == types.VSwitchSelectionPolicyOrdered {
@@ -345,28 +738,240 @@ func (f *eniFactory) GetVSwitches() ([]string, error) {
 		}
 	}
 
-	return vSwitches, nil
+	return f.preferDualStack(vSwitches), nil
+}
+
+// weightedShuffle order the vswitches by picking the remaining ones with the probability of their weights,
+// the vswitch without weight has weight 1
+func weightedShuffle(vSwitches []string, weights map[string]int) []string {
+	remaining := make([]string, len(vSwitches))
+	copy(remaining, vSwitches)
+	weightOf := func(vSwitch string) int {
+		if w, ok := weights[vSwitch]; ok && w > 0 {
+			return w
+		}
+		return 1
+	}
+	total := 0
+	for _, vSwitch := range remaining {
+		total += weightOf(vSwitch)
+	}
+	result := make([]string, 0, len(remaining))
+	for len(remaining) > 0 {
+		n := rand.Intn(total)
+		i := 0
+		for ; i < len(remaining)-1; i++ {
+			n -= weightOf(remaining[i])
+			if n < 0 {
+				break
+			}
+		}
+		total -= weightOf(remaining[i])
+		result = append(result, remaining[i])
+		remaining = append(remaining[:i], remaining[i+1:]...)
+	}
+	return result
+}
+
+// preferDualStack move the vswitches with ipv6 cidr ahead in dual stack, as the ipv6 can't be allocated from the others
+func (f *eniFactory) preferDualStack(vSwitches []string) []string {
+	if !f.dualStack {
+		return vSwitches
+	}
+	dual := make([]string, 0, len(vSwitches))
+	var single []string
+	for _, vSwitch := range vSwitches {
+		if f.vSwitchDualStack(vSwitch) {
+			dual = append(dual, vSwitch)
+		} else {
+			single = append(single, vSwitch)
+		}
+	}
+	if len(dual) == 0 {
+		f.Lock()
+		warned := f.noDualStackWarned
+		f.noDualStackWarned = true
+		f.Unlock()
+		if !warned {
+			msg := fmt.Sprintf("none of vswitches %v has ipv6 cidr, ipv6 of dual stack pods can't be allocated", vSwitches)
+			eniLog.Warn(msg)
+			_ = tracing.RecordNodeEvent(corev1.EventTypeWarning, "VSwitchNoIPv6", msg)
+		}
+		return vSwitches
+	}
+	return append(dual, single...)
+}
+
+// vSwitchDualStack return whether the vswitch has ipv6 cidr, cached as it's rarely changed. the vswitch failed
+// to describe is taken as dual stack, so it's not put behind the others for a transient error
+func (f *eniFactory) vSwitchDualStack(vSwitch string) bool {
+	f.RLock()
+	ipv6, ok := f.vSwitchIPv6[vSwitch]
+	f.RUnlock()
+	if ok {
+		return ipv6
+	}
+	vsw, err := f.ecs.DescribeVSwitchByID(context.Background(), vSwitch)
+	if err != nil {
+		eniLog.Warnf("error describe vswitch %s for ipv6 cidr, %v", vSwitch, err)
+		return true
+	}
+	ipv6 = vsw.Ipv6CidrBlock != ""
+	if !ipv6 {
+		eniLog.Warnf("vswitch %s has no ipv6 cidr, used for dual stack only when no other vswitch has", vSwitch)
+	}
+	f.Lock()
+	f.vSwitchIPv6[vSwitch] = ipv6
+	f.Unlock()
+	return ipv6
 }
 
 func (f *eniFactory) Create(int) ([]types.NetworkResource, error) {
-	return f.CreateWithIPCount(1, false)
+	return f.CreateWithIPCount(context.Background(), 1, false)
+}
+
+func (f *eniFactory) CreateWithIPCount(ctx context.Context, count int, trunk bool) ([]types.NetworkResource, error) {
+	return f.CreateWithIPCountOnVSwitch(ctx, count, trunk, "")
 }
 
-func (f *eniFactory) CreateWithIPCount(count int, trunk bool) ([]types.NetworkResource, error) {
+// CreateWithIPCountOnVSwitch create eni on the vswitch given, the configured vswitches are used if vSwitch is empty.
+// the creation waits for the rate limit up to the deadline of ctx
+func (f *eniFactory) CreateWithIPCountOnVSwitch(ctx context.Context, count int, trunk bool, vSwitch string) ([]types.NetworkResource, error) {
+	if vSwitch != "" {
+		if err := f.waitCreate(ctx); err != nil {
+			return nil, err
+		}
+		eni, err := f.allocateENI(vSwitch, trunk, count, f.allocateTags())
+		if err != nil {
+			return nil, fmt.Errorf("error allocate eni on vswitch %s: %w", vSwitch, err)
+		}
+		eni.VSwitchID = vSwitch
+		return []types.NetworkResource{eni}, nil
+	}
+
 	vSwitches, _ := f.GetVSwitches()
 	eniLog.Infof("adjusted vswitch slice: %+v", vSwitches)
 
+	tags := f.allocateTags()
+	var (
+		eni *types.ENI
+		err error
+	)
+	candidates := make([]string, 0, len(vSwitches)+len(f.fallbackSwitches))
+	candidates = append(candidates, vSwitches...)
+	candidates = append(candidates, f.fallbackSwitches...)
+	// try next vswitch when the vswitch has no available ip
+	for i, vSwitch := range candidates {
+		if i >= len(vSwitches) && !f.inZone(vSwitch) {
+			continue
+		}
+		if err = f.waitCreate(ctx); err != nil {
+			return nil, err
+		}
+		eni, err = f.allocateENI(vSwitch, trunk, count, tags)
+		if err == nil {
+			eni.VSwitchID = vSwitch
+			eniLog.Infof("eni %s allocated from vswitch %s", eni.ID, vSwitch)
+			break
+		}
+		if !strings.Contains(err.Error(), apiErr.InvalidVSwitchIDIPNotEnough) {
+			return nil, err
+		}
+		eniLog.Warnf("vswitch %s has no available ip, try next one", vSwitch)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []types.NetworkResource{eni}, nil
+}
+
+// inZone return true if the vswitch is in the zone of instance, the eni can not be created on the vswitch of other zones
+func (f *eniFactory) inZone(vSwitch string) bool {
+	if f.zone == "" {
+		return true
+	}
+	vsw, err := f.ecs.DescribeVSwitchByID(context.Background(), vSwitch)
+	if err != nil {
+		eniLog.Warnf("error get zone of fallback vswitch %s, skip it: %v", vSwitch, err)
+		return false
+	}
+	if vsw.ZoneId != f.zone {
+		eniLog.Debugf("skip fallback vswitch %s in zone %s, not the zone %s of instance", vSwitch, vsw.ZoneId, f.zone)
+		return false
+	}
+	return true
+}
+
+// allocateENI create and attach the eni, then wait for the device of it appears in kernel
+func (f *eniFactory) allocateENI(vSwitch string, trunk bool, count int, tags map[string]string) (*types.ENI, error) {
+	start := time.Now()
+	eni, err := f.ecs.AllocateENI(context.Background(), vSwitch, f.securityGroups, f.instanceID, trunk, count, tags)
+	if err != nil {
+		metric.ENIAttachLatency.WithLabelValues(metric.ENIAttachFail).Observe(metric.MsSince(start))
+		return nil, err
+	}
+	err = f.waitDevice(eni)
+	if err == nil {
+		metric.ENIAttachLatency.WithLabelValues(metric.ENIAttachSucceed).Observe(metric.MsSince(start))
+		return eni, nil
+	}
+	if !errors.Is(err, errENIDeviceNotReady) {
+		metric.ENIAttachLatency.WithLabelValues(metric.ENIAttachFail).Observe(metric.MsSince(start))
+	} else {
+		metric.ENIAttachLatency.WithLabelValues(metric.ENIAttachNotInKernel).Observe(metric.MsSince(start))
+		_ = tracing.RecordNodeEvent(corev1.EventTypeWarning, "ENIDeviceNotReady", err.Error())
+	}
+	// the eni is attached in ecs, free it instead of leaking
+	if freeErr := f.ecs.FreeENI(context.Background(), eni.ID, f.instanceID); freeErr != nil {
+		eniLog.Errorf("error free eni %s not ready, may cause eni leak: %v", eni.ID, freeErr)
+	}
+	return nil, err
+}
+
+// waitDevice wait for the device of eni appears in kernel, the eni attached in ecs may not show up in kernel for a while
+func (f *eniFactory) waitDevice(eni *types.ENI) error {
+	if f.attachTimeout <= 0 {
+		return nil
+	}
+	var lastErr error
+	err := wait.PollImmediate(f.attachPollInterval, f.attachTimeout, func() (bool, error) {
+		_, lastErr = f.deviceNumber(eni.MAC)
+		if lastErr == nil {
+			return true, nil
+		}
+		if errors.Is(lastErr, link.ErrNotFound) {
+			return false, nil
+		}
+		return false, lastErr
+	})
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return fmt.Errorf("%w: eni %s attached but the device not found in kernel after %s", errENIDeviceNotReady, eni.ID, f.attachTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("error wait device of eni %s, %w", eni.ID, err)
+	}
+	return nil
+}
+
+// waitCreate wait for the rate limit of eni creation, gives up if the limit can not be satisfied before the deadline of ctx
+func (f *eniFactory) waitCreate(ctx context.Context) error {
+	if f.createLimiter == nil {
+		return nil
+	}
+	if err := f.createLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("error wait for rate limit of eni creation, %w", err)
+	}
+	return nil
+}
+
+func (f *eniFactory) allocateTags() map[string]string {
 	tags := map[string]string{
 		types.NetworkInterfaceTagCreatorKey: types.NetworkInterfaceTagCreatorValue,
 	}
 	for k, v := range f.eniTags {
 		tags[k] = v
 	}
-	eni, err := f.ecs.AllocateENI(context.Background(), vSwitches[0], f.securityGroups, f.instanceID, trunk, count, tags)
-	if err != nil {
-		return nil, err
-	}
-	return []types.NetworkResource{eni}, nil
+	return tags
 }
 
 func (f *eniFactory) Dispose(resource types.NetworkResource) error {
@@ -374,7 +979,15 @@ func (f *eniFactory) Dispose(resource types.NetworkResource) error {
 	if f.enableTrunk && eni.Trunk {
 		return fmt.Errorf("trunk ENI %+v will not dispose", eni.ID)
 	}
-	return f.ecs.FreeENI(context.Background(), eni.ID, f.instanceID)
+	err := f.ecs.FreeENI(context.Background(), eni.ID, f.instanceID)
+	if errors.Is(err, apiErr.ErrENIInUseByOther) {
+		// nothing to free, drop it from pool instead of retrying forever
+		msg := fmt.Sprintf("eni %s is in use by other instance, drop it", eni.ID)
+		eniLog.Warn(msg)
+		_ = tracing.RecordNodeEvent(corev1.EventTypeWarning, "ENIInUseByOther", msg)
+		return nil
+	}
+	return err
 }
 
 func (f *eniFactory) Config() []tracing.MapKeyValueEntry {
@@ -400,6 +1013,15 @@ func (f *eniFactory) Trace() []tracing.MapKeyValueEntry {
 		})
 	}
 
+	f.RLock()
+	for vs, ipv6 := range f.vSwitchIPv6 {
+		trace = append(trace, tracing.MapKeyValueEntry{
+			Key:   fmt.Sprintf("vswitch/%s/ipv6", vs),
+			Value: fmt.Sprint(ipv6),
+		})
+	}
+	f.RUnlock()
+
 	return trace
 }
 
@@ -0,0 +1,36 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/metric"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// eniQuotaCheckPeriod the eni quota is checked infrequently as listing eni may call the openapi
+const eniQuotaCheckPeriod = time.Hour
+
+// checkENIQuota report the eni usage of instance, alert by node event when it is approaching the quota
+func (n *networkService) checkENIQuota() {
+	if n.eniQuotaAlertRatio <= 0 || n.eniQuota <= 0 || time.Since(n.eniQuotaCheckedAt) < eniQuotaCheckPeriod {
+		return
+	}
+	n.eniQuotaCheckedAt = time.Now()
+
+	enis, err := n.ecs.GetAttachedENIs(context.Background(), true, "")
+	if err != nil {
+		serviceLog.Errorf("error get attached eni for quota check, %v", err)
+		return
+	}
+	ratio := float64(len(enis)) / float64(n.eniQuota)
+	metric.ENIQuotaUsage.Set(ratio)
+	if ratio < n.eniQuotaAlertRatio {
+		return
+	}
+	msg := fmt.Sprintf("%d enis attached, quota of instance %d, above %.0f%% of the quota", len(enis), n.eniQuota, n.eniQuotaAlertRatio*100)
+	serviceLog.Warn(msg)
+	n.k8s.RecordNodeEvent(corev1.EventTypeWarning, "ENIQuotaApproaching", msg)
+}
@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/ipam"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeAttachedAPI struct {
+	ipam.API
+	enis  []*types.ENI
+	calls int
+}
+
+func (f *fakeAttachedAPI) GetAttachedENIs(ctx context.Context, containsMainENI bool, trunkENIID string) ([]*types.ENI, error) {
+	f.calls++
+	return f.enis, nil
+}
+
+func Test_networkService_checkENIQuota(t *testing.T) {
+	k8s := &eventRecorderK8s{}
+	api := &fakeAttachedAPI{enis: []*types.ENI{{ID: "eni-1"}, {ID: "eni-2"}, {ID: "eni-3"}}}
+	n := &networkService{k8s: k8s, ecs: api, eniQuota: 4, eniQuotaAlertRatio: 0.8}
+
+	n.checkENIQuota()
+	assert.Empty(t, k8s.events)
+
+	// checked infrequently
+	api.enis = append(api.enis, &types.ENI{ID: "eni-4"})
+	n.checkENIQuota()
+	assert.Equal(t, 1, api.calls)
+	assert.Empty(t, k8s.events)
+
+	n.eniQuotaCheckedAt = time.Time{}
+	n.checkENIQuota()
+	assert.Equal(t, []string{"ENIQuotaApproaching"}, k8s.events)
+}
@@ -1,7 +1,23 @@
 package daemon
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
+
+	apiErr "github.com/AliyunContainerService/terway/pkg/aliyun/client/errors"
+	"github.com/AliyunContainerService/terway/pkg/ipam"
+	"github.com/AliyunContainerService/terway/pkg/link"
+	"github.com/AliyunContainerService/terway/pkg/pool"
+	"github.com/AliyunContainerService/terway/pkg/tracing"
+	"github.com/AliyunContainerService/terway/types"
+	"github.com/AliyunContainerService/terway/types/daemon"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/util/flowcontrol"
 )
 
 func TestMapSorter(t *testing.T) {
@@ -18,3 +34,458 @@ func TestMapSorter(t *testing.T) {
 		}
 	}
 }
+
+func Test_eniFactory_createRateLimit(t *testing.T) {
+	f, err := newENIFactory(&types.PoolConfig{SecurityGroups: []string{"sg-1"}, ENICreateQPS: 1}, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, f.createLimiter)
+	assert.NoError(t, f.waitCreate(context.Background()))
+
+	f, err = newENIFactory(&types.PoolConfig{SecurityGroups: []string{"sg-1"}}, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, f.createLimiter)
+	assert.NoError(t, f.waitCreate(context.Background()))
+
+	api := &fakeTrunkAPI{}
+	f = &eniFactory{ecs: api, switches: []string{"vsw-1"}, createLimiter: flowcontrol.NewTokenBucketRateLimiter(0.001, 1)}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = f.CreateWithIPCount(ctx, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, api.allocated)
+
+	// eni not created when the limit is not satisfied before the deadline of caller
+	_, err = f.CreateWithIPCount(ctx, 1, false)
+	assert.Error(t, err)
+	_, err = f.CreateWithIPCountOnVSwitch(ctx, 1, false, "vsw-1")
+	assert.Error(t, err)
+	assert.Equal(t, 1, api.allocated)
+
+	assert.Error(t, validateConfig(&daemon.Config{ENICreateRateLimit: -1}))
+}
+
+func Test_eniFactory_waitDevice(t *testing.T) {
+	eni := &types.ENI{ID: "eni-1", MAC: "00:00:00:00:00:01"}
+	calls := 0
+	f := &eniFactory{attachTimeout: time.Second, attachPollInterval: 10 * time.Millisecond, deviceNumber: func(mac string) (int32, error) {
+		calls++
+		if calls < 3 {
+			return 0, fmt.Errorf("%w: dev %s", link.ErrNotFound, mac)
+		}
+		return 3, nil
+	}}
+	assert.NoError(t, f.waitDevice(eni))
+	assert.Equal(t, 3, calls)
+
+	// attached in ecs but not in kernel
+	f.attachTimeout = 50 * time.Millisecond
+	f.deviceNumber = func(mac string) (int32, error) {
+		return 0, link.ErrNotFound
+	}
+	assert.True(t, errors.Is(f.waitDevice(eni), errENIDeviceNotReady))
+
+	// real failure is not retried
+	calls = 0
+	f.deviceNumber = func(mac string) (int32, error) {
+		calls++
+		return 0, fmt.Errorf("netlink failed")
+	}
+	err := f.waitDevice(eni)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, errENIDeviceNotReady))
+	assert.Equal(t, 1, calls)
+
+	// not wait
+	f.attachTimeout = 0
+	assert.NoError(t, f.waitDevice(eni))
+}
+
+func Test_weightedShuffle(t *testing.T) {
+	first := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		vSwitches := weightedShuffle([]string{"vsw-1", "vsw-2", "vsw-3"}, map[string]int{"vsw-1": 18, "vsw-2": 1})
+		assert.ElementsMatch(t, []string{"vsw-1", "vsw-2", "vsw-3"}, vSwitches)
+		first[vSwitches[0]]++
+	}
+	// vsw-1 is picked first with probability 0.9
+	assert.Greater(t, first["vsw-1"], 800)
+	assert.Greater(t, first["vsw-2"], 0)
+	assert.Greater(t, first["vsw-3"], 0)
+
+	f := &eniFactory{switches: []string{"vsw-1", "vsw-2"}, vswitchSelectionPolicy: types.VSwitchSelectionPolicyWeightedRoundRobin}
+	vSwitches, err := f.GetVSwitches()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"vsw-1", "vsw-2"}, vSwitches)
+
+	assert.Error(t, validateConfig(&daemon.Config{VSwitchWeights: map[string]int{"vsw-1": 0}}))
+}
+
+func Test_eniFactory_preferDualStack(t *testing.T) {
+	api := &fakeVSwitchAPI{ipv4Only: []string{"vsw-1", "vsw-3"}}
+	f := &eniFactory{ecs: api, vSwitchIPv6: make(map[string]bool), dualStack: true}
+
+	assert.Equal(t, []string{"vsw-2", "vsw-4", "vsw-1", "vsw-3"}, f.preferDualStack([]string{"vsw-1", "vsw-2", "vsw-3", "vsw-4"}))
+	// cached
+	assert.Equal(t, []string{"vsw-2", "vsw-1"}, f.preferDualStack([]string{"vsw-1", "vsw-2"}))
+	assert.Equal(t, 4, api.calls)
+	assert.Contains(t, f.Trace(), tracing.MapKeyValueEntry{Key: "vswitch/vsw-1/ipv6", Value: "false"})
+
+	// fall back to the order given when none has ipv6
+	assert.Equal(t, []string{"vsw-3", "vsw-1"}, f.preferDualStack([]string{"vsw-3", "vsw-1"}))
+	assert.True(t, f.noDualStackWarned)
+
+	f.dualStack = false
+	assert.Equal(t, []string{"vsw-1", "vsw-2"}, f.preferDualStack([]string{"vsw-1", "vsw-2"}))
+}
+
+type fakeExhaustedAPI struct {
+	fakeVSwitchAPI
+	// exhausted the vswitches have no available ip
+	exhausted []string
+	tried     []string
+}
+
+func (f *fakeExhaustedAPI) AllocateENI(ctx context.Context, vSwitch string, securityGroups []string, instanceID string, trunk bool, ipCount int, eniTags map[string]string) (*types.ENI, error) {
+	f.tried = append(f.tried, vSwitch)
+	for _, v := range f.exhausted {
+		if v == vSwitch {
+			return nil, fmt.Errorf("error create eni, %s", apiErr.InvalidVSwitchIDIPNotEnough)
+		}
+	}
+	return &types.ENI{ID: "eni-" + vSwitch}, nil
+}
+
+func Test_eniFactory_fallbackVSwitch(t *testing.T) {
+	api := &fakeExhaustedAPI{
+		fakeVSwitchAPI: fakeVSwitchAPI{zones: map[string]string{"vsw-i": "cn-hangzhou-i", "vsw-j": "cn-hangzhou-j", "vsw-i2": "cn-hangzhou-i"}},
+		exhausted:      []string{"vsw-i"},
+	}
+	f := &eniFactory{ecs: api, switches: []string{"vsw-i"}, fallbackSwitches: []string{"vsw-j", "vsw-i2"}, zone: "cn-hangzhou-i"}
+
+	// the fallback vswitch in other zone is skipped
+	res, err := f.CreateWithIPCount(context.Background(), 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "vsw-i2", res[0].(*types.ENI).VSwitchID)
+	assert.Equal(t, []string{"vsw-i", "vsw-i2"}, api.tried)
+
+	api.tried, api.exhausted = nil, []string{"vsw-i", "vsw-i2"}
+	_, err = f.CreateWithIPCount(context.Background(), 1, false)
+	assert.ErrorContains(t, err, apiErr.InvalidVSwitchIDIPNotEnough)
+	assert.Equal(t, []string{"vsw-i", "vsw-i2"}, api.tried)
+}
+
+type fakeDetachedAPI struct {
+	ipam.API
+}
+
+func (f *fakeDetachedAPI) GetENIByMac(ctx context.Context, mac string) (*types.ENI, error) {
+	return nil, apiErr.ErrNotFound
+}
+
+func Test_eniResourceManager_checkReused(t *testing.T) {
+	eni := &types.ENI{ID: "eni-1", MAC: "00:00:00:00:00:01", VSwitchID: "vsw-1"}
+	p := &fakeTrunkPool{fakeMatchPool: fakeMatchPool{idle: []types.NetworkResource{eni}}}
+	m := &eniResourceManager{pool: p, factory: &eniFactory{ecs: &fakeDetachedAPI{}}}
+
+	// not reused
+	res, err := m.checkReused("", eni)
+	assert.NoError(t, err)
+	assert.Equal(t, eni, res)
+
+	// the eni not attached is removed from pool instead of released as idle
+	_, err = m.checkReused(eni.GetResourceID(), eni)
+	assert.ErrorIs(t, err, ErrENINotAttached)
+	assert.Equal(t, []string{eni.GetResourceID()}, p.removed)
+	assert.Empty(t, p.released)
+
+	// checked on allocating from the vswitch of pod too
+	ctx := &networkContext{
+		Context: context.Background(),
+		pod:     &types.PodInfo{Name: "foo", Namespace: "default", VSwitchID: "vsw-1"},
+	}
+	_, err = m.allocate(ctx, eni.GetResourceID())
+	assert.ErrorIs(t, err, ErrENINotAttached)
+	assert.Equal(t, []string{eni.GetResourceID(), eni.GetResourceID()}, p.removed)
+}
+
+type fakeMatchPool struct {
+	pool.ObjectPool
+	idle []types.NetworkResource
+}
+
+func (f *fakeMatchPool) AcquireMatch(ctx context.Context, resID, idempotentKey string, match func(types.NetworkResource) bool, create func() ([]types.NetworkResource, error)) (types.NetworkResource, error) {
+	for _, res := range f.idle {
+		if match(res) {
+			return res, nil
+		}
+	}
+	return nil, pool.ErrNotFound
+}
+
+func Test_eniResourceManager_capPolicy(t *testing.T) {
+	trunk := &types.ENI{ID: "eni-trunk", VSwitchID: "vsw-1", Trunk: true}
+	secondary := &types.ENI{ID: "eni-1", VSwitchID: "vsw-1"}
+	m := &eniResourceManager{
+		pool:      &fakeMatchPool{idle: []types.NetworkResource{trunk, secondary}},
+		capPolicy: types.ENICapPolicyPreferTrunk,
+	}
+	ctx := &networkContext{
+		Context: context.Background(),
+		pod:     &types.PodInfo{Name: "foo", Namespace: "default", ENICapPolicy: types.ENICapPolicyPreferSecondary},
+	}
+
+	res, err := m.allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, secondary, res)
+
+	ctx.pod.VSwitchID = "vsw-1"
+	res, err = m.allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, secondary, res)
+
+	// the pod not override it use the policy of node
+	assert.Equal(t, types.ENICapPolicy(types.ENICapPolicyPreferTrunk), m.podCapPolicy(&types.PodInfo{}))
+	assert.Equal(t, types.ENICapPolicy(types.ENICapPolicyPreferSecondary), m.podCapPolicy(ctx.pod))
+
+	m.capPolicy = types.ENICapPolicyPreferSecondary
+	ctx.pod.ENICapPolicy = types.ENICapPolicyDefault
+	res, err = m.allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, secondary, res)
+
+	// the pod prefer trunk on the node prefer secondary
+	ctx.pod.ENICapPolicy = types.ENICapPolicyPreferTrunk
+	res, err = m.allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, trunk, res)
+
+	// fall back to the other one if the preferred is not available
+	m.pool = &fakeMatchPool{idle: []types.NetworkResource{secondary}}
+	res, err = m.allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, secondary, res)
+
+	m.pool = &fakeMatchPool{idle: []types.NetworkResource{trunk}}
+	ctx.pod.ENICapPolicy = types.ENICapPolicyPreferSecondary
+	res, err = m.allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, trunk, res)
+}
+
+type fakeTrunkPool struct {
+	fakeMatchPool
+	removed  []string
+	released []string
+}
+
+func (f *fakeTrunkPool) AcquireMatch(ctx context.Context, resID, idempotentKey string, match func(types.NetworkResource) bool, create func() ([]types.NetworkResource, error)) (types.NetworkResource, error) {
+	res, err := f.fakeMatchPool.AcquireMatch(ctx, resID, idempotentKey, match, create)
+	if err == nil || create == nil {
+		return res, err
+	}
+	created, err := create()
+	if err != nil {
+		return nil, err
+	}
+	return created[0], nil
+}
+
+func (f *fakeTrunkPool) Release(resID string) error {
+	f.released = append(f.released, resID)
+	return nil
+}
+
+func (f *fakeTrunkPool) Remove(resID string) error {
+	f.removed = append(f.removed, resID)
+	return nil
+}
+
+type fakeTrunkAPI struct {
+	ipam.API
+	freed     []string
+	allocated int
+}
+
+func (f *fakeTrunkAPI) FreeENI(ctx context.Context, eniID string, instanceID string) error {
+	f.freed = append(f.freed, eniID)
+	return nil
+}
+
+func (f *fakeTrunkAPI) AllocateENI(ctx context.Context, vSwitch string, securityGroups []string, instanceID string, trunk bool, ipCount int, eniTags map[string]string) (*types.ENI, error) {
+	f.allocated++
+	return &types.ENI{ID: "eni-2", MAC: "mac-2", Trunk: trunk}, nil
+}
+
+type fakeTrunkK8s struct {
+	Kubernetes
+	trunkOn string
+}
+
+func (f *fakeTrunkK8s) PatchTrunkInfo(trunkEni string) error {
+	f.trunkOn = trunkEni
+	return nil
+}
+
+func Test_eniResourceManager_idleTrunk(t *testing.T) {
+	trunk := &types.ENI{ID: "eni-1", MAC: "mac-1", Trunk: true}
+	p := &fakeTrunkPool{fakeMatchPool: fakeMatchPool{idle: []types.NetworkResource{trunk}}}
+	api := &fakeTrunkAPI{}
+	k8s := &fakeTrunkK8s{trunkOn: trunk.ID}
+	m := &eniResourceManager{
+		pool:            p,
+		ecs:             api,
+		k8s:             k8s,
+		factory:         &eniFactory{ecs: api, switches: []string{"vsw-1"}, trunkOnEni: trunk.ID},
+		trunkENI:        trunk,
+		trunkIdleDetach: time.Minute,
+	}
+
+	m.checkIdleTrunk(1)
+	assert.True(t, m.trunkIdleSince.IsZero())
+	m.checkIdleTrunk(0)
+	assert.False(t, m.trunkIdleSince.IsZero())
+	// not idle long enough
+	m.checkIdleTrunk(0)
+	assert.NotNil(t, m.getTrunkENI())
+
+	m.trunkIdleSince = time.Now().Add(-2 * time.Minute)
+	m.checkIdleTrunk(0)
+	assert.Nil(t, m.getTrunkENI())
+	assert.Equal(t, []string{"eni-1"}, api.freed)
+	assert.Equal(t, []string{"mac-1"}, p.removed)
+	assert.Equal(t, "", k8s.trunkOn)
+
+	p.idle = nil
+	assert.NoError(t, m.ensureTrunk(context.Background()))
+	assert.Equal(t, "eni-2", m.getTrunkENI().ID)
+	assert.Equal(t, "eni-2", m.factory.trunkOnEni)
+	assert.Equal(t, []string{"mac-2"}, p.released)
+	assert.Equal(t, "eni-2", k8s.trunkOn)
+
+	assert.Error(t, validateConfig(&daemon.Config{TrunkENIIdleDetachSeconds: 60, WaitTrunkENI: true}))
+}
+
+func (f *fakeTagAPI) UntagNetworkInterface(ctx context.Context, eniID string, keys []string) error {
+	delete(f.tags, eniID)
+	return nil
+}
+
+type fakeTagPool struct {
+	fakeMatchPool
+}
+
+func (f *fakeTagPool) ReleaseWithReservation(resID string, reservation time.Duration) error {
+	return nil
+}
+
+func (f *fakeTagPool) Stat(resID string) (types.NetworkResource, error) {
+	for _, res := range f.idle {
+		if res.GetResourceID() == resID {
+			return res, nil
+		}
+	}
+	return nil, pool.ErrNotFound
+}
+
+type fakeReservePool struct {
+	fakeMatchPool
+	reservations map[string]time.Duration
+}
+
+func (f *fakeReservePool) ReleaseWithReservation(resID string, reservation time.Duration) error {
+	f.reservations[resID] = reservation
+	return nil
+}
+
+func Test_eniResourceManager_idleRetain(t *testing.T) {
+	p := &fakeReservePool{reservations: map[string]time.Duration{}}
+	m := &eniResourceManager{pool: p}
+	ctx := &networkContext{
+		Context: context.Background(),
+		pod:     &types.PodInfo{Namespace: "default", Name: "foo"},
+	}
+
+	// released at once by default
+	assert.NoError(t, m.Release(ctx, types.ResourceItem{Type: types.ResourceTypeENI, ID: "mac-1", ENIID: "eni-1"}))
+	assert.Equal(t, time.Duration(0), p.reservations["mac-1"])
+
+	// the released eni is kept idle in pool
+	m.idleRetain = time.Minute
+	assert.NoError(t, m.Release(ctx, types.ResourceItem{Type: types.ResourceTypeENI, ID: "mac-2", ENIID: "eni-2"}))
+	assert.Equal(t, time.Minute, p.reservations["mac-2"])
+	assert.NoError(t, m.Release(nil, types.ResourceItem{Type: types.ResourceTypeENI, ID: "mac-3", ENIID: "eni-3"}))
+	assert.Equal(t, time.Minute, p.reservations["mac-3"])
+
+	// the longer ip stick time of pod takes precedence
+	ctx.pod.IPStickTime = time.Hour
+	assert.NoError(t, m.Release(ctx, types.ResourceItem{Type: types.ResourceTypeENI, ID: "mac-4", ENIID: "eni-4"}))
+	assert.Equal(t, time.Hour, p.reservations["mac-4"])
+
+	assert.Error(t, validateConfig(&daemon.Config{ENIIdleRetainSeconds: -1}))
+}
+
+func Test_eniResourceManager_tagWithPod(t *testing.T) {
+	eni := &types.ENI{ID: "eni-1", MAC: "mac-1", VSwitchID: "vsw-1"}
+	api := &fakeTagAPI{tags: map[string]map[string]string{}}
+	m := &eniResourceManager{
+		pool: &fakeTagPool{fakeMatchPool{idle: []types.NetworkResource{eni}}},
+		ecs:  api,
+	}
+	ctx := &networkContext{
+		Context: context.Background(),
+		pod:     &types.PodInfo{Namespace: "default", Name: strings.Repeat("a", 200), VSwitchID: "vsw-1"},
+	}
+
+	_, err := m.Allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Empty(t, api.tags)
+
+	m.tagWithPod = true
+	_, err = m.Allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "default", api.tags["eni-1"][types.TagKubernetesPodNamespace])
+	assert.Equal(t, strings.Repeat("a", tagValueMaxLength), api.tags["eni-1"][types.TagKubernetesPodName])
+
+	assert.NoError(t, m.Release(ctx, types.ResourceItem{Type: types.ResourceTypeENI, ID: "mac-1", ENIID: "eni-1"}))
+	assert.Empty(t, api.tags)
+
+	// the eni id is looked up from pool for the record without it
+	_, err = m.Allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.NoError(t, m.Release(ctx, types.ResourceItem{Type: types.ResourceTypeENI, ID: "mac-1"}))
+	assert.Empty(t, api.tags)
+}
+
+func Test_eniResourceManager_podLabelTags(t *testing.T) {
+	eni := &types.ENI{ID: "eni-1", MAC: "mac-1", VSwitchID: "vsw-1"}
+	api := &fakeTagAPI{tags: map[string]map[string]string{}}
+	poolConfig := &types.PoolConfig{ENITags: map[string]string{"k1": "v1"}, TagENIWithPod: true}
+	for i := 0; i < 15; i++ {
+		poolConfig.ENITags[fmt.Sprintf("static-%d", i)] = "v"
+	}
+	// 20 - 16 static - 1 creator - 2 pod
+	assert.Equal(t, 1, maxPodLabelTags(poolConfig))
+	m := &eniResourceManager{
+		pool:            &fakeTagPool{fakeMatchPool{idle: []types.NetworkResource{eni}}},
+		ecs:             api,
+		podLabelTags:    []string{"team", "app", "cost-center"},
+		maxPodLabelTags: maxPodLabelTags(poolConfig),
+	}
+	ctx := &networkContext{
+		Context: context.Background(),
+		pod:     &types.PodInfo{Namespace: "default", Name: "foo", VSwitchID: "vsw-1", Labels: map[string]string{"app": "web", "cost-center": "c1"}},
+	}
+
+	_, err := m.Allocate(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "web"}, api.tags["eni-1"])
+
+	assert.NoError(t, m.Release(ctx, types.ResourceItem{Type: types.ResourceTypeENI, ID: "mac-1", ENIID: "eni-1"}))
+	assert.Empty(t, api.tags)
+
+	assert.Error(t, validateConfig(&daemon.Config{ENITags: map[string]string{"app": "foo"}, PodLabelENITags: []string{"app"}}))
+	assert.Error(t, validateConfig(&daemon.Config{PodLabelENITags: []string{"acs:app"}}))
+	cfg := &daemon.Config{PodLabelENITags: []string{"app"}}
+	assert.NoError(t, validateConfig(cfg))
+	assert.Equal(t, []string{"app"}, cfg.PodLabelENITags)
+}
@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+)
+
+// extraRoutesFingerprint return the fingerprint of the extra routes in net confs
+func extraRoutesFingerprint(netConfs []*rpc.NetConf) string {
+	var routes []string
+	for _, conf := range netConfs {
+		for _, route := range conf.ExtraRoutes {
+			routes = append(routes, fmt.Sprintf("%s/%s/%s/%d/%d", conf.IfName, route.Dst, route.Src, route.Table, route.Priority))
+		}
+	}
+	sort.Strings(routes)
+	sum := sha256.Sum256([]byte(strings.Join(routes, ",")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// reportRoutesChanged set the fingerprint of the extra routes of crd to the reply, and report the change
+// until the cni sends back the fingerprint reconciled.
+func (n *networkService) reportRoutesChanged(r *rpc.GetInfoRequest, podRes types.PodResources, netConfs []*rpc.NetConf, reply *rpc.GetInfoReply) {
+	fingerprint := extraRoutesFingerprint(netConfs)
+	reply.RouteFingerprint = fingerprint
+	if podRes.PodInfo == nil || podRes.RouteFingerprint == fingerprint {
+		return
+	}
+	if podRes.RouteFingerprint == "" {
+		// allocated by old version, take the routes as applied
+		n.storeRouteFingerprint(podRes, fingerprint)
+		return
+	}
+	if r.RouteFingerprint != fingerprint || !n.storeRouteFingerprint(podRes, fingerprint) {
+		reply.RoutesChanged = true
+	}
+}
+
+// storeRouteFingerprint store the fingerprint of the extra routes reconciled by cni, return false if the
+// record is changed by others
+func (n *networkService) storeRouteFingerprint(podRes types.PodResources, fingerprint string) bool {
+	key := podInfoKey(podRes.PodInfo.Namespace, podRes.PodInfo.Name)
+	done, ok := n.markPending(key)
+	if !ok {
+		// the pod is being allocated or released, leave the record to it
+		return false
+	}
+	defer done()
+
+	obj, err := n.resourceDB.Get(key)
+	if err != nil {
+		return false
+	}
+	latest := obj.(types.PodResources)
+	if !sameContainer(latest.ContainerID, podRes.ContainerID) {
+		return false
+	}
+	latest.RouteFingerprint = fingerprint
+	err = n.resourceDB.Put(key, latest)
+	if err != nil {
+		serviceLog.Warnf("error store route fingerprint of pod %s, %v", key, err)
+		return false
+	}
+	return true
+}
+
+// putCRDPodResource store the record of the pod its default interface is from crd, only to keep the
+// fingerprint of the extra routes, the record of resources allocated locally is left as it is
+func (n *networkService) putCRDPodResource(ctx *networkContext, r *rpc.AllocIPRequest, oldRes types.PodResources, netConfs []*rpc.NetConf) error {
+	if len(oldRes.Resources) > 0 {
+		return nil
+	}
+	return n.putPodResource(ctx, types.PodResources{
+		PodInfo:          ctx.pod,
+		NetNs:            &r.Netns,
+		ContainerID:      &r.K8SPodInfraContainerId,
+		RouteFingerprint: extraRoutesFingerprint(netConfs),
+	})
+}
+
+func sameContainer(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
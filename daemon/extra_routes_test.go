@@ -0,0 +1,92 @@
+package daemon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_extraRoutesFingerprint(t *testing.T) {
+	netConfs := []*rpc.NetConf{
+		{IfName: "eth0", ExtraRoutes: []*rpc.Route{{Dst: "10.0.0.0/8"}, {Dst: "192.168.0.0/16"}}},
+	}
+	reordered := []*rpc.NetConf{
+		{IfName: "eth0", ExtraRoutes: []*rpc.Route{{Dst: "192.168.0.0/16"}, {Dst: "10.0.0.0/8"}}},
+	}
+	fp := extraRoutesFingerprint(netConfs)
+	assert.Equal(t, fp, extraRoutesFingerprint(reordered))
+
+	changed := []*rpc.NetConf{
+		{IfName: "eth0", ExtraRoutes: []*rpc.Route{{Dst: "10.0.0.0/8"}}},
+	}
+	assert.NotEqual(t, fp, extraRoutesFingerprint(changed))
+}
+
+func Test_networkService_reportRoutesChanged(t *testing.T) {
+	netConfs := []*rpc.NetConf{
+		{IfName: "eth1", ExtraRoutes: []*rpc.Route{{Dst: "10.0.0.0/8"}}},
+	}
+	changed := []*rpc.NetConf{
+		{IfName: "eth1", ExtraRoutes: []*rpc.Route{{Dst: "192.168.0.0/16"}}},
+	}
+	podInfo := &types.PodInfo{Namespace: "default", Name: "pod"}
+	key := podInfoKey(podInfo.Namespace, podInfo.Name)
+	containerID := "c1"
+	r := &rpc.GetInfoRequest{K8SPodNamespace: podInfo.Namespace, K8SPodName: podInfo.Name, K8SPodInfraContainerId: containerID}
+
+	n := &networkService{resourceDB: storage.NewMemoryStorage()}
+	// the pod its network is all from crd
+	ctx := &networkContext{Context: context.Background(), pod: podInfo}
+	assert.NoError(t, n.putCRDPodResource(ctx, &rpc.AllocIPRequest{K8SPodInfraContainerId: containerID}, types.PodResources{}, netConfs))
+	obj, err := n.resourceDB.Get(key)
+	assert.NoError(t, err)
+	podRes := obj.(types.PodResources)
+	assert.Equal(t, extraRoutesFingerprint(netConfs), podRes.RouteFingerprint)
+
+	reply := &rpc.GetInfoReply{}
+	n.reportRoutesChanged(r, podRes, netConfs, reply)
+	assert.False(t, reply.RoutesChanged)
+
+	// reported until the cni reconciled them
+	for i := 0; i < 2; i++ {
+		reply = &rpc.GetInfoReply{}
+		n.reportRoutesChanged(r, podRes, changed, reply)
+		assert.True(t, reply.RoutesChanged)
+		assert.Equal(t, extraRoutesFingerprint(changed), reply.RouteFingerprint)
+	}
+	obj, _ = n.resourceDB.Get(key)
+	assert.Equal(t, extraRoutesFingerprint(netConfs), obj.(types.PodResources).RouteFingerprint)
+
+	// the fingerprint of other routes is not taken
+	reply = &rpc.GetInfoReply{}
+	n.reportRoutesChanged(&rpc.GetInfoRequest{RouteFingerprint: extraRoutesFingerprint(netConfs)}, podRes, changed, reply)
+	assert.True(t, reply.RoutesChanged)
+
+	r.RouteFingerprint = extraRoutesFingerprint(changed)
+	reply = &rpc.GetInfoReply{}
+	n.reportRoutesChanged(r, podRes, changed, reply)
+	assert.False(t, reply.RoutesChanged)
+	obj, _ = n.resourceDB.Get(key)
+	podRes = obj.(types.PodResources)
+	assert.Equal(t, extraRoutesFingerprint(changed), podRes.RouteFingerprint)
+
+	// allocated by old version, take the routes as applied
+	podRes.RouteFingerprint = ""
+	assert.NoError(t, n.resourceDB.Put(key, podRes))
+	reply = &rpc.GetInfoReply{}
+	n.reportRoutesChanged(&rpc.GetInfoRequest{}, podRes, netConfs, reply)
+	assert.False(t, reply.RoutesChanged)
+	obj, _ = n.resourceDB.Get(key)
+	assert.Equal(t, extraRoutesFingerprint(netConfs), obj.(types.PodResources).RouteFingerprint)
+
+	// the record of resources allocated locally is left as it is
+	podRes.Resources = []types.ResourceItem{{Type: types.ResourceTypeENIIP, ID: "eni-1.192.168.0.1"}}
+	assert.NoError(t, n.putCRDPodResource(ctx, &rpc.AllocIPRequest{K8SPodInfraContainerId: "c2"}, podRes, changed))
+	obj, _ = n.resourceDB.Get(key)
+	assert.Equal(t, containerID, *obj.(types.PodResources).ContainerID)
+}
@@ -0,0 +1,213 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	gcQuarantineDBPath = "/var/lib/cni/terway/GCQuarantine.db"
+	gcQuarantineDBName = "gc_quarantine"
+)
+
+// resourceGCError the error of garbage collection on the resource, returned by the resource manager
+type resourceGCError struct {
+	resID string
+	err   error
+}
+
+func (e *resourceGCError) Error() string {
+	return fmt.Sprintf("error gc resource %s: %v", e.resID, e.err)
+}
+
+func (e *resourceGCError) Unwrap() error {
+	return e.err
+}
+
+// quarantinedResource the resource failed gc repeatedly, it is not collected by gc until retried manually
+type quarantinedResource struct {
+	Item          types.ResourceItem
+	Failures      int
+	LastError     string
+	QuarantinedAt time.Time
+}
+
+func (r quarantinedResource) String() string {
+	return fmt.Sprintf("%s(%s) quarantined at %s after %d failures, last error: %s",
+		r.Item.ID, r.Item.Type, r.QuarantinedAt.Format(time.RFC3339), r.Failures, r.LastError)
+}
+
+func marshalQuarantinedResource(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func unmarshalQuarantinedResource(bytes []byte) (interface{}, error) {
+	res := quarantinedResource{}
+	err := json.Unmarshal(bytes, &res)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error unmarshal quarantined resource")
+	}
+	return res, nil
+}
+
+// gcQuarantine count the consecutive gc failures of the resources,
+// the resource is quarantined when the failures reach the threshold
+type gcQuarantine struct {
+	lock      sync.Mutex
+	threshold int
+	failures  map[string]int
+	// store the quarantined resources by resource id, persisted across restarts
+	store storage.Storage
+}
+
+func newGCQuarantine(threshold int, store storage.Storage) *gcQuarantine {
+	return &gcQuarantine{
+		threshold: threshold,
+		failures:  make(map[string]int),
+		store:     store,
+	}
+}
+
+// Failed record the gc failure of the resource, return true if the resource is quarantined
+func (q *gcQuarantine) Failed(item types.ResourceItem, gcErr error) (bool, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.failures[item.ID]++
+	if q.failures[item.ID] < q.threshold {
+		return false, nil
+	}
+	err := q.store.Put(item.ID, quarantinedResource{
+		Item:          item,
+		Failures:      q.failures[item.ID],
+		LastError:     gcErr.Error(),
+		QuarantinedAt: time.Now(),
+	})
+	if err != nil {
+		return false, err
+	}
+	delete(q.failures, item.ID)
+	return true, nil
+}
+
+// Succeeded reset the failures of the resources collected
+func (q *gcQuarantine) Succeeded(items map[string]types.ResourceItem) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for id := range items {
+		delete(q.failures, id)
+	}
+}
+
+// Quarantined return true if the resource is quarantined
+func (q *gcQuarantine) Quarantined(resID string) bool {
+	_, err := q.store.Get(resID)
+	return err == nil
+}
+
+// Get return the quarantined resource
+func (q *gcQuarantine) Get(resID string) (quarantinedResource, bool) {
+	v, err := q.store.Get(resID)
+	if err != nil {
+		return quarantinedResource{}, false
+	}
+	return v.(quarantinedResource), true
+}
+
+// List return the quarantined resources sorted by id
+func (q *gcQuarantine) List() ([]quarantinedResource, error) {
+	list, err := q.store.List()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]quarantinedResource, 0, len(list))
+	for _, v := range list {
+		res = append(res, v.(quarantinedResource))
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Item.ID < res[j].Item.ID
+	})
+	return res, nil
+}
+
+// Remove release the resource from quarantine
+func (q *gcQuarantine) Remove(resID string) error {
+	return q.store.Delete(resID)
+}
+
+// retryQuarantined collect the quarantined resources given manually, the resource failed is kept in quarantine.
+// the resource collected is dropped from the record kept by gc, so it is not released again by the next gc
+func (n *networkService) retryQuarantined(resIDs []string, message chan<- string) {
+	if n.gcQuarantine == nil {
+		message <- "gc quarantine not enabled\n"
+		return
+	}
+	n.Lock()
+	defer n.Unlock()
+	for _, id := range resIDs {
+		res, ok := n.gcQuarantine.Get(id)
+		if !ok {
+			message <- fmt.Sprintf("resource %s not quarantined\n", id)
+			continue
+		}
+		mgr, ok := n.mgrForResource[resourceManagerKey{resType: res.Item.Type, poolID: res.Item.GetPoolID()}]
+		if !ok {
+			message <- fmt.Sprintf("no resource manager for %s(%s)\n", id, res.Item.Type)
+			continue
+		}
+		_, err := mgr.GarbageCollection(map[string]types.ResourceItem{}, map[string]types.ResourceItem{id: res.Item})
+		if err != nil {
+			message <- fmt.Sprintf("error gc resource %s, kept in quarantine: %v\n", id, err)
+			continue
+		}
+		err = n.forgetCollected(id)
+		if err != nil {
+			message <- fmt.Sprintf("error remove resource %s from resource db: %v\n", id, err)
+			continue
+		}
+		err = n.gcQuarantine.Remove(id)
+		if err != nil {
+			message <- fmt.Sprintf("error remove resource %s from quarantine: %v\n", id, err)
+			continue
+		}
+		message <- fmt.Sprintf("resource %s collected\n", id)
+	}
+}
+
+// forgetCollected remove the resource collected from the records in resource db, the record left empty is deleted
+func (n *networkService) forgetCollected(resID string) error {
+	resRelateList, err := n.resourceDB.List()
+	if err != nil {
+		return err
+	}
+	for _, resRelateObj := range resRelateList {
+		resRelate := resRelateObj.(types.PodResources)
+		var kept []types.ResourceItem
+		for _, res := range resRelate.Resources {
+			if res.ID != resID {
+				kept = append(kept, res)
+			}
+		}
+		if len(kept) == len(resRelate.Resources) {
+			continue
+		}
+		podKey := podInfoKey(resRelate.PodInfo.Namespace, resRelate.PodInfo.Name)
+		if len(kept) == 0 {
+			err = n.resourceDB.Delete(podKey)
+		} else {
+			resRelate.Resources = kept
+			err = n.resourceDB.Put(podKey, resRelate)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
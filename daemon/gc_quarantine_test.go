@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_networkService_gcFailed(t *testing.T) {
+	k8s := &eventRecorderK8s{}
+	n := &networkService{k8s: k8s, gcQuarantine: newGCQuarantine(2, storage.NewMemoryStorage())}
+	item := types.ResourceItem{Type: types.ResourceTypeENIIP, ID: "00:00:00:00:00:01.192.168.0.1"}
+	expireSet := map[string]types.ResourceItem{item.ID: item}
+	gcErr := &resourceGCError{resID: item.ID, err: fmt.Errorf("release failed")}
+
+	n.gcFailed(expireSet, gcErr)
+	assert.False(t, n.gcQuarantine.Quarantined(item.ID))
+	// failures not consecutive
+	n.gcQuarantine.Succeeded(expireSet)
+	n.gcFailed(expireSet, gcErr)
+	assert.False(t, n.gcQuarantine.Quarantined(item.ID))
+	// error not of the resource is ignored
+	n.gcFailed(expireSet, fmt.Errorf("release failed"))
+	assert.False(t, n.gcQuarantine.Quarantined(item.ID))
+
+	n.gcFailed(expireSet, fmt.Errorf("wrapped: %w", gcErr))
+	assert.True(t, n.gcQuarantine.Quarantined(item.ID))
+	assert.Equal(t, []string{"ResourceQuarantined"}, k8s.events)
+	quarantined, err := n.gcQuarantine.List()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(quarantined))
+	assert.Equal(t, item, quarantined[0].Item)
+	assert.Equal(t, 2, quarantined[0].Failures)
+	assert.Equal(t, "release failed", quarantined[0].LastError)
+
+	assert.NoError(t, n.gcQuarantine.Remove(item.ID))
+	assert.False(t, n.gcQuarantine.Quarantined(item.ID))
+}
+
+func Test_networkService_gcKeepQuarantined(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	eniIPMgr, eipMgr := &gcRecordManager{}, &gcRecordManager{}
+	n := &networkService{k8s: &fakeK8s{}, resourceDB: db, gcQuarantine: newGCQuarantine(1, storage.NewMemoryStorage())}
+	n.setResourceManagers(map[string]ResourceManager{
+		types.ResourceTypeENIIP: eniIPMgr,
+		types.ResourceTypeEIP:   eipMgr,
+	})
+	res := types.PodResources{
+		PodInfo: &types.PodInfo{Namespace: "default", Name: "foo"},
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1", ENIID: "eni-1", IPv4: "192.168.0.1"},
+			{Type: types.ResourceTypeEIP, ID: "eip-1"},
+		},
+	}
+	assert.NoError(t, db.Put("default/foo", res))
+	quarantined, err := n.gcQuarantine.Failed(res.Resources[1], fmt.Errorf("release failed"))
+	assert.NoError(t, err)
+	assert.True(t, quarantined)
+
+	_, err = n.gc()
+	assert.NoError(t, err)
+	assert.Contains(t, eniIPMgr.expire, "mac-1.192.168.0.1")
+	assert.NotContains(t, eipMgr.expire, "eip-1")
+	// the record is kept with the quarantined resource only, so it can be retried after restart
+	obj, err := db.Get("default/foo")
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ResourceItem{res.Resources[1]}, obj.(types.PodResources).Resources)
+
+	// the record is deleted once the resource is out of quarantine and collected
+	assert.NoError(t, n.gcQuarantine.Remove("eip-1"))
+	_, err = n.gc()
+	assert.NoError(t, err)
+	assert.Contains(t, eipMgr.expire, "eip-1")
+	_, err = db.Get("default/foo")
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func Test_networkService_retryQuarantined(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	eipMgr := &gcRecordManager{}
+	n := &networkService{k8s: &fakeK8s{}, resourceDB: db, gcQuarantine: newGCQuarantine(1, storage.NewMemoryStorage())}
+	n.setResourceManagers(map[string]ResourceManager{
+		types.ResourceTypeENIIP: &gcRecordManager{},
+		types.ResourceTypeEIP:   eipMgr,
+	})
+	item := types.ResourceItem{Type: types.ResourceTypeEIP, ID: "eip-1"}
+	assert.NoError(t, db.Put("default/foo", types.PodResources{
+		PodInfo:   &types.PodInfo{Namespace: "default", Name: "foo"},
+		Resources: []types.ResourceItem{item},
+	}))
+	_, err := n.gcQuarantine.Failed(item, fmt.Errorf("release failed"))
+	assert.NoError(t, err)
+
+	message := make(chan string, 10)
+	n.retryQuarantined([]string{"eip-1"}, message)
+	close(message)
+	var messages []string
+	for msg := range message {
+		messages = append(messages, msg)
+	}
+	assert.Equal(t, []string{"resource eip-1 collected\n"}, messages)
+	assert.False(t, n.gcQuarantine.Quarantined("eip-1"))
+	// the record kept for the quarantined resource is dropped, the next gc does not release it again
+	_, err = db.Get("default/foo")
+	assert.Equal(t, storage.ErrNotFound, err)
+	eipMgr.expire = nil
+	_, err = n.gc()
+	assert.NoError(t, err)
+	assert.NotContains(t, eipMgr.expire, "eip-1")
+}
@@ -0,0 +1,52 @@
+package daemon
+
+import (
+	"time"
+
+	"github.com/AliyunContainerService/terway/types"
+)
+
+// ipStickExpired return true if the resources of pod are allocated longer than the max ip stick duration
+func (n *networkService) ipStickExpired(res types.PodResources) bool {
+	if n.maxIPStickDuration <= 0 || res.AllocatedAt.IsZero() {
+		return false
+	}
+	return time.Since(res.AllocatedAt) > n.maxIPStickDuration
+}
+
+// ipv6Releaser release the ipv6 of the resource in use while the ipv4 is kept in use
+type ipv6Releaser interface {
+	// ReleaseIPv6 return the item of the resource with ipv4 only
+	ReleaseIPv6(resItem types.ResourceItem) (types.ResourceItem, error)
+}
+
+// releaseNoStickIPv6 release the ipv6 of the eniips of the deleted pod whose ipv6 not sticks, while the ipv4
+// sticks. the record with the items of ipv4 only is returned, the ipv6 failed to release is released with the ipv4
+func (n *networkService) releaseNoStickIPv6(podKey string, res types.PodResources) types.PodResources {
+	if !res.PodInfo.IPv6NoStick {
+		return res
+	}
+	var released []types.ResourceItem
+	resources := make([]types.ResourceItem, 0, len(res.Resources))
+	for _, item := range res.Resources {
+		releaser, ok := n.getResourceManagerForRes(item).(ipv6Releaser)
+		if !ok || item.Type != types.ResourceTypeENIIP || item.IPv6 == "" {
+			resources = append(resources, item)
+			continue
+		}
+		v4Item, err := releaser.ReleaseIPv6(item)
+		if err != nil {
+			serviceLog.Warnf("error release ipv6 %s of pod %s, released with the ipv4: %v", item.IPv6, podKey, err)
+			resources = append(resources, item)
+			continue
+		}
+		serviceLog.Infof("ipv6 %s of pod %s released, ipv4 %s sticks", item.IPv6, podKey, item.IPv4)
+		released = append(released, types.ResourceItem{Type: item.Type, ID: item.ID, IPv6: item.IPv6})
+		resources = append(resources, v4Item)
+	}
+	res.Resources = resources
+	if n.podHistory != nil && len(released) > 0 {
+		n.podHistory.Add(podKey, podHistoryGCIPv6NoStick, released)
+	}
+	return res
+}
@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_networkService_maxIPStickDuration(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	n := &networkService{k8s: &fakeK8s{}, resourceDB: db, maxIPStickDuration: time.Hour}
+	veth := []types.ResourceItem{{Type: types.ResourceTypeVeth, ID: "veth-1"}}
+
+	for _, res := range []types.PodResources{
+		{PodInfo: &types.PodInfo{Namespace: "default", Name: "expired", IPStickTime: time.Minute}, Resources: []types.ResourceItem{{Type: types.ResourceTypeVeth, ID: "veth-0"}}, AllocatedAt: time.Now().Add(-2 * time.Hour)},
+		{PodInfo: &types.PodInfo{Namespace: "default", Name: "sticky", IPStickTime: time.Minute}, Resources: veth, AllocatedAt: time.Now()},
+	} {
+		assert.NoError(t, db.Put(podInfoKey(res.PodInfo.Namespace, res.PodInfo.Name), res))
+	}
+	_, err := n.gc()
+	assert.NoError(t, err)
+	_, err = db.Get("default/expired")
+	assert.Equal(t, storage.ErrNotFound, err)
+	obj, err := db.Get("default/sticky")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), obj.(types.PodResources).PodInfo.IPStickTime)
+
+	// the allocation time is kept for the resources reused by the pod recreated
+	allocatedAt := obj.(types.PodResources).AllocatedAt
+	pod := &types.PodInfo{Namespace: "default", Name: "sticky", IPStickTime: time.Minute}
+	assert.NoError(t, n.putPodResource(context.Background(), types.PodResources{PodInfo: pod, Resources: veth}))
+	obj, _ = db.Get("default/sticky")
+	assert.Equal(t, allocatedAt, obj.(types.PodResources).AllocatedAt)
+
+	assert.NoError(t, n.putPodResource(context.Background(), types.PodResources{PodInfo: pod, Resources: []types.ResourceItem{{Type: types.ResourceTypeVeth, ID: "veth-2"}}}))
+	obj, _ = db.Get("default/sticky")
+	assert.True(t, obj.(types.PodResources).AllocatedAt.After(allocatedAt))
+}
+
+// ipv6ReleaseManager record the gc and release the ipv6 of the items
+type ipv6ReleaseManager struct {
+	gcRecordManager
+	err error
+}
+
+func (m *ipv6ReleaseManager) ReleaseIPv6(resItem types.ResourceItem) (types.ResourceItem, error) {
+	if m.err != nil {
+		return resItem, m.err
+	}
+	resItem.ID = "mac-1." + resItem.IPv4
+	resItem.IPv6 = ""
+	return resItem, nil
+}
+
+func newIPv6StickService(mgr ResourceManager) *networkService {
+	n := &networkService{k8s: &fakeK8s{}, resourceDB: storage.NewMemoryStorage(), podHistory: newPodHistory(time.Hour)}
+	n.setResourceManagers(map[string]ResourceManager{types.ResourceTypeENIIP: mgr})
+	return n
+}
+
+var ipv6StickItem = types.ResourceItem{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1-fd00::1", ENIID: "eni-1", IPv4: "192.168.0.1", IPv6: "fd00::1"}
+
+func Test_networkService_gcIPv6NoStick(t *testing.T) {
+	mgr := &ipv6ReleaseManager{}
+	n := newIPv6StickService(mgr)
+	assert.NoError(t, n.resourceDB.Put("default/foo", types.PodResources{
+		PodInfo:   &types.PodInfo{Namespace: "default", Name: "foo", IPStickTime: time.Minute, IPv6NoStick: true},
+		Resources: []types.ResourceItem{ipv6StickItem},
+	}))
+
+	_, err := n.gc()
+	assert.NoError(t, err)
+	// the ipv6 is released at once while the ipv4 sticks
+	obj, err := n.resourceDB.Get("default/foo")
+	assert.NoError(t, err)
+	resources := obj.(types.PodResources).Resources
+	assert.Len(t, resources, 1)
+	assert.Equal(t, "mac-1.192.168.0.1", resources[0].ID)
+	assert.Equal(t, "192.168.0.1", resources[0].IPv4)
+	assert.Empty(t, resources[0].IPv6)
+	assert.Contains(t, mgr.inUse, "mac-1.192.168.0.1")
+	assert.Empty(t, mgr.expire)
+	history := n.podHistory.Get("default/foo")
+	assert.Len(t, history, 1)
+	assert.Equal(t, podHistoryGCIPv6NoStick, history[0].Action)
+
+	// the ipv4 is released after the stick time
+	_, err = n.gc()
+	assert.NoError(t, err)
+	assert.Contains(t, mgr.expire, "mac-1.192.168.0.1")
+}
+
+func Test_networkService_gcIPv6NoStick_failed(t *testing.T) {
+	mgr := &ipv6ReleaseManager{err: fmt.Errorf("unassign failed")}
+	n := newIPv6StickService(mgr)
+	assert.NoError(t, n.resourceDB.Put("default/foo", types.PodResources{
+		PodInfo:   &types.PodInfo{Namespace: "default", Name: "foo", IPStickTime: time.Minute, IPv6NoStick: true},
+		Resources: []types.ResourceItem{ipv6StickItem},
+	}))
+
+	_, err := n.gc()
+	assert.NoError(t, err)
+	// the ipv6 sticks with the ipv4 and released together
+	obj, err := n.resourceDB.Get("default/foo")
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ResourceItem{ipv6StickItem}, obj.(types.PodResources).Resources)
+	assert.Contains(t, mgr.inUse, ipv6StickItem.ID)
+	assert.Empty(t, n.podHistory.Get("default/foo"))
+}
+
+func Test_networkService_gcIPv6Stick(t *testing.T) {
+	mgr := &ipv6ReleaseManager{}
+	n := newIPv6StickService(mgr)
+	assert.NoError(t, n.resourceDB.Put("default/foo", types.PodResources{
+		PodInfo:   &types.PodInfo{Namespace: "default", Name: "foo", IPStickTime: time.Minute},
+		Resources: []types.ResourceItem{ipv6StickItem},
+	}))
+
+	_, err := n.gc()
+	assert.NoError(t, err)
+	obj, err := n.resourceDB.Get("default/foo")
+	assert.NoError(t, err)
+	assert.Equal(t, []types.ResourceItem{ipv6StickItem}, obj.(types.PodResources).Resources)
+}
@@ -37,6 +37,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	typedv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/record"
 )
@@ -63,6 +64,8 @@ type Kubernetes interface {
 	GetPod(namespace, name string) (*types.PodInfo, error)
 	GetServiceCIDR() *types.IPNetSet
 	GetNodeCidr() *types.IPNetSet
+	GetNodeName() string
+	IsNodeTerminating() (bool, error)
 	SetNodeAllocatablePod(count int) error
 	PatchEipInfo(info *types.PodInfo) error
 	PatchTrunkInfo(trunkEni string) error
@@ -76,10 +79,12 @@ type Kubernetes interface {
 	SetSvcCidr(svcCidr *types.IPNetSet) error
 	SetCustomStatefulWorkloadKinds(kinds []string) error
 	WaitTrunkReady() (string, error)
+	SetNodeCondition(conditionType corev1.NodeConditionType, status corev1.ConditionStatus, reason, message string) error
 }
 
 type k8s struct {
 	client                  kubernetes.Interface
+	criticalClient          kubernetes.Interface // for AllocIP critical calls, has a separate rate limiter from client
 	podEniClient            v1beta1.NetworkV1beta1Interface
 	storage                 storage.Storage
 	broadcaster             record.EventBroadcaster
@@ -143,15 +148,21 @@ func (k *k8s) SetSvcCidr(svcCidr *types.IPNetSet) error {
 	k.Lock()
 	defer k.Unlock()
 
-	var err error
-	if svcCidr.IPv4 == nil {
-		svcCidr.IPv4, err = serviceCidrFromAPIServer(k.client)
+	// keep the svcCidr set even detect failed, so GetServiceCIDR never return nil
+	k.svcCidr = svcCidr
+	if svcCidr.IPv4 != nil {
+		return nil
+	}
+
+	ipNet, err := serviceCidrFromAPIServer(k.client)
+	if err != nil {
+		log.Debugf("error get service cidr from kubeadm config, %v", err)
+		ipNet, err = serviceCidrFromKubeAPIServer(k.client)
 		if err != nil {
 			return errors.Wrap(err, "failed getting service cidr")
 		}
 	}
-
-	k.svcCidr = svcCidr
+	svcCidr.IPv4 = ipNet
 	return nil
 }
 
@@ -269,6 +280,72 @@ func (k *k8s) WaitTrunkReady() (string, error) {
 	return id, err
 }
 
+// IsNodeTerminating return true if the node is deleted or tainted to be deleted
+func (k *k8s) IsNodeTerminating() (bool, error) {
+	node, err := k.client.CoreV1().Nodes().Get(context.TODO(), k.nodeName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		k.reconnectOnTimeoutError(err)
+		return false, err
+	}
+	return isNodeTerminating(node), nil
+}
+
+func isNodeTerminating(node *corev1.Node) bool {
+	if node.DeletionTimestamp != nil {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == nodeTaintToBeDeleted {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNodeCondition update the node condition, skip if the status is not changed
+func (k *k8s) SetNodeCondition(conditionType corev1.NodeConditionType, status corev1.ConditionStatus, reason, message string) error {
+	node, err := k.client.CoreV1().Nodes().Get(context.TODO(), k.nodeName, metav1.GetOptions{
+		ResourceVersion: "0",
+	})
+	if err != nil || node == nil {
+		k.reconnectOnTimeoutError(err)
+		return err
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == conditionType && cond.Status == status && cond.Reason == reason {
+			return nil
+		}
+	}
+
+	now := metav1.Now()
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []corev1.NodeCondition{
+				{
+					Type:               conditionType,
+					Status:             status,
+					LastHeartbeatTime:  now,
+					LastTransitionTime: now,
+					Reason:             reason,
+					Message:            message,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = k.client.CoreV1().Nodes().PatchStatus(context.TODO(), k.nodeName, patch)
+	if err != nil {
+		k.reconnectOnTimeoutError(err)
+		return err
+	}
+	return nil
+}
+
 // newK8S return Kubernetes service by pod spec and daemon mode
 func newK8S(master, kubeconfig string, daemonMode string, globalConfig *daemon.Config) (Kubernetes, error) {
 
@@ -294,6 +371,20 @@ func newK8S(master, kubeconfig string, daemonMode string, globalConfig *daemon.C
 		return nil, err
 	}
 
+	criticalRestConfig := k8sRestConfig
+	criticalClient := client
+	if globalConfig.CriticalKubeClientQPS > 0 {
+		criticalRestConfig = rest.CopyConfig(k8sRestConfig)
+		criticalRestConfig.QPS = globalConfig.CriticalKubeClientQPS
+		if globalConfig.CriticalKubeClientBurst > 0 {
+			criticalRestConfig.Burst = globalConfig.CriticalKubeClientBurst
+		}
+		criticalClient, err = kubernetes.NewForConfig(criticalRestConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	nodeName, err := getNodeName(client)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed getting node name")
@@ -337,6 +428,7 @@ func newK8S(master, kubeconfig string, daemonMode string, globalConfig *daemon.C
 
 	k8sObj := &k8s{
 		client:          client,
+		criticalClient:  criticalClient,
 		mode:            daemonMode,
 		node:            node,
 		nodeName:        nodeName,
@@ -349,7 +441,7 @@ func newK8S(master, kubeconfig string, daemonMode string, globalConfig *daemon.C
 		apiConnTime:     time.Now(),
 		Locker:          &sync.RWMutex{},
 	}
-	podENICli, err := v1beta1.NewForConfig(k8sRestConfig)
+	podENICli, err := v1beta1.NewForConfig(criticalRestConfig)
 	if err != nil {
 		return nil, errors.Wrapf(err, "error init pod ENI client")
 	}
@@ -443,10 +535,42 @@ func serviceCidrFromAPIServer(client kubernetes.Interface) (*net.IPNet, error) {
 	return nil, fmt.Errorf("cannot found kubeproxy config for svc cidr")
 }
 
+// serviceCidrFromKubeAPIServer parse the service cidr from the kube-apiserver static pod args
+func serviceCidrFromKubeAPIServer(client kubernetes.Interface) (*net.IPNet, error) {
+	pods, err := client.CoreV1().Pods(k8sSystemNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector:   k8sKubeAPIServerSelector,
+		ResourceVersion: "0",
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, pod := range pods.Items {
+		for _, c := range pod.Spec.Containers {
+			for _, arg := range append(c.Command, c.Args...) {
+				if !strings.HasPrefix(arg, k8sServiceClusterIPRangeFlag) {
+					continue
+				}
+				for _, cidr := range strings.Split(strings.TrimPrefix(arg, k8sServiceClusterIPRangeFlag), ",") {
+					ipNet, err := parseCidr(strings.TrimSpace(cidr))
+					if err == nil && ipNet.IP.To4() != nil {
+						return ipNet, nil
+					}
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("cannot found %s in kube-apiserver pods", k8sServiceClusterIPRangeFlag)
+}
+
 const k8sSystemNamespace = "kube-system"
+
+// nodeTaintToBeDeleted taint added by cluster autoscaler on the node to be scaled down
+const nodeTaintToBeDeleted = "ToBeDeletedByClusterAutoscaler"
 const k8sKubeadmConfigmap = "kubeadm-config"
 const k8sKubeadmConfigmapNetworking = "MasterConfiguration"
 const k8sKubeadmConfigmapClusterconfiguration = "ClusterConfiguration"
+const k8sKubeAPIServerSelector = "component=kube-apiserver"
+const k8sServiceClusterIPRangeFlag = "--service-cluster-ip-range="
 
 const podNeedEni = "k8s.aliyun.com/ENI"
 const podIngressBandwidth = "k8s.aliyun.com/ingress-bandwidth" //deprecated
@@ -464,6 +588,38 @@ const eipBandwidthPackageID = "k8s.aliyun.com/eip-common-bandwidth-package-id"
 const eipISP = "k8s.aliyun.com/eip-isp"
 const eipPublicIPAddressPoolID = "k8s.aliyun.com/eip-public-ip-address-pool-id"
 
+// podEnableEIP set to false to opt out the pod from eip
+const podEnableEIP = "terway.alibabacloud.com/enable-eip"
+
+// pod dns override, the value is comma separated list
+const podDNSNameservers = "k8s.aliyun.com/pod-dns-nameservers"
+const podDNSSearch = "k8s.aliyun.com/pod-dns-search"
+const podDNSOptions = "k8s.aliyun.com/pod-dns-options"
+
+// podVSwitch request pod ip from the vswitch given
+const podVSwitch = "terway.alibabacloud.com/vswitch"
+
+// podENIIndex request pod ip from the eni at the device index
+const podENIIndex = "terway.alibabacloud.com/eni-index"
+
+// podCritical mark the pod as critical, which can use the ips reserved
+const podCritical = "terway.alibabacloud.com/critical"
+
+// podPreferPreviousIP reuse the exact ip allocated before if it is still free
+const podPreferPreviousIP = "terway.alibabacloud.com/prefer-previous-ip"
+
+// podDisableRPFilter disable reverse path filter on the pod interfaces, for pod with asymmetric routing
+const podDisableRPFilter = "terway.alibabacloud.com/disable-rp-filter"
+
+// podENICapPolicy override the eni_cap_policy of node, the pod falls back to the other kind of eni if the preferred is not available
+const podENICapPolicy = "terway.alibabacloud.com/eni-cap-policy"
+
+// criticalPriorityClasses the priority classes of critical pods
+var criticalPriorityClasses = sets.NewString("system-node-critical", "system-cluster-critical")
+
+// podSecondaryIPCount request extra eniips for the pod
+const podSecondaryIPCount = "terway.alibabacloud.com/secondary-ip-count"
+
 const defaultStickTimeForSts = 5 * time.Minute
 
 var (
@@ -509,6 +665,7 @@ func convertPod(daemonMode string, statefulWorkloadKindSet sets.String, pod *cor
 	}
 
 	pi.PodNetworkType = podNetworkType(daemonMode, pod)
+	pi.HostNetwork = pod.Spec.HostNetwork
 
 	for _, str := range pod.Status.PodIPs {
 		pi.PodIPs.SetIP(str.IP)
@@ -580,8 +737,51 @@ func convertPod(daemonMode string, statefulWorkloadKindSet sets.String, pod *cor
 	if eipAnnotation, ok := podAnnotation[eipPublicIPAddressPoolID]; ok && eipAnnotation != "" {
 		pi.EipInfo.PodEipPoolID = eipAnnotation
 	}
+	if eipAnnotation, ok := podAnnotation[podEnableEIP]; ok {
+		enabled, err := strconv.ParseBool(strings.TrimSpace(eipAnnotation))
+		pi.EipInfo.PodEipDisabled = err == nil && !enabled
+	}
 
 	pi.SandboxExited = pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded
+	pi.Labels = pod.Labels
+
+	pi.DNS = parsePodDNS(podAnnotation)
+
+	pi.VSwitchID = strings.TrimSpace(podAnnotation[podVSwitch])
+
+	pi.Critical = parseBool(podAnnotation[podCritical]) || criticalPriorityClasses.Has(pod.Spec.PriorityClassName)
+	pi.PreferPreviousIP = parseBool(podAnnotation[podPreferPreviousIP])
+	pi.DisableRPFilter = parseBool(podAnnotation[podDisableRPFilter])
+
+	if policy, ok := podAnnotation[podENICapPolicy]; ok {
+		switch p := types.ENICapPolicy(strings.TrimSpace(policy)); p {
+		case types.ENICapPolicyPreferTrunk, types.ENICapPolicyPreferSecondary:
+			pi.ENICapPolicy = p
+		default:
+			_ = tracing.RecordPodEvent(pod.Name, pod.Namespace, eventTypeWarning,
+				"ParseFailed", fmt.Sprintf("Parse pod annotation %s failed.", podENICapPolicy))
+		}
+	}
+
+	if index, ok := podAnnotation[podENIIndex]; ok {
+		n, err := strconv.Atoi(strings.TrimSpace(index))
+		if err != nil || n < 1 {
+			_ = tracing.RecordPodEvent(pod.Name, pod.Namespace, eventTypeWarning,
+				"ParseFailed", fmt.Sprintf("Parse pod annotation %s failed.", podENIIndex))
+		} else {
+			pi.ENIIndex = n
+		}
+	}
+
+	if count, ok := podAnnotation[podSecondaryIPCount]; ok {
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || n < 0 {
+			_ = tracing.RecordPodEvent(pod.Name, pod.Namespace, eventTypeWarning,
+				"ParseFailed", fmt.Sprintf("Parse pod annotation %s failed.", podSecondaryIPCount))
+		} else {
+			pi.SecondaryIPs = n
+		}
+	}
 
 	if podENI, ok := podAnnotation[types.PodENI]; ok {
 		var err error
@@ -592,15 +792,8 @@ func convertPod(daemonMode string, statefulWorkloadKindSet sets.String, pod *cor
 		}
 	}
 
-	if prio, ok := podAnnotation[types.NetworkPriority]; ok {
-		switch types.NetworkPrio(prio) {
-		case types.NetworkPrioBestEffort, types.NetworkPrioBurstable, types.NetworkPrioGuaranteed:
-			pi.NetworkPriority = prio
-		default:
-			_ = tracing.RecordPodEvent(pod.Name, pod.Namespace, eventTypeWarning,
-				"ParseFailed", fmt.Sprintf("Parse pod annotation %s failed.", types.NetworkPriority))
-		}
-	}
+	// the invalid priority is clamped to the default one on allocation
+	pi.NetworkPriority = podAnnotation[types.NetworkPriority]
 
 	// determine whether pod's IP will stick 5 minutes for a reuse, priorities as below,
 	// 1. pod has a positive pod-ip-reservation annotation
@@ -616,10 +809,38 @@ func convertPod(daemonMode string, statefulWorkloadKindSet sets.String, pod *cor
 			}
 		}
 	}
+	// the ipv6 of pod with ip reserved can be released alone, e.g. the ipv4 is pinned by firewall
+	if v, ok := podAnnotation[types.PodIPv6Reservation]; ok && pi.IPStickTime != 0 {
+		reserved, err := strconv.ParseBool(v)
+		pi.IPv6NoStick = err == nil && !reserved
+	}
 
 	return pi
 }
 
+// parsePodDNS parse dns override from pod annotations, return nil if not set
+func parsePodDNS(podAnnotation map[string]string) *types.DNSConfig {
+	split := func(key string) []string {
+		var res []string
+		for _, v := range strings.Split(podAnnotation[key], ",") {
+			v = strings.TrimSpace(v)
+			if v != "" {
+				res = append(res, v)
+			}
+		}
+		return res
+	}
+	dns := &types.DNSConfig{
+		Nameservers: split(podDNSNameservers),
+		Search:      split(podDNSSearch),
+		Options:     split(podDNSOptions),
+	}
+	if len(dns.Nameservers) == 0 && len(dns.Search) == 0 && len(dns.Options) == 0 {
+		return nil
+	}
+	return dns
+}
+
 func parseBool(s string) bool {
 	b, _ := strconv.ParseBool(s)
 	return b
@@ -689,7 +910,7 @@ func deserialize(data []byte) (interface{}, error) {
 }
 
 func (k *k8s) GetPod(namespace, name string) (*types.PodInfo, error) {
-	pod, err := k.client.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{
+	pod, err := k.criticalClient.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{
 		ResourceVersion: "0",
 	})
 	if err != nil {
@@ -723,6 +944,10 @@ func (k *k8s) GetNodeCidr() *types.IPNetSet {
 	return k.nodeCidr
 }
 
+func (k *k8s) GetNodeName() string {
+	return k.nodeName
+}
+
 func (k *k8s) GetLocalPods() ([]*types.PodInfo, error) {
 	options := metav1.ListOptions{
 		FieldSelector:   fields.OneTermEqualSelector("spec.nodeName", k.nodeName).String(),
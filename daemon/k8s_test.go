@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_serviceCidrFromKubeAPIServer(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-apiserver-master",
+			Namespace: k8sSystemNamespace,
+			Labels:    map[string]string{"component": "kube-apiserver"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    "kube-apiserver",
+				Command: []string{"kube-apiserver", "--secure-port=6443", "--service-cluster-ip-range=fd00::/108,172.21.0.0/20"},
+			}},
+		},
+	})
+	ipNet, err := serviceCidrFromKubeAPIServer(client)
+	assert.NoError(t, err)
+	assert.Equal(t, "172.21.0.0/20", ipNet.String())
+
+	_, err = serviceCidrFromKubeAPIServer(fake.NewSimpleClientset())
+	assert.Error(t, err)
+}
+
+func Test_isNodeTerminating(t *testing.T) {
+	node := &corev1.Node{}
+	assert.False(t, isNodeTerminating(node))
+
+	node.Spec.Taints = []corev1.Taint{{Key: nodeTaintToBeDeleted, Effect: corev1.TaintEffectNoSchedule}}
+	assert.True(t, isNodeTerminating(node))
+
+	now := metav1.Now()
+	assert.True(t, isNodeTerminating(&corev1.Node{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}}))
+}
+
+func Test_convertPodHostNetwork(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+		Spec:       corev1.PodSpec{HostNetwork: true},
+	}
+	assert.True(t, convertPod(daemonModeENIMultiIP, nil, pod).HostNetwork)
+
+	pod.Spec.HostNetwork = false
+	assert.False(t, convertPod(daemonModeENIMultiIP, nil, pod).HostNetwork)
+}
+
+func Test_convertPodDisableRPFilter(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: map[string]string{podDisableRPFilter: "true"}},
+	}
+	assert.True(t, convertPod(daemonModeENIMultiIP, nil, pod).DisableRPFilter)
+
+	pod.Annotations = nil
+	assert.False(t, convertPod(daemonModeENIMultiIP, nil, pod).DisableRPFilter)
+}
+
+func Test_convertPodIPv6NoStick(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", Annotations: map[string]string{types.PodIPReservation: "true", types.PodIPv6Reservation: "false"}},
+	}
+	assert.True(t, convertPod(daemonModeENIMultiIP, nil, pod).IPv6NoStick)
+
+	pod.Annotations[types.PodIPv6Reservation] = "true"
+	assert.False(t, convertPod(daemonModeENIMultiIP, nil, pod).IPv6NoStick)
+
+	// no ip to stick
+	delete(pod.Annotations, types.PodIPReservation)
+	pod.Annotations[types.PodIPv6Reservation] = "false"
+	assert.False(t, convertPod(daemonModeENIMultiIP, nil, pod).IPv6NoStick)
+}
@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// eniRouteTableMin the route table of eni is eniRouteTableMin + the index of the device of eni,
+	// same as the table created by plugin
+	eniRouteTableMin = 1000
+	eniRouteTableMax = eniRouteTableMin + 1<<16
+	// eniRulePriority the priority of the ip rule from pod ip to the route table of eni, same as the rule created by plugin
+	eniRulePriority = 2048
+)
+
+// cleanOrphanRoutes delete the ip rules and routes left in the route tables of the eni deleted,
+// which blackhole the traffic of the pod ip reused
+func (n *networkService) cleanOrphanRoutes() {
+	if n.deleteOrphanRoutes == nil {
+		return
+	}
+	liveMACs, err := n.ecs.GetSecondaryENIMACs(context.Background())
+	if err != nil {
+		serviceLog.Warnf("error get the enis attached, skip clean orphan routes: %v", err)
+		return
+	}
+	deleted, err := n.deleteOrphanRoutes(eniRulePriority, eniRouteTableMin, eniRouteTableMax, liveMACs)
+	if err != nil {
+		serviceLog.Warnf("error clean orphan routes, deleted %v: %v", deleted, err)
+	}
+	if len(deleted) == 0 {
+		return
+	}
+	msg := fmt.Sprintf("deleted %d orphan ip rules and routes of the eni deleted: %s", len(deleted), strings.Join(deleted, "; "))
+	serviceLog.Info(msg)
+	n.k8s.RecordNodeEvent(corev1.EventTypeNormal, "OrphanRoutesDeleted", msg)
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/ipam"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeENIMACsAPI struct {
+	ipam.API
+	macs []string
+	err  error
+}
+
+func (f *fakeENIMACsAPI) GetSecondaryENIMACs(ctx context.Context) ([]string, error) {
+	return f.macs, f.err
+}
+
+func Test_networkService_cleanOrphanRoutes(t *testing.T) {
+	k8s := &eventRecorderK8s{}
+	api := &fakeENIMACsAPI{macs: []string{"mac-1"}}
+	n := &networkService{k8s: k8s, ecs: api}
+	// disabled
+	n.cleanOrphanRoutes()
+
+	var deleted []string
+	n.deleteOrphanRoutes = func(rulePriority, tableMin, tableMax int, liveMACs []string) ([]string, error) {
+		assert.Equal(t, 2048, rulePriority)
+		assert.Equal(t, 1000, tableMin)
+		assert.Equal(t, []string{"mac-1"}, liveMACs)
+		return deleted, nil
+	}
+	n.cleanOrphanRoutes()
+	assert.Empty(t, k8s.events)
+
+	deleted = []string{"ip rule 0: from 192.168.0.1/32 table 1005"}
+	n.cleanOrphanRoutes()
+	assert.Equal(t, []string{"OrphanRoutesDeleted"}, k8s.events)
+
+	// nothing deleted if the enis attached are unknown
+	api.err = fmt.Errorf("metadata unavailable")
+	n.cleanOrphanRoutes()
+	assert.Equal(t, []string{"OrphanRoutesDeleted"}, k8s.events)
+}
@@ -0,0 +1,211 @@
+package daemon
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+)
+
+// maxPodHistoryEntries count of the latest events kept for each pod
+const maxPodHistoryEntries = 64
+
+// actions of pod history
+const (
+	podHistoryAlloc   = "alloc"
+	podHistoryRelease = "release"
+
+	// the resources released by gc, by the reason
+	podHistoryGCPodDeleted      = "gc_pod_deleted"
+	podHistoryGCIPStickExpired  = "gc_ip_stick_expired"
+	podHistoryGCEIPStickExpired = "gc_eip_stick_expired"
+	podHistoryGCIPv6NoStick     = "gc_ipv6_no_stick"
+)
+
+// podHistoryEntry the record of resources allocated or released for a pod
+type podHistoryEntry struct {
+	Time      time.Time
+	Action    string
+	Resources []string
+}
+
+func (e podHistoryEntry) String() string {
+	return fmt.Sprintf("%s %s %s", e.Time.Format(time.RFC3339), e.Action, strings.Join(e.Resources, " "))
+}
+
+// podHistory append only log of the allocation and release of pods, kept in memory for the retention,
+// and bounded by maxPodHistoryEntries for each pod
+type podHistory struct {
+	retention time.Duration
+
+	lock   sync.Mutex
+	pods   map[string][]podHistoryEntry
+	pruned time.Time
+}
+
+func newPodHistory(retention time.Duration) *podHistory {
+	return &podHistory{
+		retention: retention,
+		pods:      make(map[string][]podHistoryEntry),
+	}
+}
+
+// Add record the resources allocated or released for the pod
+func (h *podHistory) Add(podKey, action string, resources []types.ResourceItem) {
+	entry := podHistoryEntry{Time: time.Now(), Action: action}
+	for _, res := range resources {
+		id := fmt.Sprintf("(%s)%s", res.Type, res.ID)
+		if res.IPv4 != "" || res.IPv6 != "" {
+			id = fmt.Sprintf("%s[%s]", id, strings.Trim(res.IPv4+","+res.IPv6, ","))
+		}
+		entry.Resources = append(entry.Resources, id)
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	entries := append(h.pods[podKey], entry)
+	if len(entries) > maxPodHistoryEntries {
+		entries = entries[len(entries)-maxPodHistoryEntries:]
+	}
+	h.pods[podKey] = entries
+	// the pods gone are pruned at most once a minute
+	if entry.Time.Sub(h.pruned) > time.Minute {
+		h.prune(entry.Time)
+	}
+}
+
+func (h *podHistory) prune(now time.Time) {
+	h.pruned = now
+	for key, entries := range h.pods {
+		i := sort.Search(len(entries), func(i int) bool {
+			return now.Sub(entries[i].Time) <= h.retention
+		})
+		if i == len(entries) {
+			delete(h.pods, key)
+			continue
+		}
+		h.pods[key] = entries[i:]
+	}
+}
+
+// Get return the history of the pod in the retention, the oldest first
+func (h *podHistory) Get(podKey string) []podHistoryEntry {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	now := time.Now()
+	var ret []podHistoryEntry
+	for _, entry := range h.pods[podKey] {
+		if now.Sub(entry.Time) <= h.retention {
+			ret = append(ret, entry)
+		}
+	}
+	return ret
+}
+
+// Pods return the pods with history, sorted by key
+func (h *podHistory) Pods() []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	keys := make([]string, 0, len(h.pods))
+	for key := range h.pods {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// gcReleaseOwner the pod the resource released by gc belongs to, and the reason as the history action
+type gcReleaseOwner struct {
+	podKey string
+	action string
+}
+
+// recordGCHistory record the resources released by gc in the history of the pods they belong to
+func (n *networkService) recordGCHistory(released map[string]types.ResourceItem, owners map[string]gcReleaseOwner) {
+	if n.podHistory == nil {
+		return
+	}
+	byOwner := make(map[gcReleaseOwner][]types.ResourceItem)
+	for id, res := range released {
+		owner, ok := owners[id]
+		if !ok {
+			continue
+		}
+		byOwner[owner] = append(byOwner[owner], res)
+	}
+	for owner, resources := range byOwner {
+		sort.Slice(resources, func(i, j int) bool {
+			return resources[i].ID < resources[j].ID
+		})
+		n.podHistory.Add(owner.podKey, owner.action, resources)
+	}
+}
+
+// allocatedResources return the resources allocated for the pod, the ips in netconf are used for
+// the pod allocated by crd, which has no resource managed by the daemon
+func allocatedResources(pod *types.PodInfo, resources []types.ResourceItem, netConfs []*rpc.NetConf) []types.ResourceItem {
+	if len(resources) > 0 {
+		return resources
+	}
+	var ret []types.ResourceItem
+	for _, netConf := range netConfs {
+		if netConf.GetBasicInfo().GetPodIP() == nil {
+			continue
+		}
+		ret = append(ret, types.ResourceItem{
+			Type: podNetworkResourceType(pod.PodNetworkType),
+			ID:   netConf.GetENIInfo().GetMAC(),
+			IPv4: netConf.GetBasicInfo().GetPodIP().GetIPv4(),
+			IPv6: netConf.GetBasicInfo().GetPodIP().GetIPv6(),
+		})
+	}
+	return ret
+}
+
+// listPodHistory write the history of the pod given as namespace/name, or the pods with history if no pod given
+func (n *networkService) listPodHistory(args []string, message chan<- string) {
+	if n.podHistory == nil {
+		message <- "pod history not recorded\n"
+		return
+	}
+	if len(args) == 0 {
+		pods := n.podHistory.Pods()
+		for _, pod := range pods {
+			message <- pod + "\n"
+		}
+		message <- fmt.Sprintf("%d pods with history\n", len(pods))
+		return
+	}
+	for _, pod := range args {
+		entries := n.podHistory.Get(pod)
+		for _, entry := range entries {
+			message <- fmt.Sprintf("%s %s\n", pod, entry)
+		}
+		message <- fmt.Sprintf("%d events of pod %s\n", len(entries), pod)
+	}
+}
+
+// writePodHistoryJSON write the history of the pods given as namespace/name keyed by pod, or the pods with history
+// if no pod given, as json
+func (n *networkService) writePodHistoryJSON(args []string, message chan<- string) {
+	if len(args) == 0 {
+		pods := []string{}
+		if n.podHistory != nil {
+			pods = append(pods, n.podHistory.Pods()...)
+		}
+		writeJSON(message, pods)
+		return
+	}
+	out := make(map[string][]podHistoryEntry, len(args))
+	for _, pod := range args {
+		out[pod] = []podHistoryEntry{}
+		if n.podHistory != nil {
+			out[pod] = append(out[pod], n.podHistory.Get(pod)...)
+		}
+	}
+	writeJSON(message, out)
+}
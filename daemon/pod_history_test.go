@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_podHistory(t *testing.T) {
+	h := newPodHistory(time.Hour)
+	h.Add("default/a", podHistoryAlloc, []types.ResourceItem{{Type: types.ResourceTypeENIIP, ID: "00:00:00:00:00:01.192.168.0.10", IPv4: "192.168.0.10"}})
+	h.Add("default/a", podHistoryRelease, []types.ResourceItem{{Type: types.ResourceTypeENIIP, ID: "00:00:00:00:00:01.192.168.0.10", IPv4: "192.168.0.10"}})
+	h.Add("default/b", podHistoryAlloc, nil)
+
+	entries := h.Get("default/a")
+	assert.Len(t, entries, 2)
+	assert.Equal(t, podHistoryAlloc, entries[0].Action)
+	assert.Equal(t, podHistoryRelease, entries[1].Action)
+	assert.Equal(t, []string{"(eniIp)00:00:00:00:00:01.192.168.0.10[192.168.0.10]"}, entries[1].Resources)
+	assert.Equal(t, []string{"default/a", "default/b"}, h.Pods())
+
+	for i := 0; i < maxPodHistoryEntries+1; i++ {
+		h.Add("default/b", podHistoryAlloc, nil)
+	}
+	assert.Len(t, h.Get("default/b"), maxPodHistoryEntries)
+
+	// out of retention
+	h.prune(time.Now().Add(2 * time.Hour))
+	assert.Empty(t, h.Pods())
+
+	pod := &types.PodInfo{PodNetworkType: podNetworkTypeVPCENI}
+	res := allocatedResources(pod, nil, []*rpc.NetConf{{
+		BasicInfo: &rpc.BasicInfo{PodIP: &rpc.IPSet{IPv4: "192.168.0.11"}},
+		ENIInfo:   &rpc.ENIInfo{MAC: "00:00:00:00:00:02"},
+	}})
+	assert.Equal(t, []types.ResourceItem{{Type: types.ResourceTypeENI, ID: "00:00:00:00:00:02", IPv4: "192.168.0.11"}}, res)
+}
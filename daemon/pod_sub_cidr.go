@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/AliyunContainerService/terway/types"
+	"github.com/AliyunContainerService/terway/types/daemon"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// podSubCIDR the pods selected by the label selector get eniip in the cidr
+type podSubCIDR struct {
+	selector labels.Selector
+	cidr     *net.IPNet
+}
+
+// parsePodSubCIDRs parse the sub cidrs of config, only ipv4 is supported
+func parsePodSubCIDRs(cfgs []daemon.PodSubCIDR) ([]podSubCIDR, error) {
+	var subCIDRs []podSubCIDR
+	for _, cfg := range cfgs {
+		selector, err := labels.Parse(cfg.Selector)
+		if err != nil || selector.Empty() {
+			return nil, fmt.Errorf("invalid selector %q of pod_sub_cidrs: %v", cfg.Selector, err)
+		}
+		_, cidr, err := net.ParseCIDR(cfg.CIDR)
+		if err != nil || cidr.IP.To4() == nil {
+			return nil, fmt.Errorf("invalid cidr %q of pod_sub_cidrs, should be ipv4 cidr", cfg.CIDR)
+		}
+		subCIDRs = append(subCIDRs, podSubCIDR{selector: selector, cidr: cidr})
+	}
+	return subCIDRs, nil
+}
+
+// podSubCIDR return the sub cidr of the first selector matches the pod, nil for none
+func (n *networkService) podSubCIDR(pod *types.PodInfo) *net.IPNet {
+	for _, subCIDR := range n.podSubCIDRs {
+		if subCIDR.selector.Matches(labels.Set(pod.Labels)) {
+			return subCIDR.cidr
+		}
+	}
+	return nil
+}
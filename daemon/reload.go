@@ -0,0 +1,191 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/AliyunContainerService/terway/pkg/backoff"
+	"github.com/AliyunContainerService/terway/pkg/logger"
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types/daemon"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// config fields can be applied without restart
+const (
+	configKeyMaxPoolSize     = "max_pool_size"
+	configKeyMinPoolSize     = "min_pool_size"
+	configKeyBackoffOverride = "backoff_override"
+	configKeyLogLevel        = "log_level"
+)
+
+var reloadableConfigKeys = map[string]bool{
+	configKeyMaxPoolSize:     true,
+	configKeyMinPoolSize:     true,
+	configKeyBackoffOverride: true,
+	configKeyLogLevel:        true,
+}
+
+// ReloadConfig re-read the config file merged with the dynamic config, and apply the reloadable fields.
+// the config is rejected if any other field changed
+func (n *networkService) ReloadConfig(ctx context.Context, r *rpc.Empty) (*rpc.ReloadConfigReply, error) {
+	if n.readOnly {
+		return nil, errStandby
+	}
+	n.reloadLock.Lock()
+	defer n.reloadLock.Unlock()
+
+	dynamicCfg, nodeLabel, err := getDynamicConfig(n.k8s)
+	if err != nil {
+		// not fallback to the default config as startup, which may revert the dynamic config applied
+		return nil, status.Errorf(codes.Unavailable, "error get dynamic config: %v", err)
+	}
+	config, err := daemon.GetConfigFromFileWithMerge(n.configFilePath, []byte(dynamicCfg))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed parse config: %v", err)
+	}
+	if err = validateConfig(config); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid config: %v", err)
+	}
+	if err = setDefault(config); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid config: %v", err)
+	}
+
+	changed, err := configChanges(n.config, config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error compare config: %v", err)
+	}
+	var rejected []string
+	for _, key := range changed {
+		if !reloadableConfigKeys[key] {
+			rejected = append(rejected, key)
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "config %v changed, restart is required", rejected)
+	}
+
+	// validate all the changes before applying any, so the config is applied all or nothing
+	var (
+		resizePool, overrideBackoff, setLogLevel bool
+		resizer                                  ResourceResizer
+		minIdle                                  int
+	)
+	for _, key := range changed {
+		switch key {
+		case configKeyMaxPoolSize, configKeyMinPoolSize:
+			resizePool = true
+		case configKeyBackoffOverride:
+			overrideBackoff = true
+		case configKeyLogLevel:
+			setLogLevel = true
+		}
+	}
+	if resizePool {
+		resizer, minIdle, err = n.poolResizer(config)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "error resize pool: %v", err)
+		}
+	}
+
+	// resize is the only one could fail, apply it first
+	if resizePool {
+		if err = resizer.Resize(minIdle, config.MaxPoolSize); err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "error resize pool: %v", err)
+		}
+	}
+	if overrideBackoff {
+		backoff.OverrideBackoff(config.BackoffOverride)
+	}
+	if setLogLevel {
+		applyLogLevel(config.LogLevel)
+	}
+	n.config = config
+	serviceLog.Infof("config reloaded with dynamic config %q, changed: %v", nodeLabel, changed)
+	return &rpc.ReloadConfigReply{
+		Changed: changed,
+	}, nil
+}
+
+// poolResizer return the resource manager to resize and the min idle of the pool by the config
+func (n *networkService) poolResizer(config *daemon.Config) (ResourceResizer, int, error) {
+	if config.AutoSizePool {
+		return nil, 0, fmt.Errorf("pool size is decided by auto_size_pool")
+	}
+	var (
+		mgr     ResourceManager
+		minIdle = config.MinPoolSize
+	)
+	// min_eni take precedence over min_pool_size as startup
+	switch n.daemonMode {
+	case daemonModeENIMultiIP:
+		mgr = n.eniIPResMgr
+		if eniIPMgr, ok := mgr.(*eniIPResourceManager); ok && config.MinENI != 0 {
+			minIdle = config.MinENI * eniIPMgr.factory.eniMaxIP
+		}
+	case daemonModeENIOnly:
+		mgr = n.eniResMgr
+		if config.MinENI != 0 {
+			minIdle = config.MinENI
+		}
+	}
+	resizer, ok := mgr.(ResourceResizer)
+	if !ok {
+		return nil, 0, fmt.Errorf("resize pool is not supported in daemon mode %s", n.daemonMode)
+	}
+	if minIdle > config.MaxPoolSize {
+		return nil, 0, fmt.Errorf("min pool size %d exceed max pool size %d", minIdle, config.MaxPoolSize)
+	}
+	return resizer, minIdle, nil
+}
+
+// applyLogLevel set the log level configured, the level of command line flag is kept if empty
+func applyLogLevel(level string) {
+	if level == "" {
+		return
+	}
+	if err := logger.SetLevel(level); err != nil {
+		serviceLog.Warnf("error set log level %q: %v", level, err)
+	}
+}
+
+// configChanges return the json keys of the fields differ in the two configs, sorted
+func configChanges(old, new *daemon.Config) ([]string, error) {
+	oldFields, err := configFields(old)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := configFields(new)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for key, value := range newFields {
+		if !reflect.DeepEqual(value, oldFields[key]) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range oldFields {
+		if _, ok := newFields[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// configFields return the fields of config keyed by json key
+func configFields(cfg *daemon.Config) (map[string]interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]interface{})
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
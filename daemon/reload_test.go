@@ -0,0 +1,70 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/logger"
+	"github.com/AliyunContainerService/terway/types/daemon"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeResizeManager struct {
+	ResourceManager
+	minIdle, maxIdle int
+}
+
+func (f *fakeResizeManager) Resize(minIdle, maxIdle int) error {
+	f.minIdle, f.maxIdle = minIdle, maxIdle
+	return nil
+}
+
+func Test_networkService_poolResizer(t *testing.T) {
+	mgr := &fakeResizeManager{}
+	n := &networkService{daemonMode: daemonModeENIOnly, eniResMgr: mgr}
+
+	resizer, minIdle, err := n.poolResizer(&daemon.Config{MinPoolSize: 1, MaxPoolSize: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, mgr, resizer)
+	assert.Equal(t, 1, minIdle)
+
+	// min_eni raised by trunking take precedence over min_pool_size
+	_, minIdle, err = n.poolResizer(&daemon.Config{MinENI: 2, MaxPoolSize: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, minIdle)
+
+	_, _, err = n.poolResizer(&daemon.Config{MinPoolSize: 6, MaxPoolSize: 5})
+	assert.Error(t, err)
+	_, _, err = n.poolResizer(&daemon.Config{AutoSizePool: true, MaxPoolSize: 5})
+	assert.Error(t, err)
+
+	n = &networkService{daemonMode: daemonModeVPC}
+	_, _, err = n.poolResizer(&daemon.Config{MaxPoolSize: 5})
+	assert.Error(t, err)
+}
+
+func Test_applyLogLevel(t *testing.T) {
+	level := logger.DefaultLogger.GetLevel()
+	t.Cleanup(func() {
+		logger.DefaultLogger.SetLevel(level)
+	})
+	applyLogLevel("debug")
+	assert.Equal(t, "debug", logger.DefaultLogger.GetLevel().String())
+	// empty keep the level of command line flag
+	applyLogLevel("")
+	assert.Equal(t, "debug", logger.DefaultLogger.GetLevel().String())
+
+	assert.Error(t, validateConfig(&daemon.Config{LogLevel: "foo"}))
+	assert.NoError(t, validateConfig(&daemon.Config{LogLevel: "info"}))
+}
+
+func Test_configChanges(t *testing.T) {
+	old := &daemon.Config{MaxPoolSize: 5, IPStack: "ipv4"}
+	changed, err := configChanges(old, &daemon.Config{MaxPoolSize: 5, IPStack: "ipv4"})
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+
+	changed, err = configChanges(old, &daemon.Config{MaxPoolSize: 10, MinPoolSize: 1, IPStack: "dual"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ip_stack", configKeyMaxPoolSize, configKeyMinPoolSize}, changed)
+}
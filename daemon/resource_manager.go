@@ -1,14 +1,24 @@
 package daemon
 
 import (
+	"context"
+	"sync"
+
 	"github.com/AliyunContainerService/terway/pkg/tracing"
 
 	"github.com/AliyunContainerService/terway/types"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 const (
 	resDBPath = "/var/lib/cni/terway/ResRelation.db"
 	resDBName = "relation"
+
+	// restartCountPath file counting the daemon started on node, beside the resource db
+	restartCountPath = "/var/lib/cni/terway/restart_count"
+
+	defaultRestoreParallelism = 4
 )
 
 type resourceManagerInitItem struct {
@@ -16,12 +26,62 @@ type resourceManagerInitItem struct {
 	podInfo *types.PodInfo
 }
 
+// restoreParallel call fn for each index in [0, count) by at most parallelism workers,
+// errors of all calls are aggregated
+func restoreParallel(count, parallelism int, fn func(i int) error) error {
+	if parallelism <= 0 {
+		parallelism = defaultRestoreParallelism
+	}
+	errs := make([]error, count)
+	tokens := make(chan struct{}, parallelism)
+	wg := sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		tokens <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-tokens
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return utilerrors.NewAggregate(errs)
+}
+
 // ResourceManager Allocate/Release/Pool/Stick/GC pod resource
 // managed pod and resource relationship
 type ResourceManager interface {
 	Allocate(context *networkContext, prefer string) (types.NetworkResource, error)
 	Release(context *networkContext, resItem types.ResourceItem) error
-	GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) error
+	// GarbageCollection release the expired resources, return the count of them released
+	GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) (int, error)
 	Stat(context *networkContext, resID string) (types.NetworkResource, error)
 	tracing.ResourceMappingHandler
 }
+
+// ResourceCounter report the count of resource can be allocated
+type ResourceCounter interface {
+	Free() int
+}
+
+// ResourceCapacity report the count of resource can be created and the max count of resource
+type ResourceCapacity interface {
+	Capacity() (creatable, capacity int)
+}
+
+// ResourceWarmer pre-allocate resource to pool
+type ResourceWarmer interface {
+	Warm(ctx context.Context, idle int) (int, error)
+}
+
+// ResourceResizer change the min and max idle of the pool
+type ResourceResizer interface {
+	Resize(minIdle, maxIdle int) error
+}
+
+// ResourceDrainer release all idle resource in pool
+type ResourceDrainer interface {
+	Drain(ctx context.Context) (int, error)
+}
@@ -0,0 +1,40 @@
+package daemon
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_restoreParallel(t *testing.T) {
+	var running, maxRunning int32
+	done := make([]bool, 10)
+	err := restoreParallel(len(done), 3, func(i int) error {
+		cur := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			old := atomic.LoadInt32(&maxRunning)
+			if cur <= old || atomic.CompareAndSwapInt32(&maxRunning, old, cur) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		done[i] = true
+		if i%4 == 1 {
+			return fmt.Errorf("error restore %d", i)
+		}
+		return nil
+	})
+	assert.EqualError(t, err, "[error restore 1, error restore 5, error restore 9]")
+	assert.LessOrEqual(t, maxRunning, int32(3))
+	for i := range done {
+		assert.True(t, done[i])
+	}
+
+	assert.NoError(t, restoreParallel(0, 0, func(i int) error {
+		return fmt.Errorf("never called")
+	}))
+}
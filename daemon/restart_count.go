@@ -0,0 +1,28 @@
+package daemon
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// increaseRestartCount return the times daemon started before, and count the start in the file.
+// it's best effort, the count is reset if the file is lost or broken
+func increaseRestartCount(path string) int {
+	count := 0
+	data, err := os.ReadFile(path)
+	if err == nil {
+		count, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil {
+			serviceLog.Warnf("error parse restart count in %s, reset it: %v", path, err)
+			count = 0
+		}
+	} else if !os.IsNotExist(err) {
+		serviceLog.Warnf("error read restart count from %s: %v", path, err)
+	}
+	err = os.WriteFile(path, []byte(strconv.Itoa(count+1)), 0644)
+	if err != nil {
+		serviceLog.Warnf("error write restart count to %s: %v", path, err)
+	}
+	return count
+}
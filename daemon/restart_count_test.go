@@ -0,0 +1,34 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/pkg/tracing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_increaseRestartCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "restart_count")
+	assert.Equal(t, 0, increaseRestartCount(path))
+	assert.Equal(t, 1, increaseRestartCount(path))
+	assert.Equal(t, 2, increaseRestartCount(path))
+
+	// reset the broken one
+	assert.NoError(t, os.WriteFile(path, []byte("foo"), 0644))
+	assert.Equal(t, 0, increaseRestartCount(path))
+	assert.Equal(t, 1, increaseRestartCount(path))
+
+	// not persisted
+	assert.Equal(t, 0, increaseRestartCount(filepath.Join(path, "not-exist", "restart_count")))
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := &networkService{resourceDB: storage.NewMemoryStorage(), startTime: start, restartCount: 2}
+	trace := n.Trace()
+	assert.Contains(t, trace, tracing.MapKeyValueEntry{Key: tracingKeyStartTime, Value: "2022-01-01T00:00:00Z"})
+	assert.Contains(t, trace, tracing.MapKeyValueEntry{Key: tracingKeyRestartCount, Value: "2"})
+}
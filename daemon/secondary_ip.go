@@ -0,0 +1,104 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AliyunContainerService/terway/pkg/tracing"
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+)
+
+// secondaryIfName return the interface name of the i-th secondary ip, eth0 is kept for the primary one
+func secondaryIfName(i int) string {
+	return fmt.Sprintf("eth%d", i+1)
+}
+
+// checkSecondaryIPs check the secondary ip count requested by pod is within the config and instance limit
+func (n *networkService) checkSecondaryIPs(podinfo *types.PodInfo) error {
+	count := podinfo.SecondaryIPs
+	if count == 0 {
+		return nil
+	}
+	if count > n.maxSecondaryIPCount {
+		return fmt.Errorf("secondary ip count %d exceed max_secondary_ip_count %d", count, n.maxSecondaryIPCount)
+	}
+	if n.limit != nil && count+1 > n.limit.MultiIPPod() {
+		return fmt.Errorf("secondary ip count %d exceed instance ip limit %d", count, n.limit.MultiIPPod())
+	}
+	return nil
+}
+
+// allocateSecondaryIPs allocate the extra eniips of the pod, allocated ones are recorded in ctx for rollback.
+// the secondary eniips stored in the old record are preferred, so they stick to the pod as the primary one
+func (n *networkService) allocateSecondaryIPs(ctx *networkContext, old *types.PodResources) ([]*types.ENIIP, error) {
+	prefers := previousSecondaryIPs(old)
+	var eniIPs []*types.ENIIP
+	for i := 0; i < ctx.pod.SecondaryIPs; i++ {
+		prefer := ""
+		if i < len(prefers) {
+			prefer = prefers[i]
+		}
+		_, endSpan := startSpan(ctx, "Allocate", tracing.Attr("resource_type", types.ResourceTypeENIIP))
+		res, err := n.eniIPResMgr.Allocate(ctx, prefer)
+		endSpan(err)
+		if err != nil {
+			return nil, n.throttledErr(ctx, err)
+		}
+		eniIP := res.(*types.ENIIP)
+		ctx.resources = append(ctx.resources, eniIP.ToResItems()...)
+		eniIPs = append(eniIPs, eniIP)
+	}
+	return eniIPs, nil
+}
+
+// previousSecondaryIPs return the resource id of the secondary eniips in the old record, the first eniip is the primary one
+func previousSecondaryIPs(old *types.PodResources) []string {
+	if old == nil || old.PodInfo == nil {
+		return nil
+	}
+	items := old.GetResourceItemByType(types.ResourceTypeENIIP)
+	if len(items) <= 1 || len(items) > 1+old.PodInfo.SecondaryIPs {
+		return nil
+	}
+	var ids []string
+	for _, item := range items[1:] {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+// singleENIOf return the mac of eni if all the eniips are on it, empty if they are spread across enis
+func singleENIOf(primary *types.ENIIP, secondaryIPs []*types.ENIIP) string {
+	for _, eniIP := range secondaryIPs {
+		if eniIP.ENI.MAC != primary.ENI.MAC {
+			return ""
+		}
+	}
+	return primary.ENI.MAC
+}
+
+// secondaryIPNetConf return the net conf of the secondary ip, which never has default route
+func (n *networkService) secondaryIPNetConf(ctx context.Context, podinfo *types.PodInfo, eniIP *types.ENIIP, ifName string) *rpc.NetConf {
+	return &rpc.NetConf{
+		BasicInfo: &rpc.BasicInfo{
+			PodIP:       eniIP.IPSet.ToRPC(),
+			PodCIDR:     n.podCIDRForENIIP(ctx, eniIP).ToRPC(),
+			GatewayIP:   eniIP.ENI.GatewayIP.ToRPC(),
+			ServiceCIDR: n.k8s.GetServiceCIDR().ToRPC(),
+		},
+		ENIInfo: &rpc.ENIInfo{
+			MAC:             eniIP.ENI.MAC,
+			Trunk:           false,
+			ENIPrimaryIP:    eniIP.ENI.PrimaryIP.ToRPC(),
+			DisableRPFilter: podinfo.DisableRPFilter,
+		},
+		Pod: &rpc.Pod{
+			Ingress:         podinfo.TcIngress,
+			Egress:          podinfo.TcEgress,
+			NetworkPriority: podinfo.NetworkPriority,
+		},
+		IfName:       ifName,
+		DefaultRoute: false,
+	}
+}
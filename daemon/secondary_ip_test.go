@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/aliyun"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkSecondaryIPs(t *testing.T) {
+	n := &networkService{}
+	assert.NoError(t, n.checkSecondaryIPs(&types.PodInfo{}))
+	assert.Error(t, n.checkSecondaryIPs(&types.PodInfo{SecondaryIPs: 1}))
+
+	n.maxSecondaryIPCount = 4
+	assert.NoError(t, n.checkSecondaryIPs(&types.PodInfo{SecondaryIPs: 4}))
+	assert.Error(t, n.checkSecondaryIPs(&types.PodInfo{SecondaryIPs: 5}))
+
+	n.limit = &aliyun.Limits{Adapters: 2, IPv4PerAdapter: 3}
+	assert.NoError(t, n.checkSecondaryIPs(&types.PodInfo{SecondaryIPs: 2}))
+	assert.Error(t, n.checkSecondaryIPs(&types.PodInfo{SecondaryIPs: 3}))
+
+	assert.Equal(t, "eth1", secondaryIfName(0))
+}
+
+type preferRecordManager struct {
+	ResourceManager
+	prefers []string
+}
+
+func (m *preferRecordManager) Allocate(ctx *networkContext, prefer string) (types.NetworkResource, error) {
+	m.prefers = append(m.prefers, prefer)
+	return &types.ENIIP{ENI: &types.ENI{MAC: "mac-1"}, IPSet: types.IPSet{IPv4: net.ParseIP("192.168.0.1")}}, nil
+}
+
+func Test_networkService_allocateSecondaryIPs(t *testing.T) {
+	mgr := &preferRecordManager{}
+	n := &networkService{eniIPResMgr: mgr}
+	ctx := &networkContext{Context: context.Background(), pod: &types.PodInfo{SecondaryIPs: 2}}
+	old := &types.PodResources{
+		PodInfo: &types.PodInfo{SecondaryIPs: 1},
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1"},
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.2"},
+		},
+	}
+
+	// the secondary eniip of the old record is preferred, the one more requested is allocated from any
+	eniIPs, err := n.allocateSecondaryIPs(ctx, old)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(eniIPs))
+	assert.Equal(t, []string{"mac-1.192.168.0.2", ""}, mgr.prefers)
+	assert.Equal(t, 2, len(ctx.resources))
+
+	mgr.prefers = nil
+	_, err = n.allocateSecondaryIPs(ctx, &types.PodResources{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", ""}, mgr.prefers)
+}
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"runtime"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/AliyunContainerService/terway/pkg/logger"
 	"github.com/AliyunContainerService/terway/pkg/metric"
@@ -24,6 +26,9 @@ import (
 	"google.golang.org/grpc"
 )
 
+// shutdownTimeout max time spent on releasing resources when daemon stop
+const shutdownTimeout = 30 * time.Second
+
 // stackTriger print golang stack trace to log
 func stackTriger() {
 	sigchain := make(chan os.Signal, 1)
@@ -125,6 +130,10 @@ func Run(pidFilePath, socketFilePath, debugSocketListen, configFilePath, kubecon
 
 	<-stop
 	grpcServer.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	networkService.shutdown(ctx)
 	return nil
 }
 
@@ -170,7 +179,12 @@ func runDebugServer(debugSocketListen string) error {
 // RegisterPrometheus register metrics to prometheus server
 func registerPrometheus() {
 	prometheus.MustRegister(metric.RPCLatency)
+	prometheus.MustRegister(metric.RollbackCount)
+	prometheus.MustRegister(metric.DuplicateContainerIDCount)
+	prometheus.MustRegister(metric.InvalidResourceMappingCount)
+	prometheus.MustRegister(metric.ContainerIDMismatchCount)
 	prometheus.MustRegister(metric.OpenAPILatency)
+	prometheus.MustRegister(metric.OpenAPICallCount)
 	prometheus.MustRegister(metric.MetadataLatency)
 	// ResourcePool
 	prometheus.MustRegister(metric.ResourcePoolTotal)
@@ -180,4 +194,6 @@ func registerPrometheus() {
 	prometheus.MustRegister(metric.ENIIPFactoryIPCount)
 	prometheus.MustRegister(metric.ENIIPFactoryENICount)
 	prometheus.MustRegister(metric.ENIIPFactoryIPAllocCount)
+	prometheus.MustRegister(metric.ENIQuotaUsage)
+	prometheus.MustRegister(metric.ENIAttachLatency)
 }
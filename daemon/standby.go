@@ -0,0 +1,125 @@
+package daemon
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/pkg/tracing"
+	"github.com/AliyunContainerService/terway/types"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// standbyRefreshPeriod the period the active daemon snapshot the resource db and the standby daemon reload it
+const standbyRefreshPeriod = 10 * time.Second
+
+// errStandby is returned by the rpc change resources on the read only standby daemon
+var errStandby = status.Error(codes.Unavailable, "daemon is a read only standby")
+
+// standbyResourceManager stand in for the resource managers on the standby daemon,
+// the pools are not started so no resource is allocated or released by the standby.
+// the resources are served from the records in resource db and the eni in instance metadata
+type standbyResourceManager struct {
+	resType string
+	db      storage.Storage
+	// eniByMAC return the eni attached by mac from instance metadata
+	eniByMAC func(mac string) (*types.ENI, error)
+}
+
+func newStandbyResourceManager(resType string, db storage.Storage, eniByMAC func(mac string) (*types.ENI, error)) *standbyResourceManager {
+	return &standbyResourceManager{resType: resType, db: db, eniByMAC: eniByMAC}
+}
+
+func (m *standbyResourceManager) Allocate(*networkContext, string) (types.NetworkResource, error) {
+	return nil, errStandby
+}
+
+func (m *standbyResourceManager) Release(*networkContext, types.ResourceItem) error {
+	return errStandby
+}
+
+func (m *standbyResourceManager) GarbageCollection(map[string]types.ResourceItem, map[string]types.ResourceItem) (int, error) {
+	return 0, errStandby
+}
+
+// Stat build the resource from the record stored, the eni is looked up from instance metadata by mac
+func (m *standbyResourceManager) Stat(ctx *networkContext, resID string) (types.NetworkResource, error) {
+	if m.resType != types.ResourceTypeENI && m.resType != types.ResourceTypeENIIP {
+		return nil, errStandby
+	}
+	item, err := m.findItem(resID)
+	if err != nil {
+		return nil, err
+	}
+	mac := item.ENIMAC
+	if mac == "" {
+		// compatible with the resource stored by old version, the id is mac or mac.ip
+		mac = strings.SplitN(item.ID, ".", 2)[0]
+	}
+	eni, err := m.eniByMAC(mac)
+	if err != nil {
+		return nil, fmt.Errorf("error get eni %s from metadata: %w", mac, err)
+	}
+	if m.resType == types.ResourceTypeENI {
+		return eni, nil
+	}
+	ipv4 := item.IPv4
+	if ipv4 == "" {
+		if list := strings.SplitN(item.ID, ".", 2); len(list) == 2 {
+			ipv4 = list[1]
+		}
+	}
+	return &types.ENIIP{
+		ENI:   eni,
+		IPSet: types.IPSet{IPv4: net.ParseIP(ipv4), IPv6: net.ParseIP(item.IPv6)},
+	}, nil
+}
+
+// findItem return the resource item of the type stored in resource db
+func (m *standbyResourceManager) findItem(resID string) (types.ResourceItem, error) {
+	resRelateList, err := m.db.List()
+	if err != nil {
+		return types.ResourceItem{}, err
+	}
+	for _, resRelateObj := range resRelateList {
+		for _, item := range resRelateObj.(types.PodResources).Resources {
+			if item.Type == m.resType && item.ID == resID {
+				return item, nil
+			}
+		}
+	}
+	return types.ResourceItem{}, fmt.Errorf("resource %s of %s not found in resource db", resID, m.resType)
+}
+
+func (m *standbyResourceManager) GetResourceMapping() (tracing.ResourcePoolStats, error) {
+	return nil, errStandby
+}
+
+// refreshStandbyDB keep the view of resource db warm from the snapshot written by the active daemon
+func (n *networkService) refreshStandbyDB() {
+	db, ok := n.resourceDB.(*storage.ReadOnlyDiskStorage)
+	if !ok {
+		return
+	}
+	err := db.Refresh()
+	if err != nil {
+		serviceLog.Debugf("standby keep the previous view of resource db: %v", err)
+	}
+}
+
+// snapshotResourceDB write the snapshot of resource db to the separate file for the standby daemon,
+// the db itself is file locked while the active daemon running
+func (n *networkService) snapshotResourceDB() {
+	db, ok := n.resourceDB.(*storage.DiskStorage)
+	if !ok || n.standbySnapshotPath == "" {
+		return
+	}
+	err := db.Snapshot(n.standbySnapshotPath)
+	if err != nil {
+		serviceLog.Warnf("error snapshot resource db for standby: %v", err)
+	}
+}
@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/rpc"
+	"github.com/AliyunContainerService/terway/types"
+	"github.com/AliyunContainerService/terway/types/daemon"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_networkService_readOnly(t *testing.T) {
+	n := &networkService{readOnly: true}
+	_, err := n.AllocIP(context.Background(), &rpc.AllocIPRequest{K8SPodName: "foo", K8SPodNamespace: "default"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = n.ReleaseIP(context.Background(), &rpc.ReleaseIPRequest{K8SPodName: "foo", K8SPodNamespace: "default"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = n.TriggerGC(context.Background(), &rpc.Empty{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func Test_networkService_snapshotResourceDB(t *testing.T) {
+	dir := t.TempDir()
+	db, err := storage.NewDiskStorage(resDBName, filepath.Join(dir, "db"), marshalPodResources, unmarshalPodResources)
+	assert.NoError(t, err)
+	podRes := types.PodResources{PodInfo: &types.PodInfo{Namespace: "default", Name: "foo"}}
+	assert.NoError(t, db.Put(podInfoKey("default", "foo"), podRes))
+
+	// snapshot is disabled by default
+	n := &networkService{resourceDB: db}
+	n.snapshotResourceDB()
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	n.standbySnapshotPath = filepath.Join(dir, "db.snapshot")
+	n.snapshotResourceDB()
+	standby := storage.NewReadOnlyDiskStorage(resDBName, n.standbySnapshotPath, unmarshalPodResources)
+	obj, err := standby.Get(podInfoKey("default", "foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", obj.(types.PodResources).PodInfo.Name)
+
+	assert.Error(t, validateConfig(&daemon.Config{ReadOnly: true}))
+	assert.NoError(t, validateConfig(&daemon.Config{ReadOnly: true, StandbySnapshotPath: n.standbySnapshotPath}))
+}
+
+func Test_standbyResourceManager_Stat(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	assert.NoError(t, db.Put("default/foo", types.PodResources{
+		PodInfo: &types.PodInfo{Namespace: "default", Name: "foo"},
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.2", ENIID: "eni-1", ENIMAC: "mac-1", IPv4: "192.168.0.2"},
+		},
+	}))
+	eni := &types.ENI{ID: "eni-1", MAC: "mac-1", GatewayIP: types.IPSet{IPv4: net.ParseIP("192.168.0.253")}}
+	eniByMAC := func(mac string) (*types.ENI, error) {
+		if mac != eni.MAC {
+			return nil, fmt.Errorf("eni %s not found", mac)
+		}
+		return eni, nil
+	}
+
+	m := newStandbyResourceManager(types.ResourceTypeENIIP, db, eniByMAC)
+	res, err := m.Stat(nil, "mac-1.192.168.0.2")
+	assert.NoError(t, err)
+	eniIP := res.(*types.ENIIP)
+	assert.Equal(t, eni, eniIP.ENI)
+	assert.Equal(t, "192.168.0.2", eniIP.IPSet.IPv4.String())
+
+	_, err = m.Stat(nil, "mac-1.192.168.0.3")
+	assert.Error(t, err)
+
+	// the resource changed are still rejected on standby
+	_, err = m.Allocate(nil, "")
+	assert.Equal(t, errStandby, err)
+	_, err = newStandbyResourceManager(types.ResourceTypeVeth, db, eniByMAC).Stat(nil, "veth-1")
+	assert.Equal(t, errStandby, err)
+}
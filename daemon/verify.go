@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AliyunContainerService/terway/types"
+)
+
+// verifyResource compare the resources in db with the enis attached to this instance in ecs,
+// report the ones missing on either side to message. nothing is changed
+func (n *networkService) verifyResource(ctx context.Context, message chan<- string) {
+	var (
+		resType string
+		mgr     ResourceManager
+	)
+	switch n.daemonMode {
+	case daemonModeENIMultiIP:
+		resType, mgr = types.ResourceTypeENIIP, n.eniIPResMgr
+	case daemonModeENIOnly:
+		resType, mgr = types.ResourceTypeENI, n.eniResMgr
+	default:
+		message <- fmt.Sprintf("nothing to verify in daemon mode %s\n", n.daemonMode)
+		return
+	}
+
+	// resources in ecs
+	message <- "fetching attached ENIs from aliyun\n"
+	enis, err := n.ecs.GetAttachedENIs(ctx, false, "")
+	if err != nil {
+		message <- fmt.Sprintf("error while fetching from remote: %s\n", err.Error())
+		return
+	}
+	remote := make(map[string]struct{})
+	for _, eni := range enis {
+		if resType == types.ResourceTypeENI {
+			remote[eni.MAC] = struct{}{}
+			continue
+		}
+		ipv4s, ipv6s, err := n.ecs.GetENIIPs(ctx, eni.MAC)
+		if err != nil {
+			message <- fmt.Sprintf("error while fetching ips of eni %s from remote: %s\n", eni.ID, err.Error())
+			return
+		}
+		for _, ip := range append(ipv4s, ipv6s...) {
+			remote[eni.MAC+"/"+ip.String()] = struct{}{}
+		}
+	}
+	message <- fmt.Sprintf("%d enis, %d %s fetched\n", len(enis), len(remote), resType)
+
+	// resources in db and pool
+	n.RLock()
+	podResList, err := n.resourceDB.List()
+	if err != nil {
+		n.RUnlock()
+		message <- fmt.Sprintf("error list resource db: %s\n", err.Error())
+		return
+	}
+	var poolRes []string
+	if mgr != nil {
+		stats, err := mgr.GetResourceMapping()
+		if err != nil {
+			n.RUnlock()
+			message <- fmt.Sprintf("error get resource mapping of pool: %s\n", err.Error())
+			return
+		}
+		for id := range stats.GetLocal() {
+			poolRes = append(poolRes, id)
+		}
+	}
+	n.RUnlock()
+
+	mismatched := 0
+	known := make(map[string]struct{})
+	for _, v := range podResList {
+		podRes := v.(types.PodResources)
+		for _, res := range podRes.Resources {
+			if res.Type != resType {
+				continue
+			}
+			for _, key := range resourceKeys(res) {
+				known[key] = struct{}{}
+				if _, ok := remote[key]; ok {
+					continue
+				}
+				mismatched++
+				message <- fmt.Sprintf("db has but ecs missing: pod %s, %s %s\n",
+					podInfoKey(podRes.PodInfo.Namespace, podRes.PodInfo.Name), resType, key)
+			}
+		}
+	}
+	// idle resources are held by pool
+	for _, id := range poolRes {
+		for _, key := range resourceKeys(types.ResourceItem{Type: resType, ID: id}) {
+			known[key] = struct{}{}
+		}
+	}
+
+	var missing []string
+	for key := range remote {
+		if _, ok := known[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	for _, key := range missing {
+		mismatched++
+		message <- fmt.Sprintf("ecs has but db missing: %s %s\n", resType, key)
+	}
+
+	message <- fmt.Sprintf("verify finished, %d mismatched\n", mismatched)
+}
+
+// resourceKeys return the keys identify the eni or eni ips of the resource,
+// eni is identified by mac and eni ip by mac/ip
+func resourceKeys(res types.ResourceItem) []string {
+	mac := res.ENIMAC
+	if mac == "" {
+		// compatible with the resource stored by old version, the id is mac or mac.ip
+		mac = strings.SplitN(res.ID, ".", 2)[0]
+	}
+	if res.Type == types.ResourceTypeENI {
+		return []string{mac}
+	}
+
+	var ips []string
+	if res.IPv4 != "" || res.IPv6 != "" {
+		ips = []string{res.IPv4, res.IPv6}
+	} else if parts := strings.SplitN(res.ID, ".", 2); len(parts) == 2 {
+		// the ip part of id is ipv4-ipv6
+		ips = strings.Split(parts[1], "-")
+	}
+
+	var keys []string
+	for _, ip := range ips {
+		if ip != "" {
+			keys = append(keys, mac+"/"+ip)
+		}
+	}
+	return keys
+}
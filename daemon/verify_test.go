@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/AliyunContainerService/terway/pkg/ipam"
+	"github.com/AliyunContainerService/terway/pkg/storage"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVerifyAPI struct {
+	ipam.API
+	enis []*types.ENI
+	ips  map[string][]net.IP
+}
+
+func (f *fakeVerifyAPI) GetAttachedENIs(ctx context.Context, containsMainENI bool, trunkENIID string) ([]*types.ENI, error) {
+	return f.enis, nil
+}
+
+func (f *fakeVerifyAPI) GetENIIPs(ctx context.Context, mac string) ([]net.IP, []net.IP, error) {
+	return f.ips[mac], nil, nil
+}
+
+func Test_verifyResource(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	info := &types.PodInfo{Namespace: "default", Name: "foo"}
+	assert.NoError(t, db.Put(podInfoKey(info.Namespace, info.Name), types.PodResources{
+		PodInfo: info,
+		Resources: []types.ResourceItem{
+			{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1", ENIMAC: "mac-1", IPv4: "192.168.0.1"},
+			{Type: types.ResourceTypeENIIP, ID: "mac-2.192.168.0.2"},
+		},
+	}))
+	n := &networkService{
+		daemonMode: daemonModeENIMultiIP,
+		resourceDB: db,
+		ecs: &fakeVerifyAPI{
+			enis: []*types.ENI{{ID: "eni-1", MAC: "mac-1"}},
+			ips:  map[string][]net.IP{"mac-1": {net.ParseIP("192.168.0.1"), net.ParseIP("192.168.0.3")}},
+		},
+	}
+	message := make(chan string, 10)
+	n.verifyResource(context.Background(), message)
+	close(message)
+	var out []string
+	for msg := range message {
+		out = append(out, msg)
+	}
+	result := strings.Join(out, "")
+	assert.Contains(t, result, "db has but ecs missing: pod default/foo, eniIp mac-2/192.168.0.2")
+	assert.Contains(t, result, "ecs has but db missing: eniIp mac-1/192.168.0.3")
+	assert.Contains(t, result, "2 mismatched")
+}
+
+func Test_resourceKeys(t *testing.T) {
+	assert.Equal(t, []string{"mac-1"}, resourceKeys(types.ResourceItem{Type: types.ResourceTypeENI, ID: "mac-1"}))
+	assert.Equal(t, []string{"mac-1/192.168.0.1", "mac-1/fd00::1"},
+		resourceKeys(types.ResourceItem{Type: types.ResourceTypeENIIP, ID: "mac-1.192.168.0.1-fd00::1"}))
+}
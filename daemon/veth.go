@@ -42,20 +42,20 @@ func (*vethResourceManager) Release(context *networkContext, resItem types.Resou
 	return nil
 }
 
-func (f *vethResourceManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) error {
+func (f *vethResourceManager) GarbageCollection(inUseResSet map[string]types.ResourceItem, expireResSet map[string]types.ResourceItem) (int, error) {
 	// fixme do gc on cni binary
 	lock, err := disk.NewFileLock(defaultIpamPath)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer lock.Close()
 	err = lock.Lock()
 	if err != nil {
-		return err
+		return 0, err
 	}
 	sandboxList, err := f.runtimeAPI.GetRunningSandbox()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	sandboxStubSet := make(map[string]interface{})
@@ -66,7 +66,7 @@ func (f *vethResourceManager) GarbageCollection(inUseResSet map[string]types.Res
 	files, err := os.ReadDir(defaultIpamPath)
 	if err != nil {
 		log.Errorf("Failed to list files in %q: %v", defaultIpamPath, err)
-		return fmt.Errorf("failed to list files in %q: %v", defaultIpamPath, err)
+		return 0, fmt.Errorf("failed to list files in %q: %v", defaultIpamPath, err)
 	}
 
 	// gather containerIDs for allocated ips
@@ -100,16 +100,20 @@ func (f *vethResourceManager) GarbageCollection(inUseResSet map[string]types.Res
 		ipContainerIDMap[file.Name()] = strings.TrimSpace(string(content))
 	}
 
+	// the veth resources have nothing to release, the leaked ips are counted instead
+	released := 0
 	for ip, containerID := range ipContainerIDMap {
 		if _, ok := sandboxStubSet[containerID]; !ok && containerID != "" {
 			log.Warnf("detect ip address leak: %s, removing", ip)
 			err := os.Remove(filepath.Join(defaultIpamPath, ip))
 			if err != nil {
 				log.Errorf("error remove leak ip: %s, err: %v", ip, err)
+				continue
 			}
+			released++
 		}
 	}
-	return nil
+	return released, nil
 }
 
 func (f *vethResourceManager) GetResourceMapping() (tracing.ResourcePoolStats, error) {
@@ -0,0 +1,130 @@
+package daemon
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/ipam"
+	"github.com/AliyunContainerService/terway/types"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+)
+
+type cachedVSwitch struct {
+	vsw      vpc.VSwitch
+	expireAt time.Time
+}
+
+// vSwitchCachedAPI cache the vswitches described by openapi for ttl, shared by all the vswitch lookups of daemon.
+// the cached vswitch is dropped once the describe failed
+type vSwitchCachedAPI struct {
+	ipam.API
+	ttl time.Duration
+
+	lock      sync.Mutex
+	vSwitches map[string]cachedVSwitch
+}
+
+func newVSwitchCachedAPI(api ipam.API, ttl time.Duration) *vSwitchCachedAPI {
+	return &vSwitchCachedAPI{
+		API:       api,
+		ttl:       ttl,
+		vSwitches: make(map[string]cachedVSwitch),
+	}
+}
+
+// DescribeVSwitchByID return the cached vswitch if it's not expired
+func (c *vSwitchCachedAPI) DescribeVSwitchByID(ctx context.Context, vSwitchID string) (*vpc.VSwitch, error) {
+	c.lock.Lock()
+	cached, ok := c.vSwitches[vSwitchID]
+	c.lock.Unlock()
+	if ok && time.Now().Before(cached.expireAt) {
+		vsw := cached.vsw
+		return &vsw, nil
+	}
+
+	vsw, err := c.API.DescribeVSwitchByID(ctx, vSwitchID)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err != nil {
+		delete(c.vSwitches, vSwitchID)
+		return nil, err
+	}
+	c.vSwitches[vSwitchID] = cachedVSwitch{vsw: *vsw, expireAt: time.Now().Add(c.ttl)}
+	return vsw, nil
+}
+
+// vSwitchCIDRCache cache the cidr of vswitch fetched from openapi, the cidr of vswitch never change
+type vSwitchCIDRCache struct {
+	ecs ipam.API
+
+	lock  sync.Mutex
+	cidrs map[string]*types.IPNetSet
+}
+
+func newVSwitchCIDRCache(ecs ipam.API) *vSwitchCIDRCache {
+	return &vSwitchCIDRCache{
+		ecs:   ecs,
+		cidrs: make(map[string]*types.IPNetSet),
+	}
+}
+
+// Get return the cidr of the vswitch
+func (c *vSwitchCIDRCache) Get(ctx context.Context, vSwitchID string) (*types.IPNetSet, error) {
+	c.lock.Lock()
+	cidr, ok := c.cidrs[vSwitchID]
+	c.lock.Unlock()
+	if ok {
+		return cidr, nil
+	}
+
+	vsw, err := c.ecs.DescribeVSwitchByID(ctx, vSwitchID)
+	if err != nil {
+		return nil, err
+	}
+	cidr = &types.IPNetSet{}
+	cidr.SetIPNet(vsw.CidrBlock)
+	cidr.SetIPNet(vsw.Ipv6CidrBlock)
+
+	c.lock.Lock()
+	c.cidrs[vSwitchID] = cidr
+	c.lock.Unlock()
+	return cidr, nil
+}
+
+// hostScopedCIDR return true if the cidr only contains one address
+func hostScopedCIDR(cidr *net.IPNet) bool {
+	if cidr == nil {
+		return false
+	}
+	ones, bits := cidr.Mask.Size()
+	return ones == bits
+}
+
+// podCIDRForENIIP return the pod cidr of the eniip, the host scoped vswitch cidr reported
+// by metadata is replaced with the real vswitch cidr
+func (n *networkService) podCIDRForENIIP(ctx context.Context, eniIP *types.ENIIP) *types.IPNetSet {
+	cidr := eniIP.PodCIDR()
+	if !hostScopedCIDR(cidr.IPv4) && !hostScopedCIDR(cidr.IPv6) {
+		return cidr
+	}
+	if n.vSwitchCIDRs == nil || eniIP.ENI.VSwitchID == "" {
+		return cidr
+	}
+	vswCIDR, err := n.vSwitchCIDRs.Get(ctx, eniIP.ENI.VSwitchID)
+	if err != nil {
+		serviceLog.Warnf("error get cidr of vswitch %s, keep the host scoped cidr %s: %v", eniIP.ENI.VSwitchID, cidr, err)
+		return cidr
+	}
+	fixed := *cidr
+	if hostScopedCIDR(cidr.IPv4) && vswCIDR.IPv4 != nil {
+		fixed.IPv4 = vswCIDR.IPv4
+	}
+	if hostScopedCIDR(cidr.IPv6) && vswCIDR.IPv6 != nil {
+		fixed.IPv6 = vswCIDR.IPv6
+	}
+	serviceLog.Infof("replace host scoped cidr %s of eni %s with vswitch %s cidr %s", cidr, eniIP.ENI.ID, eniIP.ENI.VSwitchID, &fixed)
+	return &fixed
+}
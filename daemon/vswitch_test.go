@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/ipam"
+	"github.com/AliyunContainerService/terway/types"
+	"github.com/AliyunContainerService/terway/types/daemon"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeVSwitchAPI struct {
+	ipam.API
+	calls int
+	err   error
+	// ipv4Only the vswitches without ipv6 cidr
+	ipv4Only []string
+	// zones the zone of vswitches
+	zones map[string]string
+}
+
+func (f *fakeVSwitchAPI) DescribeVSwitchByID(ctx context.Context, vSwitch string) (*vpc.VSwitch, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	for _, v := range f.ipv4Only {
+		if v == vSwitch {
+			return &vpc.VSwitch{VSwitchId: vSwitch, CidrBlock: "192.168.0.0/16", ZoneId: f.zones[vSwitch]}, nil
+		}
+	}
+	return &vpc.VSwitch{VSwitchId: vSwitch, CidrBlock: "192.168.0.0/16", Ipv6CidrBlock: "fd00::/64", ZoneId: f.zones[vSwitch]}, nil
+}
+
+func Test_vSwitchCachedAPI(t *testing.T) {
+	api := &fakeVSwitchAPI{}
+	c := newVSwitchCachedAPI(api, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		vsw, err := c.DescribeVSwitchByID(context.Background(), "vsw-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "192.168.0.0/16", vsw.CidrBlock)
+	}
+	assert.Equal(t, 1, api.calls)
+
+	// expired
+	c.vSwitches["vsw-1"] = cachedVSwitch{vsw: c.vSwitches["vsw-1"].vsw, expireAt: time.Now().Add(-time.Second)}
+	api.err = fmt.Errorf("foo")
+	_, err := c.DescribeVSwitchByID(context.Background(), "vsw-1")
+	assert.Error(t, err)
+	assert.NotContains(t, c.vSwitches, "vsw-1")
+
+	api.err = nil
+	_, err = c.DescribeVSwitchByID(context.Background(), "vsw-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, api.calls)
+
+	assert.Error(t, validateConfig(&daemon.Config{VSwitchCacheTTLSeconds: -1}))
+}
+
+func Test_podCIDRForENIIP(t *testing.T) {
+	api := &fakeVSwitchAPI{}
+	n := &networkService{vSwitchCIDRs: newVSwitchCIDRCache(api)}
+
+	eniIP := &types.ENIIP{ENI: &types.ENI{ID: "eni-1", VSwitchID: "vsw-1"}}
+	eniIP.ENI.VSwitchCIDR.SetIPNet("192.168.0.0/16")
+	assert.Equal(t, "192.168.0.0/16", n.podCIDRForENIIP(context.Background(), eniIP).String())
+	assert.Equal(t, 0, api.calls)
+
+	eniIP.ENI.VSwitchCIDR.SetIPNet("192.168.1.10/32")
+	assert.Equal(t, "192.168.0.0/16", n.podCIDRForENIIP(context.Background(), eniIP).String())
+	assert.Equal(t, "192.168.0.0/16", n.podCIDRForENIIP(context.Background(), eniIP).String())
+	assert.Equal(t, 1, api.calls)
+	// the eni is not modified
+	assert.Equal(t, "192.168.1.10/32", eniIP.ENI.VSwitchCIDR.String())
+}
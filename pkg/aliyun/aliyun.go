@@ -2,8 +2,10 @@ package aliyun
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
@@ -43,6 +45,10 @@ type Impl struct {
 
 	tagFilter map[string]string // eg.      "TagKey": "creator", "TagValue": "terway"
 
+	// deviceIndex cache the device index of eni id, which is only available from openapi and
+	// never changes while the eni attached
+	deviceIndex sync.Map
+
 	*client.OpenAPI
 }
 
@@ -92,7 +98,7 @@ func (e *Impl) AllocateENI(ctx context.Context, vSwitch string, securityGroups [
 	}()
 
 	var innerErr error
-	err = wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.WaitENIStatus), func() (bool, error) {
+	err = wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.WaitENIStatus), func() (bool, error) {
 		innerErr = e.AttachNetworkInterface(ctx, resp.NetworkInterfaceID, instanceID, "")
 		if innerErr != nil {
 			return false, nil
@@ -111,7 +117,7 @@ func (e *Impl) AllocateENI(ctx context.Context, vSwitch string, securityGroups [
 	start := time.Now()
 	// bind status is async api, sleep for first bind status inspect
 	time.Sleep(backoff.Backoff(backoff.WaitENIStatus).Duration)
-	eniStatus, err := e.WaitForNetworkInterface(ctx, resp.NetworkInterfaceID, client.ENIStatusInUse, backoff.Backoff(backoff.WaitENIStatus), false)
+	eniStatus, err := e.WaitForNetworkInterface(ctx, resp.NetworkInterfaceID, client.ENIStatusInUse, backoff.ForCategory(backoff.CategoryRead, backoff.WaitENIStatus), false)
 	metric.OpenAPILatency.WithLabelValues("WaitForNetworkInterfaceBind/"+string(client.ENIStatusInUse), fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
 
 	if err != nil {
@@ -128,12 +134,14 @@ func (e *Impl) AllocateENI(ctx context.Context, vSwitch string, securityGroups [
 				return false, nil
 			}
 			eni.Trunk = eniStatus.Type == client.ENITypeTrunk
+			eni.DeviceIndex = eniStatus.DeviceIndex
 			return true, nil
 		},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error get eni config, %v, %w", innerErr, err)
 	}
+	e.deviceIndex.Store(eni.ID, eni.DeviceIndex)
 
 	return eni, nil
 }
@@ -144,15 +152,22 @@ func (e *Impl) FreeENI(ctx context.Context, eniID, instanceID string) error {
 
 func (e *Impl) destroyInterface(ctx context.Context, eniID, instanceID, trunkENIID string) error {
 	var innerErr error
-	err := wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.ENIRelease),
+	err := wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENIRelease),
 		func() (done bool, err error) {
 			innerErr = e.DetachNetworkInterface(ctx, eniID, instanceID, trunkENIID)
+			if apiErr.ErrAssert(apiErr.ErrENINotBelongToInstance, innerErr) {
+				// not retry, the eni is not ours any more
+				return false, fmt.Errorf("%w: eni %s, %v", apiErr.ErrENIInUseByOther, eniID, innerErr)
+			}
 			if innerErr != nil {
 				return false, nil
 			}
 			return true, nil
 		},
 	)
+	if errors.Is(err, apiErr.ErrENIInUseByOther) {
+		return err
+	}
 	if err != nil {
 		fmtErr := fmt.Sprintf("cannot detach eni,  %+v", innerErr)
 		_ = tracing.RecordNodeEvent(corev1.EventTypeWarning,
@@ -162,7 +177,7 @@ func (e *Impl) destroyInterface(ctx context.Context, eniID, instanceID, trunkENI
 	time.Sleep(backoff.Backoff(backoff.WaitENIStatus).Duration)
 
 	// backoff delete network interface
-	err = wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.ENIOps),
+	err = wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENIOps),
 		func() (done bool, err error) {
 			innerErr = e.DeleteNetworkInterface(context.Background(), eniID)
 			if innerErr != nil {
@@ -212,6 +227,7 @@ func (e *Impl) GetAttachedENIs(ctx context.Context, containsMainENI bool, trunkE
 					continue
 				}
 				e.Trunk = eni.Type == client.ENITypeTrunk
+				e.DeviceIndex = eni.DeviceIndex
 
 				// take to intersect
 				result = append(result, e)
@@ -222,9 +238,43 @@ func (e *Impl) GetAttachedENIs(ctx context.Context, containsMainENI bool, trunkE
 	} else {
 		result = enis
 	}
+	e.fillDeviceIndex(ctx, result)
 	return result, nil
 }
 
+// fillDeviceIndex set the device index of enis from cache, the unknown ones are fetched from openapi.
+// enis keep index 0 if failed
+func (e *Impl) fillDeviceIndex(ctx context.Context, enis []*types.ENI) {
+	var unknown []string
+	for _, eni := range enis {
+		if eni.DeviceIndex != 0 {
+			e.deviceIndex.Store(eni.ID, eni.DeviceIndex)
+			continue
+		}
+		if index, ok := e.deviceIndex.Load(eni.ID); ok {
+			eni.DeviceIndex = index.(int)
+			continue
+		}
+		unknown = append(unknown, eni.ID)
+	}
+	if len(unknown) == 0 {
+		return
+	}
+	eniSet, err := e.DescribeNetworkInterface(ctx, "", unknown, "", "", "", nil)
+	if err != nil {
+		log.Warnf("error get device index of enis %v: %v", unknown, err)
+		return
+	}
+	for _, eni := range eniSet {
+		e.deviceIndex.Store(eni.NetworkInterfaceID, eni.DeviceIndex)
+	}
+	for _, eni := range enis {
+		if index, ok := e.deviceIndex.Load(eni.ID); ok {
+			eni.DeviceIndex = index.(int)
+		}
+	}
+}
+
 func (e *Impl) GetSecondaryENIMACs(ctx context.Context) ([]string, error) {
 	return e.metadata.GetSecondaryENIMACs()
 }
@@ -250,6 +300,138 @@ func (e *Impl) GetENIIPs(ctx context.Context, mac string) ([]net.IP, []net.IP, e
 	return ipv4, ipv6, nil
 }
 
+// GetENIIPv4Prefixes return the ipv4 prefixes delegated to the eni
+func (e *Impl) GetENIIPv4Prefixes(ctx context.Context, mac string) ([]*net.IPNet, error) {
+	e.privateIPMutex.RLock()
+	defer e.privateIPMutex.RUnlock()
+
+	return e.metadata.GetENIPrivateIPv4PrefixesByMAC(mac)
+}
+
+// AssignIPv4PrefixForENI assign count of /28 ipv4 prefix to the eni
+// the api error is returned directly when the request is rejected, so caller can fall back to secondary ip
+func (e *Impl) AssignIPv4PrefixForENI(ctx context.Context, eniID, mac string, count int) (assigned []*net.IPNet, err error) {
+	if eniID == "" || mac == "" || count <= 0 {
+		return nil, fmt.Errorf("args error")
+	}
+	e.privateIPMutex.Lock()
+	defer e.privateIPMutex.Unlock()
+
+	var prefixes []*net.IPNet
+	var innerErr error
+	// the prefixes assigned but not returned to caller are never released, roll them back
+	defer func() {
+		if err == nil || assigned != nil || len(prefixes) == 0 {
+			return
+		}
+		rollBackCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if roleBackErr := e.unAssignIPv4PrefixForENIUnSafe(rollBackCtx, eniID, mac, prefixes); roleBackErr != nil {
+			log.Errorf("error roll back prefix %s of eni %s: %v", prefixes, eniID, roleBackErr)
+		}
+	}()
+
+	idempotentKey := string(uuid.NewUUID())
+	err = wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENIOps), func() (bool, error) {
+		prefixes, innerErr = e.AssignIpv4Prefix(ctx, eniID, count, idempotentKey)
+		if innerErr != nil {
+			if apiErr.ErrStatusCodeAssert(http.StatusBadRequest, innerErr) ||
+				apiErr.ErrStatusCodeAssert(http.StatusForbidden, innerErr) {
+				return false, innerErr
+			}
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		if innerErr != nil && errors.Is(err, innerErr) {
+			return nil, innerErr
+		}
+		return nil, fmt.Errorf("error assign %d prefix for eniID: %v, %w, innerErr %v", count, eniID, err, innerErr)
+	}
+	if len(prefixes) != count {
+		return nil, fmt.Errorf("openAPI return prefix error.Want %d got %d", count, len(prefixes))
+	}
+
+	err = wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.MetaAssignPrivateIP),
+		func() (bool, error) {
+			var remote []*net.IPNet
+			remote, innerErr = e.metadata.GetENIPrivateIPv4PrefixesByMAC(mac)
+			if innerErr != nil {
+				return false, nil
+			}
+			remoteSet := sets.NewString()
+			for _, prefix := range remote {
+				remoteSet.Insert(prefix.String())
+			}
+			for _, prefix := range prefixes {
+				if !remoteSet.Has(prefix.String()) {
+					innerErr = fmt.Errorf("prefix is not present in metadataAPI,expect %s got %s", prefixes, remote)
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+	)
+	if err != nil {
+		return prefixes, fmt.Errorf("%w, metadataAPI %v", err, innerErr)
+	}
+	return prefixes, nil
+}
+
+// UnAssignIPv4PrefixForENI unassign the ipv4 prefixes from the eni, and wait the prefixes disappear in metadata
+func (e *Impl) UnAssignIPv4PrefixForENI(ctx context.Context, eniID, mac string, prefixes []*net.IPNet) error {
+	e.privateIPMutex.Lock()
+	defer e.privateIPMutex.Unlock()
+
+	return e.unAssignIPv4PrefixForENIUnSafe(ctx, eniID, mac, prefixes)
+}
+
+func (e *Impl) unAssignIPv4PrefixForENIUnSafe(ctx context.Context, eniID, mac string, prefixes []*net.IPNet) error {
+	if eniID == "" || mac == "" {
+		return fmt.Errorf("args error")
+	}
+
+	var innerErr error
+	err := wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.ENIOps), func() (bool, error) {
+		innerErr = e.UnAssignIpv4Prefix(ctx, eniID, prefixes)
+		if innerErr != nil {
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		fmtErr := fmt.Sprintf("error unassign prefix %v for eniID: %v, %v, innerErr %v", prefixes, eniID, err, innerErr)
+		_ = tracing.RecordNodeEvent(corev1.EventTypeWarning, tracing.DisposeResourceFailed, fmtErr)
+		return fmt.Errorf("%s", fmtErr)
+	}
+
+	err = wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.MetaUnAssignPrivateIP),
+		func() (bool, error) {
+			var remote []*net.IPNet
+			remote, innerErr = e.metadata.GetENIPrivateIPv4PrefixesByMAC(mac)
+			if innerErr != nil {
+				return false, nil
+			}
+			remoteSet := sets.NewString()
+			for _, prefix := range remote {
+				remoteSet.Insert(prefix.String())
+			}
+			for _, prefix := range prefixes {
+				if remoteSet.Has(prefix.String()) {
+					innerErr = fmt.Errorf("prefix is still present in metadataAPI, unassigned %s got %s", prefixes, remote)
+					return false, nil
+				}
+			}
+			return true, nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("%w, metadataAPI %v", err, innerErr)
+	}
+	return nil
+}
+
 func (e *Impl) AssignNIPsForENI(ctx context.Context, eniID, mac string, count int) ([]net.IP, []net.IP, error) {
 	if eniID == "" || mac == "" || count <= 0 {
 		return nil, nil, fmt.Errorf("args error")
@@ -289,7 +471,7 @@ func (e *Impl) AssignNIPsForENI(ctx context.Context, eniID, mac string, count in
 	if e.ipFamily.IPv4 {
 		var innerErr error
 		idempotentKey := string(uuid.NewUUID())
-		err = wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.ENIOps), func() (bool, error) {
+		err = wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENIOps), func() (bool, error) {
 			ipv4s, innerErr = e.AssignPrivateIPAddress(ctx, eniID, count, idempotentKey)
 			if innerErr != nil {
 				if apiErr.ErrAssert(apiErr.InvalidVSwitchIDIPNotEnough, innerErr) {
@@ -302,11 +484,17 @@ func (e *Impl) AssignNIPsForENI(ctx context.Context, eniID, mac string, count in
 		if err != nil {
 			return nil, nil, wrap(fmt.Errorf("%w, innerErr %v", err, innerErr))
 		}
-		if len(ipv4s) != count {
+		if len(ipv4s) == 0 || len(ipv4s) > count {
 			return nil, nil, wrap(fmt.Errorf("openAPI return IP error.Want %d got %d", count, len(ipv4s)))
 		}
+		if len(ipv4s) < count {
+			// partial success under the pressure of ip range, use the ips granted
+			log.Warnf("only %d of %d ipv4 assigned for eni %s", len(ipv4s), count, eniID)
+			count = len(ipv4s)
+		}
 		wg.Add(1)
-		go func() {
+		// the ipv4s may be trimmed after ipv6 assigned, verify the ones assigned
+		go func(ipv4s []net.IP) {
 			defer wg.Done()
 			v4Err = wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.MetaAssignPrivateIP),
 				func() (bool, error) {
@@ -325,13 +513,13 @@ func (e *Impl) AssignNIPsForENI(ctx context.Context, eniID, mac string, count in
 			if v4Err != nil {
 				v4Err = fmt.Errorf("%w, metadataAPI %v", v4Err, innerErr)
 			}
-		}()
+		}(ipv4s)
 	}
 
 	if e.ipFamily.IPv6 {
 		var innerErr error
 		idempotentKey := string(uuid.NewUUID())
-		err = wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.ENIOps), func() (bool, error) {
+		err = wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENIOps), func() (bool, error) {
 			ipv6s, innerErr = e.AssignIpv6Addresses(ctx, eniID, count, idempotentKey)
 			if innerErr != nil {
 				if apiErr.ErrAssert(apiErr.InvalidVSwitchIDIPNotEnough, innerErr) {
@@ -344,9 +532,20 @@ func (e *Impl) AssignNIPsForENI(ctx context.Context, eniID, mac string, count in
 		if err != nil {
 			return ipv4s, ipv6s, wrap(fmt.Errorf("%w, innerErr %v", err, innerErr))
 		}
-		if len(ipv6s) != count {
+		if len(ipv6s) == 0 || len(ipv6s) > count {
 			return ipv4s, ipv6s, wrap(fmt.Errorf("openAPI return IP error.Want %d got %d", count, len(ipv6s)))
 		}
+		if len(ipv6s) < count {
+			log.Warnf("only %d of %d ipv6 assigned for eni %s", len(ipv6s), count, eniID)
+			if len(ipv4s) > len(ipv6s) {
+				// the ipv4 without ipv6 paired is useless in dual stack
+				innerErr = e.unAssignIPsForENIUnSafe(ctx, eniID, mac, ipv4s[len(ipv6s):], nil)
+				if innerErr != nil {
+					return ipv4s, ipv6s, wrap(fmt.Errorf("error release ipv4 not paired with ipv6, %w", innerErr))
+				}
+				ipv4s = ipv4s[:len(ipv6s)]
+			}
+		}
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -376,6 +575,69 @@ func (e *Impl) AssignNIPsForENI(ctx context.Context, eniID, mac string, count in
 	return ipv4s, ipv6s, err
 }
 
+// AssignIPv6ForENI assign count of ipv6 to the eni, for pairing with the ipv4 whose ipv6 was released.
+// the ipv6 assigned is rolled back on error
+func (e *Impl) AssignIPv6ForENI(ctx context.Context, eniID, mac string, count int) ([]net.IP, error) {
+	if eniID == "" || mac == "" || count <= 0 {
+		return nil, fmt.Errorf("args error")
+	}
+	e.privateIPMutex.Lock()
+	defer e.privateIPMutex.Unlock()
+
+	var ipv6s []net.IP
+	var err, innerErr error
+	defer func() {
+		if err == nil || len(ipv6s) == 0 {
+			return
+		}
+		rollBackCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if roleBackErr := e.unAssignIPsForENIUnSafe(rollBackCtx, eniID, mac, nil, ipv6s); roleBackErr != nil {
+			log.Errorf("error roll back ipv6 %s of eni %s: %v", ipv6s, eniID, roleBackErr)
+		}
+	}()
+
+	idempotentKey := string(uuid.NewUUID())
+	err = wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENIOps), func() (bool, error) {
+		ipv6s, innerErr = e.AssignIpv6Addresses(ctx, eniID, count, idempotentKey)
+		if innerErr != nil {
+			if apiErr.ErrAssert(apiErr.InvalidVSwitchIDIPNotEnough, innerErr) {
+				return false, innerErr
+			}
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		err = fmt.Errorf("error assign %d ipv6 for eniID: %v, %w, innerErr %v", count, eniID, err, innerErr)
+		return nil, err
+	}
+	if len(ipv6s) != count {
+		err = fmt.Errorf("openAPI return IP error.Want %d got %d", count, len(ipv6s))
+		return nil, err
+	}
+
+	err = wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.MetaAssignPrivateIP),
+		func() (bool, error) {
+			var remoteIPs []net.IP
+			remoteIPs, innerErr = e.metadata.GetENIPrivateIPv6AddressesByMAC(mac)
+			if innerErr != nil {
+				return false, nil
+			}
+			if !ip.IPsIntersect(remoteIPs, ipv6s) {
+				innerErr = fmt.Errorf("ip is not present in metadataAPI,expect %s got %s", ipv6s, remoteIPs)
+				return false, nil
+			}
+			return true, nil
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf("%w, metadataAPI %v", err, innerErr)
+		return nil, err
+	}
+	return ipv6s, nil
+}
+
 func (e *Impl) UnAssignIPsForENI(ctx context.Context, eniID, mac string, ipv4s []net.IP, ipv6s []net.IP) error {
 	e.privateIPMutex.Lock()
 	defer e.privateIPMutex.Unlock()
@@ -393,7 +655,7 @@ func (e *Impl) unAssignIPsForENIUnSafe(ctx context.Context, eniID, mac string, i
 	if len(ipv4s) > 0 {
 		var innerErr error
 
-		err := wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.ENIOps), func() (bool, error) {
+		err := wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENIOps), func() (bool, error) {
 			innerErr = e.UnAssignPrivateIPAddresses(ctx, eniID, ipv4s)
 			if innerErr != nil {
 				return false, nil
@@ -408,7 +670,7 @@ func (e *Impl) unAssignIPsForENIUnSafe(ctx context.Context, eniID, mac string, i
 	if len(ipv6s) > 0 {
 		var innerErr error
 
-		err := wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.ENIOps), func() (bool, error) {
+		err := wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENIOps), func() (bool, error) {
 			innerErr = e.UnAssignIpv6Addresses(ctx, eniID, ipv6s)
 			if innerErr != nil {
 				return false, nil
@@ -461,7 +723,7 @@ func (e *Impl) unAssignIPsForENIUnSafe(ctx context.Context, eniID, mac string, i
 			return true, nil
 		},
 	)
-	metric.OpenAPILatency.WithLabelValues("UnassignPrivateIpAddressesAsync", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("UnassignPrivateIpAddressesAsync", err != nil, start)
 	if err != nil {
 		fmtErr := fmt.Sprintf("error unassign eni private address for %s, %v", eniID, innerErr)
 		_ = tracing.RecordNodeEvent(corev1.EventTypeWarning,
@@ -488,7 +750,7 @@ func (e *Impl) GetAttachedSecurityGroups(ctx context.Context, instanceID string)
 		req := ecs.CreateDescribeInstanceAttributeRequest()
 		req.InstanceId = instanceID
 		resp, err := e.ClientSet.ECS().DescribeInstanceAttribute(req)
-		metric.OpenAPILatency.WithLabelValues("DescribeInstanceAttribute", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("DescribeInstanceAttribute", err != nil, start)
 		if err != nil {
 			return nil, fmt.Errorf("error describe instance attribute for security group: %s,%w", instanceID, err)
 		}
@@ -508,7 +770,7 @@ func (e *Impl) GetInstanceAttributesType(ctx context.Context, instanceID string)
 
 	start := time.Now()
 	resp, err := e.ClientSet.ECS().DescribeInstances(req)
-	metric.OpenAPILatency.WithLabelValues("DescribeInstances", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("DescribeInstances", err != nil, start)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,66 @@
+package aliyun
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AliyunContainerService/terway/pkg/aliyun/client"
+	apiErr "github.com/AliyunContainerService/terway/pkg/aliyun/client/errors"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/resourcemanager"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusTransport reply every request with the status code and count the requests
+type statusTransport struct {
+	code  int
+	calls int32
+}
+
+func (s *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return &http.Response{
+		StatusCode: s.code,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"Code": "InvalidOperation.Ipv4PrefixNotSupported", "RequestId": "req"}`)),
+		Request:    req,
+	}, nil
+}
+
+type fakeClientSet struct {
+	ecs *ecs.Client
+}
+
+func (f *fakeClientSet) ECS() *ecs.Client                         { return f.ecs }
+func (f *fakeClientSet) VPC() *vpc.Client                         { return nil }
+func (f *fakeClientSet) ResourceManager() *resourcemanager.Client { return nil }
+
+func newStatusImpl(t *testing.T, code int) (*Impl, *statusTransport) {
+	ecsClient, err := ecs.NewClientWithAccessKey("cn-hangzhou", "ak", "sk")
+	assert.NoError(t, err)
+	ecsClient.Domain = "ecs.example.com"
+	transport := &statusTransport{code: code}
+	ecsClient.SetTransport(transport)
+	return &Impl{OpenAPI: &client.OpenAPI{ClientSet: &fakeClientSet{ecs: ecsClient}}}, transport
+}
+
+func TestImpl_AssignIPv4PrefixForENI_rejected(t *testing.T) {
+	for _, code := range []int{http.StatusBadRequest, http.StatusForbidden} {
+		impl, transport := newStatusImpl(t, code)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+
+		prefixes, err := impl.AssignIPv4PrefixForENI(ctx, "eni-1", "00:00:00:00:00:01", 1)
+		cancel()
+		assert.Nil(t, prefixes)
+		// the api error is passed through without wrapping, so the caller can fall back to secondary ip
+		assert.True(t, apiErr.ErrStatusCodeAssert(code, err), "status %d, err %v", code, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&transport.calls), "status %d should not be retried", code)
+	}
+}
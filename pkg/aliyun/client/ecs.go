@@ -30,6 +30,13 @@ type OpenAPI struct {
 
 	ReadOnlyRateLimiter flowcontrol.RateLimiter
 	MutatingRateLimiter flowcontrol.RateLimiter
+
+	// ENINamePrefix ENIDescription override the name and description of eni created, empty for default
+	ENINamePrefix  string
+	ENIDescription string
+
+	// ResourceGroupID resource group of eni created when not specified by caller, empty for default
+	ResourceGroupID string
 }
 
 func New(c credential.Client, readOnly, mutating flowcontrol.RateLimiter) (*OpenAPI, error) {
@@ -65,9 +72,15 @@ func (a *OpenAPI) CreateNetworkInterface(ctx context.Context, trunk bool, vSwitc
 		req.InstanceType = ENITypeTrunk
 	}
 	req.SecurityGroupIds = &securityGroups
-	req.NetworkInterfaceName = generateEniName()
+	req.NetworkInterfaceName = generateEniName(a.ENINamePrefix)
+	if resourceGroupID == "" {
+		resourceGroupID = a.ResourceGroupID
+	}
 	req.ResourceGroupId = resourceGroupID
 	req.Description = eniDescription
+	if a.ENIDescription != "" {
+		req.Description = a.ENIDescription
+	}
 	if ipCount > 1 {
 		req.SecondaryPrivateIpAddressCount = requests.NewInteger(ipCount - 1)
 	}
@@ -94,11 +107,11 @@ func (a *OpenAPI) CreateNetworkInterface(ctx context.Context, trunk bool, vSwitc
 		innerErr error
 		resp     *ecs.CreateNetworkInterfaceResponse
 	)
-	err := wait.ExponentialBackoffWithContext(ctx, backoff.Backoff(backoff.ENICreate), func() (bool, error) {
+	err := wait.ExponentialBackoffWithContext(ctx, backoff.ForCategory(backoff.CategoryWrite, backoff.ENICreate), func() (bool, error) {
 		a.MutatingRateLimiter.Accept()
 		start := time.Now()
 		resp, innerErr = a.ClientSet.ECS().CreateNetworkInterface(req)
-		metric.OpenAPILatency.WithLabelValues("CreateNetworkInterface", fmt.Sprint(innerErr != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("CreateNetworkInterface", innerErr != nil, start)
 		if innerErr != nil {
 			if apiErr.ErrAssert(apiErr.InvalidVSwitchIDIPNotEnough, innerErr) {
 				return false, innerErr
@@ -157,7 +170,7 @@ func (a *OpenAPI) DescribeNetworkInterface(ctx context.Context, vpcID string, en
 		a.ReadOnlyRateLimiter.Accept()
 		start := time.Now()
 		resp, err := a.ClientSet.ECS().DescribeNetworkInterfaces(req)
-		metric.OpenAPILatency.WithLabelValues("DescribeNetworkInterfaces", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("DescribeNetworkInterfaces", err != nil, start)
 		if err != nil {
 			l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Warn(err)
 			return nil, err
@@ -189,7 +202,7 @@ func (a *OpenAPI) AttachNetworkInterface(ctx context.Context, eniID, instanceID,
 	a.MutatingRateLimiter.Accept()
 	start := time.Now()
 	resp, err := a.ClientSet.ECS().AttachNetworkInterface(req)
-	metric.OpenAPILatency.WithLabelValues("AttachNetworkInterface", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("AttachNetworkInterface", err != nil, start)
 	if err != nil {
 		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Warnf("attach ENI failed, %s", err.Error())
 		return err
@@ -213,7 +226,7 @@ func (a *OpenAPI) DetachNetworkInterface(ctx context.Context, eniID, instanceID,
 	a.MutatingRateLimiter.Accept()
 	start := time.Now()
 	resp, err := a.ClientSet.ECS().DetachNetworkInterface(req)
-	metric.OpenAPILatency.WithLabelValues("DetachNetworkInterface", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("DetachNetworkInterface", err != nil, start)
 	if err != nil {
 		if apiErr.ErrAssert(apiErr.ErrInvalidENINotFound, err) {
 			return nil
@@ -237,7 +250,7 @@ func (a *OpenAPI) DeleteNetworkInterface(ctx context.Context, eniID string) erro
 	a.MutatingRateLimiter.Accept()
 	start := time.Now()
 	resp, err := a.ClientSet.ECS().DeleteNetworkInterface(req)
-	metric.OpenAPILatency.WithLabelValues("DeleteNetworkInterface", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("DeleteNetworkInterface", err != nil, start)
 	if err != nil {
 		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Errorf("delete eni failed, %v", err)
 		return err
@@ -292,7 +305,7 @@ func (a *OpenAPI) AssignPrivateIPAddress(ctx context.Context, eniID string, coun
 	})
 	start := time.Now()
 	resp, err := a.ClientSet.ECS().AssignPrivateIpAddresses(req)
-	metric.OpenAPILatency.WithLabelValues("AssignPrivateIpAddresses", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("AssignPrivateIpAddresses", err != nil, start)
 	if err != nil {
 		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Warnf("assign private ip failed, %s", err.Error())
 		return nil, err
@@ -307,6 +320,68 @@ func (a *OpenAPI) AssignPrivateIPAddress(ctx context.Context, eniID string, coun
 	return ips, nil
 }
 
+// AssignIpv4Prefix assign /28 ipv4 prefix
+func (a *OpenAPI) AssignIpv4Prefix(ctx context.Context, eniID string, count int, idempotentKey string) ([]*net.IPNet, error) {
+	req := ecs.CreateAssignPrivateIpAddressesRequest()
+	req.NetworkInterfaceId = eniID
+	req.Ipv4PrefixCount = requests.NewInteger(count)
+	req.ClientToken = idempotentKey
+
+	l := log.WithFields(map[string]interface{}{
+		LogFieldAPI:         "AssignPrivateIpAddresses",
+		LogFieldENIID:       eniID,
+		LogFieldPrefixCount: count,
+	})
+	start := time.Now()
+	resp, err := a.ClientSet.ECS().AssignPrivateIpAddresses(req)
+	metric.ObserveOpenAPI("AssignPrivateIpAddresses", err != nil, start)
+	if err != nil {
+		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Warnf("assign ipv4 prefix failed, %s", err.Error())
+		return nil, err
+	}
+	prefixes, err := ip.ToIPNets(resp.AssignedPrivateIpAddressesSet.Ipv4PrefixSet.Ipv4Prefixes)
+	if err != nil {
+		l.WithField(LogFieldRequestID, resp.RequestId).Errorf("assign ipv4 prefix, %v", resp.AssignedPrivateIpAddressesSet.Ipv4PrefixSet.Ipv4Prefixes)
+		return nil, err
+	}
+	l.WithField(LogFieldRequestID, resp.RequestId).Infof("assign ipv4 prefix, %v", resp.AssignedPrivateIpAddressesSet.Ipv4PrefixSet.Ipv4Prefixes)
+
+	return prefixes, nil
+}
+
+// UnAssignIpv4Prefix remove /28 ipv4 prefix from eni
+// return ok if 1. eni is released 2. prefix is already released 3. release success
+func (a *OpenAPI) UnAssignIpv4Prefix(ctx context.Context, eniID string, prefixes []*net.IPNet) error {
+	if len(prefixes) == 0 {
+		return nil
+	}
+	var strs []string
+	for _, prefix := range prefixes {
+		strs = append(strs, prefix.String())
+	}
+	req := ecs.CreateUnassignPrivateIpAddressesRequest()
+	req.NetworkInterfaceId = eniID
+	req.Ipv4Prefix = &strs
+
+	l := log.WithFields(map[string]interface{}{
+		LogFieldAPI:   "UnassignPrivateIpAddresses",
+		LogFieldENIID: eniID,
+	})
+	start := time.Now()
+	resp, err := a.ClientSet.ECS().UnassignPrivateIpAddresses(req)
+	metric.OpenAPILatency.WithLabelValues("UnassignPrivateIpAddresses", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	if err != nil {
+		if apiErr.ErrAssert(apiErr.ErrInvalidIPIPUnassigned, err) || apiErr.ErrAssert(apiErr.ErrInvalidENINotFound, err) {
+			l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Infof("unassign ipv4 prefix, %v", strs)
+			return nil
+		}
+		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Warnf("unassign ipv4 prefix failed, %v %s", strs, err.Error())
+		return err
+	}
+	l.WithField(LogFieldRequestID, resp.RequestId).Infof("unassign ipv4 prefix, %v", strs)
+	return nil
+}
+
 // UnAssignPrivateIPAddresses remove ip from eni
 // return ok if 1. eni is released 2. ip is already released 3. release success
 // for primaryIP err is InvalidIp.IpUnassigned
@@ -325,7 +400,7 @@ func (a *OpenAPI) UnAssignPrivateIPAddresses(ctx context.Context, eniID string,
 	})
 	start := time.Now()
 	resp, err := a.ClientSet.ECS().UnassignPrivateIpAddresses(req)
-	metric.OpenAPILatency.WithLabelValues("UnassignPrivateIpAddresses", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("UnassignPrivateIpAddresses", err != nil, start)
 
 	if err != nil {
 		if apiErr.ErrAssert(apiErr.ErrInvalidIPIPUnassigned, err) {
@@ -357,7 +432,7 @@ func (a *OpenAPI) AssignIpv6Addresses(ctx context.Context, eniID string, count i
 	})
 	start := time.Now()
 	resp, err := a.ClientSet.ECS().AssignIpv6Addresses(req)
-	metric.OpenAPILatency.WithLabelValues("AssignIpv6Addresses", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("AssignIpv6Addresses", err != nil, start)
 	if err != nil {
 		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Warnf("assign private ip failed, %s", err.Error())
 		return nil, err
@@ -389,7 +464,7 @@ func (a *OpenAPI) UnAssignIpv6Addresses(ctx context.Context, eniID string, ips [
 	})
 	start := time.Now()
 	resp, err := a.ClientSet.ECS().UnassignIpv6Addresses(req)
-	metric.OpenAPILatency.WithLabelValues("UnassignIpv6Addresses", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("UnassignIpv6Addresses", err != nil, start)
 
 	if err != nil {
 		if apiErr.ErrAssert(apiErr.ErrInvalidIPIPUnassigned, err) {
@@ -421,7 +496,7 @@ func (a *OpenAPI) DescribeInstanceTypes(ctx context.Context, types []string) ([]
 		}
 		start := time.Now()
 		resp, err := a.ClientSet.ECS().DescribeInstanceTypes(req)
-		metric.OpenAPILatency.WithLabelValues("DescribeInstanceTypes", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("DescribeInstanceTypes", err != nil, start)
 
 		l := log.WithFields(map[string]interface{}{
 			LogFieldAPI: "DescribeInstanceTypes",
@@ -450,7 +525,7 @@ func (a *OpenAPI) ModifyNetworkInterfaceAttribute(ctx context.Context, eniID str
 	req.SecurityGroupId = &securityGroupIDs
 	start := time.Now()
 	resp, err := a.ClientSet.ECS().ModifyNetworkInterfaceAttribute(req)
-	metric.OpenAPILatency.WithLabelValues("ModifyNetworkInterfaceAttribute", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("ModifyNetworkInterfaceAttribute", err != nil, start)
 
 	l := log.WithFields(map[string]interface{}{
 		LogFieldAPI: "ModifyNetworkInterfaceAttribute",
@@ -462,3 +537,53 @@ func (a *OpenAPI) ModifyNetworkInterfaceAttribute(ctx context.Context, eniID str
 	l.WithField(LogFieldRequestID, resp.RequestId).Infof("modify securityGroup %s", securityGroupIDs)
 	return nil
 }
+
+// TagNetworkInterface add the tags to eni, the tags of same keys are overwritten
+func (a *OpenAPI) TagNetworkInterface(ctx context.Context, eniID string, tags map[string]string) error {
+	req := ecs.CreateTagResourcesRequest()
+	req.ResourceType = "eni"
+	req.ResourceId = &[]string{eniID}
+	var ecsTags []ecs.TagResourcesTag
+	for k, v := range tags {
+		ecsTags = append(ecsTags, ecs.TagResourcesTag{Key: k, Value: v})
+	}
+	req.Tag = &ecsTags
+
+	l := log.WithFields(map[string]interface{}{
+		LogFieldAPI:   "TagResources",
+		LogFieldENIID: eniID,
+	})
+	a.MutatingRateLimiter.Accept()
+	start := time.Now()
+	resp, err := a.ClientSet.ECS().TagResources(req)
+	metric.ObserveOpenAPI("TagResources", err != nil, start)
+	if err != nil {
+		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Errorf("tag eni failed, %v", err)
+		return err
+	}
+	l.WithField(LogFieldRequestID, resp.RequestId).Infof("tag eni %v", tags)
+	return nil
+}
+
+// UntagNetworkInterface remove the tags of keys from eni
+func (a *OpenAPI) UntagNetworkInterface(ctx context.Context, eniID string, keys []string) error {
+	req := ecs.CreateUntagResourcesRequest()
+	req.ResourceType = "eni"
+	req.ResourceId = &[]string{eniID}
+	req.TagKey = &keys
+
+	l := log.WithFields(map[string]interface{}{
+		LogFieldAPI:   "UntagResources",
+		LogFieldENIID: eniID,
+	})
+	a.MutatingRateLimiter.Accept()
+	start := time.Now()
+	resp, err := a.ClientSet.ECS().UntagResources(req)
+	metric.ObserveOpenAPI("UntagResources", err != nil, start)
+	if err != nil {
+		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Errorf("untag eni failed, %v", err)
+		return err
+	}
+	l.WithField(LogFieldRequestID, resp.RequestId).Infof("untag eni %v", keys)
+	return nil
+}
@@ -42,11 +42,17 @@ const (
 
 	// ErrThrottling .
 	ErrThrottling = "Throttling"
+
+	// ErrENINotBelongToInstance the eni is attached to other instance
+	// for API DetachNetworkInterface
+	ErrENINotBelongToInstance = "InvalidOperation.EniNotBelongToInstance"
 )
 
 // define well known err
 var (
 	ErrNotFound = errors.New("not found")
+	// ErrENIInUseByOther the eni is attached to other instance, e.g. grabbed by the new node on node replacement
+	ErrENIInUseByOther = errors.New("eni is in use by other instance")
 )
 
 // ErrAssert check err is match errCode
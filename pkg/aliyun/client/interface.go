@@ -16,4 +16,5 @@ type EIP interface {
 	UnAssociateEIPAddress(eipID, eniID, eniIP string) error
 	ReleaseEIPAddress(eipID string) error
 	AddCommonBandwidthPackageIP(eipID, packageID string) error
+	DescribeCommonBandwidthPackage(packageID string) (*vpc.CommonBandwidthPackage, error)
 }
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"fmt"
+	"time"
+
+	apiErr "github.com/AliyunContainerService/terway/pkg/aliyun/client/errors"
+	"github.com/AliyunContainerService/terway/pkg/metric"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/resourcemanager"
+)
+
+// GetResourceGroup check the resource group exist
+func (a *OpenAPI) GetResourceGroup(resourceGroupID string) error {
+	a.ReadOnlyRateLimiter.Accept()
+
+	req := resourcemanager.CreateGetResourceGroupRequest()
+	req.ResourceGroupId = resourceGroupID
+
+	l := log.WithFields(map[string]interface{}{
+		LogFieldAPI:             "GetResourceGroup",
+		LogFieldResourceGroupID: resourceGroupID,
+	})
+	start := time.Now()
+	resp, err := a.ClientSet.ResourceManager().GetResourceGroup(req)
+	metric.ObserveOpenAPI("GetResourceGroup", err != nil, start)
+	if err != nil {
+		l.WithFields(map[string]interface{}{
+			LogFieldRequestID: apiErr.ErrRequestID(err)}).Errorf("get resource group failed, %s", err.Error())
+		return fmt.Errorf("error get resource group %s, %w", resourceGroupID, err)
+	}
+	if resp.ResourceGroup.Id != resourceGroupID {
+		return fmt.Errorf("resource group %s not found", resourceGroupID)
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ const (
 	LogFieldRequestID        = "requestID"
 	LogFieldInstanceID       = "instanceID"
 	LogFieldSecondaryIPCount = "secondaryIPCount"
+	LogFieldPrefixCount      = "prefixCount"
 	LogFieldENIID            = "eni"
 	LogFieldEIPID            = "eip"
 	LogFieldPrivateIP        = "privateIP"
@@ -29,16 +30,25 @@ const (
 	eniNamePrefix     = "eni-cni-"
 	eniDescription    = "interface create by terway"
 	maxSinglePageSize = 500
+
+	// ENINameMaxLength ENIDescriptionMaxLength length limit of eni name and description in ecs
+	ENINameMaxLength        = 128
+	ENIDescriptionMaxLength = 256
+	// ENINameSuffixLength length of the random suffix append to eni name prefix
+	ENINameSuffixLength = 6
 )
 
-func generateEniName() string {
+func generateEniName(prefix string) string {
+	if prefix == "" {
+		prefix = eniNamePrefix
+	}
 	b := make([]byte, 3)
 	rand.Seed(time.Now().UnixNano())
 	_, err := rand.Read(b)
 	if err != nil {
 		panic(err)
 	}
-	return eniNamePrefix + hex.EncodeToString(b)
+	return prefix + hex.EncodeToString(b)
 }
 
 // status for eni
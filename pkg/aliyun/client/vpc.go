@@ -23,7 +23,7 @@ func (a *OpenAPI) AllocateEIPAddress(bandwidth, chargeType, isp string) (*vpc.Al
 	})
 	start := time.Now()
 	resp, err := a.ClientSet.VPC().AllocateEipAddress(req)
-	metric.OpenAPILatency.WithLabelValues("AllocateEipAddress", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("AllocateEipAddress", err != nil, start)
 	if err != nil {
 		l.WithFields(map[string]interface{}{
 			LogFieldRequestID: apiErr.ErrRequestID(err)}).Errorf("alloc EIP faild, %s", err.Error())
@@ -50,11 +50,11 @@ func (a *OpenAPI) AssociateEIPAddress(eipID, eniID, privateIP string) error {
 	})
 	start := time.Now()
 
-	return retry.OnError(backoff.Backoff(backoff.DefaultKey), func(err error) bool {
+	return retry.OnError(backoff.ForCategory(backoff.CategoryWrite, backoff.DefaultKey), func(err error) bool {
 		return apiErr.ErrAssert(apiErr.ErrTaskConflict, err)
 	}, func() error {
 		resp, err := a.ClientSet.VPC().AssociateEipAddress(req)
-		metric.OpenAPILatency.WithLabelValues("AssociateEipAddress", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("AssociateEipAddress", err != nil, start)
 		if err != nil {
 			l.WithFields(map[string]interface{}{
 				LogFieldRequestID: apiErr.ErrRequestID(err)}).Warnf("associate EIP to %s failed, %s", privateIP, err.Error())
@@ -80,12 +80,12 @@ func (a *OpenAPI) UnAssociateEIPAddress(eipID, eniID, eniIP string) error {
 		LogFieldEIPID: eipID,
 	})
 
-	return retry.OnError(backoff.Backoff(backoff.DefaultKey), func(err error) bool {
+	return retry.OnError(backoff.ForCategory(backoff.CategoryWrite, backoff.DefaultKey), func(err error) bool {
 		return apiErr.ErrAssert(apiErr.ErrTaskConflict, err)
 	}, func() error {
 		start := time.Now()
 		resp, err := a.ClientSet.VPC().UnassociateEipAddress(req)
-		metric.OpenAPILatency.WithLabelValues("UnassociateEipAddress", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("UnassociateEipAddress", err != nil, start)
 		if err != nil {
 			l.WithFields(map[string]interface{}{
 				LogFieldRequestID: apiErr.ErrRequestID(err)}).Warnf("unassociate EIP failed, %s", err.Error())
@@ -111,12 +111,12 @@ func (a *OpenAPI) ReleaseEIPAddress(eipID string) error {
 		LogFieldEIPID: eipID,
 	})
 
-	return retry.OnError(backoff.Backoff(backoff.DefaultKey), func(err error) bool {
+	return retry.OnError(backoff.ForCategory(backoff.CategoryWrite, backoff.DefaultKey), func(err error) bool {
 		return apiErr.ErrAssert(apiErr.ErrTaskConflict, err)
 	}, func() error {
 		start := time.Now()
 		resp, err := a.ClientSet.VPC().ReleaseEipAddress(req)
-		metric.OpenAPILatency.WithLabelValues("ReleaseEipAddress", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("ReleaseEipAddress", err != nil, start)
 		if err != nil {
 			l.WithFields(map[string]interface{}{
 				LogFieldRequestID: apiErr.ErrRequestID(err)}).Warnf("release EIP failed, %s", err.Error())
@@ -138,12 +138,12 @@ func (a *OpenAPI) AddCommonBandwidthPackageIP(eipID, packageID string) error {
 		LogFieldAPI:   "AddCommonBandwidthPackageIp",
 		LogFieldEIPID: eipID,
 	})
-	return retry.OnError(backoff.Backoff(backoff.DefaultKey), func(err error) bool {
+	return retry.OnError(backoff.ForCategory(backoff.CategoryWrite, backoff.DefaultKey), func(err error) bool {
 		return apiErr.ErrAssert(apiErr.ErrTaskConflict, err)
 	}, func() error {
 		start := time.Now()
 		resp, err := a.ClientSet.VPC().AddCommonBandwidthPackageIp(req)
-		metric.OpenAPILatency.WithLabelValues("AddCommonBandwidthPackageIp", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("AddCommonBandwidthPackageIp", err != nil, start)
 		if err != nil {
 			l.WithFields(map[string]interface{}{
 				LogFieldRequestID: apiErr.ErrRequestID(err)}).Warnf("add eip failed, %s", err.Error())
@@ -155,6 +155,28 @@ func (a *OpenAPI) AddCommonBandwidthPackageIP(eipID, packageID string) error {
 	})
 }
 
+// DescribeCommonBandwidthPackage get the bandwidth package by id
+func (a *OpenAPI) DescribeCommonBandwidthPackage(packageID string) (*vpc.CommonBandwidthPackage, error) {
+	req := vpc.CreateDescribeCommonBandwidthPackagesRequest()
+	req.BandwidthPackageId = packageID
+
+	l := log.WithFields(map[string]interface{}{
+		LogFieldAPI: "DescribeCommonBandwidthPackages",
+	})
+	start := time.Now()
+	resp, err := a.ClientSet.VPC().DescribeCommonBandwidthPackages(req)
+	metric.ObserveOpenAPI("DescribeCommonBandwidthPackages", err != nil, start)
+	if err != nil {
+		l.WithFields(map[string]interface{}{
+			LogFieldRequestID: apiErr.ErrRequestID(err)}).Warnf("describe bandwidth package failed, %s", err.Error())
+		return nil, err
+	}
+	if len(resp.CommonBandwidthPackages.CommonBandwidthPackage) == 0 {
+		return nil, fmt.Errorf("bandwidth package %s not found", packageID)
+	}
+	return &resp.CommonBandwidthPackages.CommonBandwidthPackage[0], nil
+}
+
 // RemoveCommonBandwidthPackageIP remove EIP from bandwidth package
 func (a *OpenAPI) RemoveCommonBandwidthPackageIP(eipID, packageID string) error {
 	req := vpc.CreateRemoveCommonBandwidthPackageIpRequest()
@@ -166,12 +188,12 @@ func (a *OpenAPI) RemoveCommonBandwidthPackageIP(eipID, packageID string) error
 		LogFieldEIPID: eipID,
 	})
 
-	return retry.OnError(backoff.Backoff(backoff.DefaultKey), func(err error) bool {
+	return retry.OnError(backoff.ForCategory(backoff.CategoryWrite, backoff.DefaultKey), func(err error) bool {
 		return apiErr.ErrAssert(apiErr.ErrTaskConflict, err)
 	}, func() error {
 		start := time.Now()
 		resp, err := a.ClientSet.VPC().RemoveCommonBandwidthPackageIp(req)
-		metric.OpenAPILatency.WithLabelValues("RemoveCommonBandwidthPackageIp", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+		metric.ObserveOpenAPI("RemoveCommonBandwidthPackageIp", err != nil, start)
 		if err != nil {
 			l.WithFields(map[string]interface{}{
 				LogFieldRequestID: apiErr.ErrRequestID(err)}).Warnf("remove eip failed, %s", err.Error())
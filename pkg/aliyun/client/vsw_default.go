@@ -4,7 +4,6 @@ package client
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	apiErr "github.com/AliyunContainerService/terway/pkg/aliyun/client/errors"
@@ -24,7 +23,7 @@ func (a *OpenAPI) DescribeVSwitchByID(ctx context.Context, vSwitchID string) (*v
 
 	start := time.Now()
 	resp, err := a.ClientSet.VPC().DescribeVSwitches(req)
-	metric.OpenAPILatency.WithLabelValues("DescribeVSwitches", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("DescribeVSwitches", err != nil, start)
 	if err != nil {
 		l.WithField(LogFieldRequestID, apiErr.ErrRequestID(err)).Error(err)
 		return nil, err
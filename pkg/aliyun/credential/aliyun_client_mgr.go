@@ -16,12 +16,14 @@ import (
 
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/ecs"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/resourcemanager"
 	"github.com/aliyun/alibaba-cloud-sdk-go/services/vpc"
 )
 
 type Client interface {
 	ECS() *ecs.Client
 	VPC() *vpc.Client
+	ResourceManager() *resourcemanager.Client
 }
 
 var (
@@ -69,6 +71,7 @@ type ClientMgr struct {
 
 	ecs *ecs.Client
 	vpc *vpc.Client
+	rm  *resourcemanager.Client
 
 	ecsDomainOverride string
 	vpcDomainOverride string
@@ -140,6 +143,19 @@ func (c *ClientMgr) ECS() *ecs.Client {
 	return c.ecs
 }
 
+func (c *ClientMgr) ResourceManager() *resourcemanager.Client {
+	c.Lock()
+	defer c.Unlock()
+	ok, err := c.refreshToken()
+	if err != nil {
+		mgrLog.Error(err)
+	}
+	if ok {
+		mgrLog.WithFields(map[string]interface{}{"updateAt": c.updateAt, "expireAt": c.expireAt}).Infof("credential update")
+	}
+	return c.rm
+}
+
 func (c *ClientMgr) refreshToken() (bool, error) {
 	if c.updateAt.IsZero() || c.expireAt.Before(time.Now()) || time.Since(c.updateAt) > tokenReSyncPeriod {
 		var err error
@@ -174,6 +190,11 @@ func (c *ClientMgr) refreshToken() (bool, error) {
 			c.vpc.Domain = c.vpcDomainOverride
 		}
 
+		c.rm, err = resourcemanager.NewClientWithOptions(c.regionID, clientCfg(), cc.Credential)
+		if err != nil {
+			return false, err
+		}
+
 		c.expireAt = cc.Expiration
 		return true, nil
 	}
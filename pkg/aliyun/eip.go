@@ -26,7 +26,7 @@ func (e *Impl) AllocateEipAddress(ctx context.Context, bandwidth int, chargeType
 		err     error
 	)
 	var eni *client.NetworkInterface
-	eni, err = e.WaitForNetworkInterface(ctx, eniID, "", backoff.Backoff(backoff.ENIOps), false)
+	eni, err = e.WaitForNetworkInterface(ctx, eniID, "", backoff.ForCategory(backoff.CategoryRead, backoff.ENIOps), false)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +138,7 @@ func (e *Impl) AllocateEipAddress(ctx context.Context, bandwidth int, chargeType
 			time.Sleep(3 * time.Second)
 
 			start := time.Now()
-			_, err = e.WaitForEIP(eipID, eipStatusAvailable, backoff.Backoff(backoff.WaitENIStatus))
+			_, err = e.WaitForEIP(ctx, eipID, eipStatusAvailable, backoff.ForCategory(backoff.CategoryRead, backoff.WaitENIStatus))
 			metric.OpenAPILatency.WithLabelValues("UnassociateEipAddress/Async", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
 			if err != nil {
 				return nil, fmt.Errorf("error wait for eip to status Available: %v", err)
@@ -151,20 +151,27 @@ func (e *Impl) AllocateEipAddress(ctx context.Context, bandwidth int, chargeType
 	logrus.Debugf("get eip info: %+v", eipInfo)
 
 	// bind eip to eni/secondary address
+	// the association is async, caller should use WaitEipAssociated to wait it done
 	err = e.AssociateEIPAddress(eipInfo.ID, eniID, eniIP.String())
 	if err != nil {
 		err = fmt.Errorf("error associate eip:%v to eni:%v.%v, err: %v", eipInfo, eniID, eniIP, err)
 		return nil, err
 	}
-	time.Sleep(3 * time.Second)
+	return eipInfo, nil
+}
 
+// WaitEipAssociated wait eip status to InUse and bind to the eni, return error when ctx is done or backoff exceeded
+func (e *Impl) WaitEipAssociated(ctx context.Context, eipID, eniID string) error {
 	start := time.Now()
-	_, err = e.WaitForEIP(eipInfo.ID, "InUse", backoff.Backoff(backoff.WaitENIStatus))
+	eip, err := e.WaitForEIP(ctx, eipID, eipStatusInUse, backoff.ForCategory(backoff.CategoryRead, backoff.WaitEIPStatus))
 	metric.OpenAPILatency.WithLabelValues("AssociateEipAddress/Async", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
 	if err != nil {
-		return nil, fmt.Errorf("wait for eip error: %v", err)
+		return err
 	}
-	return eipInfo, nil
+	if eip.InstanceId != eniID {
+		return fmt.Errorf("eip %s is associated to %s, expect %s", eipID, eip.InstanceId, eniID)
+	}
+	return nil
 }
 
 // UnassociateEipAddress un associate eip
@@ -173,7 +180,7 @@ func (e *Impl) AllocateEipAddress(ctx context.Context, bandwidth int, chargeType
 // 3. if eip is not bind ,return code is IncorrectEipStatus
 func (e *Impl) UnassociateEipAddress(ctx context.Context, eipID, eniID, eniIP string) error {
 	var innerErr error
-	err := wait.ExponentialBackoff(backoff.Backoff(backoff.ENIOps),
+	err := wait.ExponentialBackoff(backoff.ForCategory(backoff.CategoryWrite, backoff.ENIOps),
 		func() (done bool, err error) {
 			// we check eip binding is not changed
 			var eips []vpc.EipAddress
@@ -210,7 +217,7 @@ func (e *Impl) UnassociateEipAddress(ctx context.Context, eipID, eniID, eniIP st
 }
 
 func (e *Impl) ReleaseEipAddress(ctx context.Context, eipID, eniID string, eniIP net.IP) error {
-	eip, err := e.WaitForEIP(eipID, "", backoff.Backoff(backoff.WaitENIStatus))
+	eip, err := e.WaitForEIP(ctx, eipID, "", backoff.ForCategory(backoff.CategoryRead, backoff.WaitENIStatus))
 	if err != nil {
 		return fmt.Errorf("error release eip: %w", err)
 	}
@@ -225,7 +232,7 @@ func (e *Impl) ReleaseEipAddress(ctx context.Context, eipID, eniID string, eniIP
 		if err == nil {
 			time.Sleep(3 * time.Second)
 			start := time.Now()
-			eip, err = e.WaitForEIP(eipID, eipStatusAvailable, backoff.Backoff(backoff.WaitENIStatus))
+			eip, err = e.WaitForEIP(ctx, eipID, eipStatusAvailable, backoff.ForCategory(backoff.CategoryRead, backoff.WaitENIStatus))
 			metric.OpenAPILatency.WithLabelValues("UnassociateEipAddress/Async", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
 			if err != nil {
 				logrus.Errorf("wait timeout UnassociateEipAddress for eni: %v, %v, %v", eniID, eniIP, err)
@@ -244,7 +251,7 @@ func (e *Impl) ReleaseEipAddress(ctx context.Context, eipID, eniID string, eniIP
 				}
 			}
 		}
-		err = wait.ExponentialBackoff(backoff.Backoff(backoff.ENIRelease), func() (done bool, err error) {
+		err = wait.ExponentialBackoff(backoff.ForCategory(backoff.CategoryWrite, backoff.ENIRelease), func() (done bool, err error) {
 			innerErr = e.ReleaseEIPAddress(eip.AllocationId)
 			if innerErr != nil {
 				return false, nil
@@ -258,11 +265,11 @@ func (e *Impl) ReleaseEipAddress(ctx context.Context, eipID, eniID string, eniIP
 	return err
 }
 
-// WaitForEIP wait status of eni, ignore status if is empty
-func (e *Impl) WaitForEIP(eipID string, status string, backoff wait.Backoff) (*vpc.EipAddress, error) {
+// WaitForEIP wait status of eip, ignore status if is empty
+func (e *Impl) WaitForEIP(ctx context.Context, eipID string, status string, backoff wait.Backoff) (*vpc.EipAddress, error) {
 	var eip *vpc.EipAddress
 	var innerErr error
-	err := wait.ExponentialBackoff(backoff,
+	err := wait.ExponentialBackoffWithContext(ctx, backoff,
 		func() (done bool, err error) {
 			var eips []vpc.EipAddress
 			eips, innerErr = e.describeEipAddresses(eipID, "")
@@ -300,7 +307,7 @@ func (e *Impl) describeEipAddresses(eipID, eniID string) ([]vpc.EipAddress, erro
 	l := log.WithFields(map[string]interface{}{client.LogFieldAPI: "DescribeEipAddresses", client.LogFieldEIPID: eipID, client.LogFieldENIID: eniID})
 	start := time.Now()
 	resp, err := e.ClientSet.VPC().DescribeEipAddresses(req)
-	metric.OpenAPILatency.WithLabelValues("DescribeEipAddresses", fmt.Sprint(err != nil)).Observe(metric.MsSince(start))
+	metric.ObserveOpenAPI("DescribeEipAddresses", err != nil, start)
 	if err != nil {
 		l.WithFields(map[string]interface{}{client.LogFieldRequestID: apiErr.ErrRequestID(err)}).Warn(err)
 		return nil, err
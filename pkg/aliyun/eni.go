@@ -13,6 +13,7 @@ type ENIInfoGetter interface {
 	GetENIByMac(mac string) (*types.ENI, error)
 	GetENIPrivateAddressesByMAC(mac string) ([]net.IP, error)
 	GetENIPrivateIPv6AddressesByMAC(mac string) ([]net.IP, error)
+	GetENIPrivateIPv4PrefixesByMAC(mac string) ([]*net.IPNet, error)
 	GetENIs(containsMainENI bool) ([]*types.ENI, error)
 	GetSecondaryENIMACs() ([]string, error)
 }
@@ -97,6 +98,10 @@ func (e *ENIMetadata) GetENIPrivateIPv6AddressesByMAC(mac string) ([]net.IP, err
 	return metadata.GetENIPrivateIPv6IPs(mac)
 }
 
+func (e *ENIMetadata) GetENIPrivateIPv4PrefixesByMAC(mac string) ([]*net.IPNet, error) {
+	return metadata.GetENIIPv4Prefixes(mac)
+}
+
 func (e *ENIMetadata) GetENIs(containsMainENI bool) ([]*types.ENI, error) {
 	var enis []*types.ENI
 
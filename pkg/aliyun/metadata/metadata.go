@@ -26,6 +26,7 @@ const (
 	eniV6GatewayPath       = "network/interfaces/macs/%s/ipv6-gateway"
 	eniPrivateIPs          = "network/interfaces/macs/%s/private-ipv4s"
 	eniPrivateV6IPs        = "network/interfaces/macs/%s/ipv6s"
+	eniIPv4PrefixPath      = "network/interfaces/macs/%s/ipv4-prefixes"
 	eniVSwitchPath         = "network/interfaces/macs/%s/vswitch-id"
 	eniVSwitchCIDRPath     = "network/interfaces/macs/%s/vswitch-cidr-block"
 	eniVSwitchIPv6CIDRPath = "network/interfaces/macs/%s/vswitch-ipv6-cidr-block"
@@ -204,6 +205,24 @@ func GetENIPrivateIPv6IPs(mac string) ([]net.IP, error) {
 	return ips, nil
 }
 
+// GetENIIPv4Prefixes by mac return the delegated ipv4 prefixes
+func GetENIIPv4Prefixes(mac string) ([]*net.IPNet, error) {
+	prefixStr, err := getValue(fmt.Sprintf(metadataBase+eniIPv4PrefixPath, mac))
+	if err != nil {
+		// metadata return 404 when no prefix is allocated
+		if errors.Is(err, apiErr.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	prefixStrList := &[]string{}
+	err = json.Unmarshal([]byte(prefixStr), prefixStrList)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse prefix, %s, %w", prefixStr, err)
+	}
+	return ip.ToIPNets(*prefixStrList)
+}
+
 // GetENIGateway return gateway ip by mac
 func GetENIGateway(mac string) (net.IP, error) {
 	addr, err := getValue(fmt.Sprintf(metadataBase+eniGatewayPath, mac))
@@ -85,6 +85,10 @@ type Allocation struct {
 
 type Route struct {
 	Dst string `json:"dst,omitempty"`
+	// Src Table Priority for policy route, the route is put in the main table if Table is not set
+	Src      string `json:"src,omitempty"`
+	Table    int32  `json:"table,omitempty"`
+	Priority int32  `json:"priority,omitempty"`
 }
 
 // ENI eni info
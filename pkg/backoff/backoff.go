@@ -1,6 +1,7 @@
 package backoff
 
 import (
+	"sync"
 	"time"
 
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -14,12 +15,33 @@ const (
 	ENIRelease            = "eni_release"
 	WaitENIStatus         = "wait_eni_status"
 	WaitPodENIStatus      = "wait_podeni_status"
+	WaitEIPStatus         = "wait_eip_status"
 	MetaAssignPrivateIP   = "meta_assign_private_ip"
 	MetaUnAssignPrivateIP = "meta_unassign_private_ip"
 	WaitStsTokenReady     = "wait_sts_token_ready"
+	PatchPodIPInfo        = "patch_pod_ip_info"
+	GetPod                = "get_pod"
+	StartupK8S            = "startup_k8s"
 )
 
-var backoffMap = map[string]wait.Backoff{
+// operation categories of openapi, the backoff configured for a category applies to all the operations
+// in the category, unless the operation key is configured itself
+const (
+	CategoryRead  = "read"
+	CategoryWrite = "write"
+)
+
+// lock guard the maps below, which are replaced as a whole by OverrideBackoff at runtime
+var lock sync.RWMutex
+
+var categoryMap = map[string]wait.Backoff{}
+
+// overridden keys configured by OverrideBackoff
+var overridden = map[string]bool{}
+
+var backoffMap = defaultBackoffMap
+
+var defaultBackoffMap = map[string]wait.Backoff{
 	DefaultKey: {
 		Duration: time.Second * 2,
 		Factor:   1.5,
@@ -56,6 +78,12 @@ var backoffMap = map[string]wait.Backoff{
 		Jitter:   0.3,
 		Steps:    3,
 	},
+	WaitEIPStatus: {
+		Duration: time.Second * 2,
+		Factor:   1.5,
+		Jitter:   0.3,
+		Steps:    8,
+	},
 	MetaAssignPrivateIP: {
 		Duration: time.Millisecond * 1100,
 		Factor:   1,
@@ -74,18 +102,73 @@ var backoffMap = map[string]wait.Backoff{
 		Jitter:   0.2,
 		Steps:    60,
 	},
+	PatchPodIPInfo: {
+		Duration: time.Millisecond * 200,
+		Factor:   2,
+		Jitter:   0.3,
+		Steps:    3,
+	},
+	GetPod: {
+		Duration: time.Millisecond * 200,
+		Factor:   2,
+		Jitter:   0.3,
+		Steps:    3,
+	},
+	StartupK8S: {
+		Duration: time.Second,
+		Factor:   2,
+		Jitter:   0.3,
+		Steps:    5,
+	},
 }
 
+// OverrideBackoff replace the overrides of backoff with the given, the keys not given fallback to the default
 func OverrideBackoff(in map[string]wait.Backoff) {
+	categories := make(map[string]wait.Backoff)
+	keys := make(map[string]bool)
+	backoffs := make(map[string]wait.Backoff, len(defaultBackoffMap)+len(in))
+	for k, v := range defaultBackoffMap {
+		backoffs[k] = v
+	}
 	for k, v := range in {
-		backoffMap[k] = v
+		switch k {
+		case CategoryRead, CategoryWrite:
+			categories[k] = v
+		default:
+			backoffs[k] = v
+			keys[k] = true
+		}
 	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	categoryMap, overridden, backoffMap = categories, keys, backoffs
 }
 
 func Backoff(key string) wait.Backoff {
+	lock.RLock()
+	defer lock.RUnlock()
+	return backoffLocked(key)
+}
+
+func backoffLocked(key string) wait.Backoff {
 	b, ok := backoffMap[key]
 	if !ok {
 		return backoffMap[DefaultKey]
 	}
 	return b
 }
+
+// ForCategory return the backoff for key of the operation in category,
+// the configured key takes precedence over the category
+func ForCategory(category, key string) wait.Backoff {
+	lock.RLock()
+	defer lock.RUnlock()
+	if overridden[key] {
+		return backoffMap[key]
+	}
+	if b, ok := categoryMap[category]; ok {
+		return b
+	}
+	return backoffLocked(key)
+}
@@ -0,0 +1,64 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// saveBackoff restore the backoff tables changed by the test on cleanup
+func saveBackoff(t *testing.T) {
+	lock.RLock()
+	categories, keys, backoffs := categoryMap, overridden, backoffMap
+	lock.RUnlock()
+	t.Cleanup(func() {
+		lock.Lock()
+		defer lock.Unlock()
+		categoryMap, overridden, backoffMap = categories, keys, backoffs
+	})
+}
+
+func TestForCategory(t *testing.T) {
+	saveBackoff(t)
+	assert.Equal(t, Backoff(ENIOps), ForCategory(CategoryWrite, ENIOps))
+
+	write := wait.Backoff{Duration: 20 * time.Second, Factor: 2, Steps: 3}
+	release := wait.Backoff{Duration: time.Second, Steps: 1}
+	OverrideBackoff(map[string]wait.Backoff{
+		CategoryWrite: write,
+		ENIRelease:    release,
+	})
+
+	assert.Equal(t, write, ForCategory(CategoryWrite, ENIOps))
+	assert.Equal(t, release, ForCategory(CategoryWrite, ENIRelease))
+	assert.Equal(t, Backoff(WaitENIStatus), ForCategory(CategoryRead, WaitENIStatus))
+	// category key is not an operation key
+	assert.Equal(t, Backoff(DefaultKey), Backoff(CategoryWrite))
+}
+
+func TestOverrideBackoff(t *testing.T) {
+	t.Cleanup(func() {
+		OverrideBackoff(nil)
+	})
+	def := Backoff(ENIOps)
+	OverrideBackoff(map[string]wait.Backoff{ENIOps: {Duration: time.Second, Steps: 1}})
+	assert.NotEqual(t, def, Backoff(ENIOps))
+
+	// the key not given any more fallback to the default
+	OverrideBackoff(map[string]wait.Backoff{})
+	assert.Equal(t, def, Backoff(ENIOps))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			OverrideBackoff(map[string]wait.Backoff{CategoryWrite: {Duration: time.Second, Steps: 1}})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_ = ForCategory(CategoryWrite, ENIOps)
+	}
+	<-done
+}
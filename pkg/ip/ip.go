@@ -28,6 +28,33 @@ func ToIPs(addrs []string) ([]net.IP, error) {
 	return result, nil
 }
 
+// ToIPNets parse cidr str to net.IPNet and return error is parse failed
+func ToIPNets(addrs []string) ([]*net.IPNet, error) {
+	var result []*net.IPNet
+	for _, addr := range addrs {
+		_, ipNet, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cidr %s, %w", addr, err)
+		}
+		result = append(result, ipNet)
+	}
+	return result, nil
+}
+
+// IPNetHosts return all the ip address in the ipNet
+func IPNetHosts(ipNet *net.IPNet) []net.IP {
+	var result []net.IP
+	for ip := ipNet.IP.Mask(ipNet.Mask); ipNet.Contains(ip); {
+		result = append(result, ip)
+		next := GetNextIP(ip)
+		if next.Equal(ip) {
+			break
+		}
+		ip = next
+	}
+	return result
+}
+
 func ToIPMap(addrs []net.IP) map[string]net.IP {
 	result := make(map[string]net.IP)
 
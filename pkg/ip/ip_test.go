@@ -53,3 +53,17 @@ func Test_ipIntersect(t *testing.T) {
 		})
 	}
 }
+
+func TestIPNetHosts(t *testing.T) {
+	_, ipNet, err := net.ParseCIDR("10.0.0.16/28")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts := IPNetHosts(ipNet)
+	if len(hosts) != 16 {
+		t.Fatalf("IPNetHosts() got %d ips, want 16", len(hosts))
+	}
+	if !hosts[0].Equal(net.ParseIP("10.0.0.16")) || !hosts[15].Equal(net.ParseIP("10.0.0.31")) {
+		t.Errorf("IPNetHosts() = %v", hosts)
+	}
+}
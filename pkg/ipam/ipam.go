@@ -18,10 +18,16 @@ type API interface {
 	FreeENI(ctx context.Context, eniID string, instanceID string) error
 	GetENIIPs(ctx context.Context, mac string) ([]net.IP, []net.IP, error)
 	AssignNIPsForENI(ctx context.Context, eniID, mac string, count int) ([]net.IP, []net.IP, error)
+	AssignIPv6ForENI(ctx context.Context, eniID, mac string, count int) ([]net.IP, error)
 	UnAssignIPsForENI(ctx context.Context, eniID, mac string, ipv4s []net.IP, ipv6s []net.IP) error
+	GetENIIPv4Prefixes(ctx context.Context, mac string) ([]*net.IPNet, error)
+	AssignIPv4PrefixForENI(ctx context.Context, eniID, mac string, count int) ([]*net.IPNet, error)
+	UnAssignIPv4PrefixForENI(ctx context.Context, eniID, mac string, prefixes []*net.IPNet) error
 	GetAttachedSecurityGroups(ctx context.Context, instanceID string) ([]string, error)
 	CheckEniSecurityGroup(ctx context.Context, sgIDs []string) error
 	DescribeInstanceTypes(ctx context.Context, types []string) ([]ecs.InstanceType, error)
+	TagNetworkInterface(ctx context.Context, eniID string, tags map[string]string) error
+	UntagNetworkInterface(ctx context.Context, eniID string, keys []string) error
 
 	// FIXME remove vendor for vpc
 	DescribeVSwitchByID(ctx context.Context, vSwitch string) (*vpc.VSwitch, error)
@@ -29,5 +35,7 @@ type API interface {
 	AllocateEipAddress(ctx context.Context, bandwidth int, chargeType types.InternetChargeType, eipID, eniID string, eniIP net.IP, allowRob bool, isp, bandwidthPackageID, poolID string) (*types.EIP, error)
 	UnassociateEipAddress(ctx context.Context, eipID, eniID, eniIP string) error
 	ReleaseEipAddress(ctx context.Context, eipID, eniID string, eniIP net.IP) error
+	WaitEipAssociated(ctx context.Context, eipID, eniID string) error
+	DescribeCommonBandwidthPackage(packageID string) (*vpc.CommonBandwidthPackage, error)
 	QueryEniIDByIP(ctx context.Context, vpcID string, address net.IP) (string, error)
 }
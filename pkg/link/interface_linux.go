@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 )
 
 // GetDeviceNumber get interface device number by mac address
@@ -60,13 +61,13 @@ func DeleteIPRulesByIP(addr *net.IPNet) error {
 	return nil
 }
 
-// DeleteRouteByIP delete all route related to the addr
+// DeleteRouteByIP delete all route related to the addr, include the routes in the extra tables
 func DeleteRouteByIP(addr *net.IPNet) error {
 	family := netlink.FAMILY_V4
 	if addr.IP.To4() == nil {
 		family = netlink.FAMILY_V6
 	}
-	routes, err := netlink.RouteList(nil, family)
+	routes, err := netlink.RouteListFiltered(family, &netlink.Route{Table: unix.RT_TABLE_UNSPEC}, netlink.RT_FILTER_TABLE)
 	if err != nil {
 		return err
 	}
@@ -81,3 +82,79 @@ func DeleteRouteByIP(addr *net.IPNet) error {
 	}
 	return nil
 }
+
+// DeleteLinkByName delete the link by name, return nil if the link is already gone
+func DeleteLinkByName(name string) error {
+	l, err := netlink.LinkByName(name)
+	if err != nil {
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil
+		}
+		return err
+	}
+	log.Infof("del link %s", name)
+	return netlink.LinkDel(l)
+}
+
+// DeleteOrphanRoutes delete the ip rules and routes in the tables [tableMin, tableMax) of which the eni is gone,
+// the table of eni is tableMin + the index of the device of eni. The table of device not in liveMACs is orphan, as the
+// index of the eni deleted may be reused by other device. Only the rules from a source address with rulePriority,
+// which are created by terway, are deleted. Return the entries deleted
+func DeleteOrphanRoutes(rulePriority, tableMin, tableMax int, liveMACs []string) ([]string, error) {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, fmt.Errorf("error get link list from netlink, %w", err)
+	}
+	live := make(map[string]bool, len(liveMACs))
+	for _, mac := range liveMACs {
+		live[mac] = true
+	}
+	liveTables := make(map[int]bool, len(liveMACs))
+	for _, link := range links {
+		if _, ok := link.(*netlink.Device); !ok {
+			continue
+		}
+		if live[link.Attrs().HardwareAddr.String()] {
+			liveTables[tableMin+link.Attrs().Index] = true
+		}
+	}
+	orphan := func(table int) bool {
+		return table >= tableMin && table < tableMax && !liveTables[table]
+	}
+
+	var deleted []string
+	for _, family := range []int{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		rules, err := netlink.RuleList(family)
+		if err != nil {
+			return deleted, err
+		}
+		for _, r := range rules {
+			if r.Priority != rulePriority || r.Src == nil || !orphan(r.Table) {
+				continue
+			}
+			log.Infof("del orphan ip rule %s", r.String())
+			err = netlink.RuleDel(&r)
+			if err != nil && !os.IsNotExist(err) {
+				return deleted, err
+			}
+			deleted = append(deleted, r.String())
+		}
+
+		routes, err := netlink.RouteListFiltered(family, &netlink.Route{Table: unix.RT_TABLE_UNSPEC}, netlink.RT_FILTER_TABLE)
+		if err != nil {
+			return deleted, err
+		}
+		for _, r := range routes {
+			if !orphan(r.Table) {
+				continue
+			}
+			log.Infof("del orphan route %s", r.String())
+			err = netlink.RouteDel(&r)
+			if err != nil && !os.IsNotExist(err) {
+				return deleted, err
+			}
+			deleted = append(deleted, r.String())
+		}
+	}
+	return deleted, nil
+}
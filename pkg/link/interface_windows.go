@@ -63,3 +63,13 @@ func DeleteRouteByIP(addr *net.IPNet) error {
 	}
 	return nil
 }
+
+// DeleteLinkByName delete the link by name
+func DeleteLinkByName(name string) error {
+	return ErrUnsupported
+}
+
+// DeleteOrphanRoutes delete the ip rules and routes of which the eni is gone
+func DeleteOrphanRoutes(rulePriority, tableMin, tableMax int, liveMACs []string) ([]string, error) {
+	return nil, ErrUnsupported
+}
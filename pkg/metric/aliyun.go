@@ -1,6 +1,11 @@
 package metric
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 var (
 	// OpenAPILatency aliyun open api latency
@@ -12,6 +17,14 @@ var (
 		},
 		[]string{"api", "error"},
 	)
+	// OpenAPICallCount aliyun open api call count, for attributing the api usage to operations
+	OpenAPICallCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aliyun_openapi_call_count",
+			Help: "aliyun openapi call count",
+		},
+		[]string{"api", "result"},
+	)
 	// MetadataLatency aliyun metadata latency
 	MetadataLatency = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
@@ -22,3 +35,13 @@ var (
 		[]string{"url", "error"},
 	)
 )
+
+// ObserveOpenAPI record the latency and count of the open api call
+func ObserveOpenAPI(api string, failed bool, start time.Time) {
+	OpenAPILatency.WithLabelValues(api, fmt.Sprint(failed)).Observe(MsSince(start))
+	result := "success"
+	if failed {
+		result = "error"
+	}
+	OpenAPICallCount.WithLabelValues(api, result).Inc()
+}
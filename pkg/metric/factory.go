@@ -28,9 +28,28 @@ var (
 			Name: "terway_eniip_factory_ip_alloc_count",
 			Help: "counter of eniip factory ip allocation",
 		},
-		// status in "succeed" or "fail"
+		// status in "succeed", "fail" or "shortfall"
 		[]string{"eni", "status"},
 	)
+
+	// ENIAttachLatency latency of eni creation until the device appears in kernel
+	ENIAttachLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "terway_eni_attach_latency",
+			Help:    "latency of eni creation until the device appears in kernel in ms",
+			Buckets: []float64{1000, 2000, 4000, 8000, 16000, 32000, 64000, 128000},
+		},
+		// status in "succeed", "not_in_kernel" or "fail"
+		[]string{"status"},
+	)
+
+	// ENIQuotaUsage fraction of the instance eni quota used by the attached enis
+	ENIQuotaUsage = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "terway_eni_quota_usage",
+			Help: "fraction of the instance eni quota used by the attached enis",
+		},
+	)
 )
 
 const (
@@ -38,4 +57,13 @@ const (
 	ENIIPAllocActionSucceed = "succeed"
 	// ENIIPAllocActionFail represents a failed ip alloc request
 	ENIIPAllocActionFail = "fail"
+	// ENIIPAllocActionShortfall represents the ips requested but not assigned by ecs in a partial success
+	ENIIPAllocActionShortfall = "shortfall"
+
+	// ENIAttachSucceed the eni is attached and the device appears in kernel
+	ENIAttachSucceed = "succeed"
+	// ENIAttachNotInKernel the eni is attached in ecs, but the device not appears in kernel in time
+	ENIAttachNotInKernel = "not_in_kernel"
+	// ENIAttachFail the eni failed to create or attach
+	ENIAttachFail = "fail"
 )
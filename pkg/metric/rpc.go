@@ -12,4 +12,39 @@ var (
 		},
 		[]string{"rpc_api", "error"},
 	)
+
+	// RollbackCount the count of resource rolled back on AllocIP failure
+	RollbackCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "terway_rpc_rollback_count",
+			Help: "terway resource rollback count on AllocIP failure",
+		},
+		[]string{"resource_type", "reason"},
+	)
+
+	// DuplicateContainerIDCount the count of container id referenced by more than one pod in resource db
+	DuplicateContainerIDCount = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "terway_duplicate_container_id_count",
+			Help: "terway count of container id referenced by more than one pod in resource db",
+		},
+	)
+
+	// InvalidResourceMappingCount the count of resources disagreed among pool, ecs and resource db
+	InvalidResourceMappingCount = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "terway_invalid_resource_mapping_count",
+			Help: "terway count of invalid resource mapping on period check, by mismatch kind",
+		},
+		[]string{"kind"},
+	)
+
+	// ContainerIDMismatchCount the count of cni requests ignored for not matching the container id stored
+	ContainerIDMismatchCount = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "terway_container_id_mismatch_count",
+			Help: "terway count of cni requests ignored for not matching the container id stored",
+		},
+		[]string{"rpc_api"},
+	)
 )
@@ -83,7 +83,24 @@ type ObjectPool interface {
 	ReleaseWithReservation(resID string, reservation time.Duration) error
 	Release(resID string) error
 	AcquireAny(ctx context.Context, idempotentKey string) (types.NetworkResource, error)
+	// AcquireMatch acquire a resource satisfy match, create is called instead of the factory when no idle resource matched,
+	// the resource created but not matched is put back to idle. ErrNotFound is returned if create is nil and no idle matched
+	AcquireMatch(ctx context.Context, resID, idempotentKey string, match func(types.NetworkResource) bool, create func() ([]types.NetworkResource, error)) (types.NetworkResource, error)
 	Stat(resID string) (types.NetworkResource, error)
+	// Free return the count of resource can be acquired, include idle and the ones can be created
+	Free() int
+	// Capacity return the count of resource can be created and the max count of resource in the pool
+	Capacity() (creatable, capacity int)
+	// Warm create resources until idle count reach target, return the count of resources added
+	Warm(ctx context.Context, target int) (int, error)
+	// Drain dispose all idle resources regardless of min idle and reservation, return the count of resources disposed
+	Drain(ctx context.Context) (int, error)
+	// Resize update the min and max idle of the pool, max idle is limited by the capacity
+	Resize(minIdle, maxIdle int) error
+	// Remove drop the resource in use from pool without dispose, for the resource disposed by the caller
+	Remove(resID string) error
+	// Replace the resource in use with res, for the resource changed by the caller, e.g. one ip of it released
+	Replace(resID string, res types.NetworkResource) error
 	GetName() string
 	tracing.ResourceMappingHandler
 }
@@ -426,6 +443,65 @@ func (p *simpleObjectPool) AcquireAny(ctx context.Context, idempotentKey string)
 	return p.Acquire(ctx, "", idempotentKey)
 }
 
+func (p *simpleObjectPool) AcquireMatch(ctx context.Context, resID, idempotentKey string, match func(types.NetworkResource) bool, create func() ([]types.NetworkResource, error)) (types.NetworkResource, error) {
+	p.lock.Lock()
+	if resItem, ok := p.inuse[resID]; ok && resItem.idempotentKey == idempotentKey && match(resItem.res) {
+		p.lock.Unlock()
+		return resItem.res, nil
+	}
+
+	var item *poolItem
+	if len(resID) > 0 {
+		item = p.idle.RobMatch(func(res types.NetworkResource) bool {
+			return res.GetResourceID() == resID && match(res)
+		})
+	}
+	if item == nil {
+		item = p.idle.RobMatch(match)
+	}
+	if item != nil {
+		res := item.res
+		p.inuse[res.GetResourceID()] = poolItem{res: res, idempotentKey: idempotentKey}
+		p.lock.Unlock()
+		log.Infof("acquire match (expect %s): return idle %s", resID, res.GetResourceID())
+		p.metricIdle.Dec()
+		p.notify()
+		return res, nil
+	}
+	if create == nil {
+		p.lock.Unlock()
+		return nil, ErrNotFound
+	}
+	size := p.sizeLocked()
+	if size >= p.capacity {
+		p.lock.Unlock()
+		log.Infof("acquire match (expect %s), size %d, capacity %d: return err %v", resID, size, p.capacity, ErrNoAvailableResource)
+		return nil, ErrNoAvailableResource
+	}
+
+	p.lock.Unlock()
+
+	select {
+	case <-p.tokenCh:
+		res, err := create()
+		if err != nil || len(res) == 0 {
+			p.tokenCh <- struct{}{}
+			return nil, fmt.Errorf("error create: %w", err)
+		}
+		if !match(res[0]) {
+			log.Infof("acquire match (expect %s): newly %s not match, add to idle", resID, res[0].GetResourceID())
+			p.AddIdle(res[0])
+			return nil, fmt.Errorf("resource %s created not match", res[0].GetResourceID())
+		}
+		log.Infof("acquire match (expect %s): return newly %s", resID, res[0].GetResourceID())
+		p.AddInuse(res[0], idempotentKey)
+		return res[0], nil
+	case <-ctx.Done():
+		log.Infof("acquire match (expect %s): return err %v", resID, ErrContextDone)
+		return nil, ErrContextDone
+	}
+}
+
 func (p *simpleObjectPool) Stat(resID string) (types.NetworkResource, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -441,6 +517,105 @@ func (p *simpleObjectPool) Stat(resID string) (types.NetworkResource, error) {
 	return nil, ErrNotFound
 }
 
+func (p *simpleObjectPool) Free() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.idle.Size() + len(p.tokenCh)
+}
+
+func (p *simpleObjectPool) Capacity() (creatable, capacity int) {
+	return len(p.tokenCh), p.capacity
+}
+
+func (p *simpleObjectPool) Warm(ctx context.Context, target int) (int, error) {
+	p.lock.Lock()
+	if target > p.maxIdle {
+		target = p.maxIdle
+	}
+	addition := target - p.idle.Size()
+	if addition > p.capacity-p.sizeLocked() {
+		addition = p.capacity - p.sizeLocked()
+	}
+	p.lock.Unlock()
+	if addition <= 0 {
+		return 0, nil
+	}
+
+	var tokenAcquired int
+	for i := 0; i < addition; i++ {
+		select {
+		case <-p.tokenCh:
+			tokenAcquired++
+		case <-ctx.Done():
+			for ; tokenAcquired > 0; tokenAcquired-- {
+				p.tokenCh <- struct{}{}
+			}
+			return 0, ErrContextDone
+		}
+	}
+
+	resList, err := p.factory.Create(tokenAcquired)
+	for _, res := range resList {
+		log.Infof("warm up: add resource %s to pool idle", res.GetResourceID())
+		p.AddIdle(res)
+	}
+	for i := len(resList); i < tokenAcquired; i++ {
+		p.tokenCh <- struct{}{}
+	}
+	if err != nil {
+		return len(resList), fmt.Errorf("error create from factory: %w", err)
+	}
+	return len(resList), nil
+}
+
+func (p *simpleObjectPool) Drain(ctx context.Context) (int, error) {
+	disposed := 0
+	for {
+		if ctx.Err() != nil {
+			return disposed, ErrContextDone
+		}
+		p.lock.Lock()
+		item := p.idle.Pop()
+		p.lock.Unlock()
+		if item == nil {
+			return disposed, nil
+		}
+
+		p.metricIdle.Dec()
+		p.metricTotal.Dec()
+
+		res := item.res
+		log.Infof("drain: dispose res %+v", res)
+		err := p.factory.Dispose(res)
+		if err != nil {
+			p.AddIdle(res)
+			return disposed, fmt.Errorf("error dispose res %s: %w", res.GetResourceID(), err)
+		}
+		p.tokenCh <- struct{}{}
+		p.metricDisposed.Inc()
+		disposed++
+	}
+}
+
+func (p *simpleObjectPool) Resize(minIdle, maxIdle int) error {
+	if minIdle < 0 || minIdle > maxIdle {
+		return ErrInvalidArguments
+	}
+	p.lock.Lock()
+	if maxIdle > p.capacity {
+		maxIdle = p.capacity
+	}
+	if minIdle > maxIdle {
+		minIdle = maxIdle
+	}
+	log.Infof("resize pool %s, min idle %d -> %d, max idle %d -> %d", p.name, p.minIdle, minIdle, p.maxIdle, maxIdle)
+	p.minIdle, p.maxIdle = minIdle, maxIdle
+	p.lock.Unlock()
+	// dispose or create resources by the new size
+	p.notify()
+	return nil
+}
+
 func (p *simpleObjectPool) GetName() string {
 	return p.name
 }
@@ -526,6 +701,34 @@ func (p *simpleObjectPool) Release(resID string) error {
 	return p.ReleaseWithReservation(resID, time.Duration(0))
 }
 
+func (p *simpleObjectPool) Remove(resID string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.inuse[resID]; !ok {
+		return ErrInvalidState
+	}
+	log.Infof("remove %s from pool", resID)
+	delete(p.inuse, resID)
+	p.tokenCh <- struct{}{}
+	p.metricTotal.Dec()
+	p.metricDisposed.Inc()
+	return nil
+}
+
+func (p *simpleObjectPool) Replace(resID string, res types.NetworkResource) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	item, ok := p.inuse[resID]
+	if !ok {
+		return ErrInvalidState
+	}
+	log.Infof("replace %s with %s in pool", resID, res.GetResourceID())
+	delete(p.inuse, resID)
+	item.res = res
+	p.inuse[res.GetResourceID()] = item
+	return nil
+}
+
 func (p *simpleObjectPool) AddIdle(resource types.NetworkResource) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
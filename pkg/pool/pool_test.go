@@ -333,3 +333,138 @@ func TestGetResourceMapping(t *testing.T) {
 	assert.NotNil(t, mapping.GetLocal())
 	assert.NotNil(t, mapping.GetRemote())
 }
+
+func TestFree(t *testing.T) {
+	factory := newMockObjectFactory(1000)
+	pool := createPool(factory, 0, 5, 3, 2)
+	assert.Equal(t, 8, pool.Free())
+	_, err := pool.Acquire(context.Background(), "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, 7, pool.Free())
+}
+
+func TestWarm(t *testing.T) {
+	factory := newMockObjectFactory(1000)
+	pool := createPool(factory, 0, 5, 1, 2)
+	added, err := pool.Warm(context.Background(), 4)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, added)
+	assert.Equal(t, 3, factory.getTotalCreated())
+
+	// bounded by max idle
+	added, err = pool.Warm(context.Background(), 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 4, factory.getTotalCreated())
+}
+
+func TestDrain(t *testing.T) {
+	factory := newMockObjectFactory(1000)
+	pool := createPool(factory, 0, 5, 3, 2)
+	disposed, err := pool.Drain(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, disposed)
+	assert.Equal(t, 3, factory.getTotalDisposed())
+
+	// in use resources are kept
+	res, err := pool.Stat("1004")
+	assert.Nil(t, err)
+	assert.Equal(t, "1004", res.GetResourceID())
+	_, err = pool.Stat("1001")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestResize(t *testing.T) {
+	factory := newMockObjectFactory(1000)
+	pool := createPool(factory, 0, 5, 4, 0)
+	assert.Equal(t, ErrInvalidArguments, pool.Resize(3, 2))
+
+	// overfull idle is disposed
+	assert.Nil(t, pool.Resize(0, 2))
+	time.Sleep(1 * time.Second)
+	assert.Equal(t, 2, factory.getTotalDisposed())
+
+	// bounded by capacity
+	assert.Nil(t, pool.Resize(20, 20))
+	time.Sleep(1 * time.Second)
+	assert.Equal(t, 8, factory.getTotalCreated())
+}
+
+func TestRemove(t *testing.T) {
+	factory := newMockObjectFactory(1000)
+	pool := createPool(factory, 0, 0, 0, 10)
+	assert.Equal(t, 0, pool.Free())
+
+	assert.Nil(t, pool.Remove("1001"))
+	assert.Equal(t, 1, pool.Free())
+	assert.Equal(t, 0, factory.getTotalDisposed())
+	_, err := pool.Stat("1001")
+	assert.Equal(t, ErrNotFound, err)
+
+	assert.Equal(t, ErrInvalidState, pool.Remove("1001"))
+}
+
+func TestReplace(t *testing.T) {
+	factory := newMockObjectFactory(1000)
+	pool := createPool(factory, 0, 0, 0, 10)
+
+	assert.Nil(t, pool.Replace("1001", &mockNetworkResource{ID: "2001"}))
+	_, err := pool.Stat("1001")
+	assert.Equal(t, ErrNotFound, err)
+	res, err := pool.Stat("2001")
+	assert.Nil(t, err)
+	assert.Equal(t, "2001", res.GetResourceID())
+	assert.Equal(t, 0, pool.Free())
+
+	assert.Equal(t, ErrInvalidState, pool.Replace("1001", &mockNetworkResource{ID: "2002"}))
+}
+
+func TestCapacity(t *testing.T) {
+	factory := newMockObjectFactory(1000)
+	pool := createPool(factory, 0, 5, 3, 2)
+	creatable, capacity := pool.Capacity()
+	assert.Equal(t, 5, creatable)
+	assert.Equal(t, 10, capacity)
+
+	_, err := pool.Acquire(context.Background(), "", "")
+	assert.Nil(t, err)
+	creatable, _ = pool.Capacity()
+	assert.Equal(t, 5, creatable)
+	assert.Equal(t, 7, pool.Free())
+}
+
+func TestAcquireMatch(t *testing.T) {
+	factory := newMockObjectFactory(1000)
+	pool := createPool(factory, 0, 5, 3, 0)
+	matchID := func(id string) func(types.NetworkResource) bool {
+		return func(res types.NetworkResource) bool {
+			return res.GetResourceID() == id
+		}
+	}
+
+	create := func() ([]types.NetworkResource, error) {
+		return factory.Create(1)
+	}
+
+	// idle resource matched
+	res, err := pool.AcquireMatch(context.Background(), "", "", matchID("1002"), create)
+	assert.Nil(t, err)
+	assert.Equal(t, "1002", res.GetResourceID())
+	assert.Equal(t, 0, factory.getTotalCreated())
+
+	// created resource matched
+	res, err = pool.AcquireMatch(context.Background(), "", "", matchID("1004"), create)
+	assert.Nil(t, err)
+	assert.Equal(t, "1004", res.GetResourceID())
+
+	// created resource not matched is put back to idle
+	_, err = pool.AcquireMatch(context.Background(), "", "", matchID("none"), create)
+	assert.NotNil(t, err)
+	res, err = pool.Stat("1005")
+	assert.Nil(t, err)
+	assert.Equal(t, "1005", res.GetResourceID())
+	// not create without create func
+	_, err = pool.AcquireMatch(context.Background(), "", "", matchID("none"), nil)
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, 2, factory.getTotalCreated())
+}
@@ -1,5 +1,7 @@
 package pool
 
+import "github.com/AliyunContainerService/terway/types"
+
 type priorityQueue struct {
 	slots    []*poolItem
 	size     int
@@ -89,6 +91,21 @@ func (q *priorityQueue) Rob(id string) *poolItem {
 	return nil
 }
 
+// RobMatch rob the first item satisfy match
+func (q *priorityQueue) RobMatch(match func(res types.NetworkResource) bool) *poolItem {
+	for i := 0; i < q.size; i++ {
+		item := q.slots[i]
+		if match(item.res) {
+			q.slots[i] = q.slots[q.size-1]
+			q.size--
+			q.bubbleDown(i)
+			return item
+		}
+	}
+
+	return nil
+}
+
 func (q *priorityQueue) Find(id string) *poolItem {
 	for i := 0; i < q.size; i++ {
 		if q.slots[i].res.GetResourceID() == id {
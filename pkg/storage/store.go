@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/AliyunContainerService/terway/pkg/logger"
 
@@ -13,9 +15,23 @@ import (
 
 var log = logger.DefaultLogger.WithField("subSys", "storage")
 
+// quarantineBucketSuffix suffix of the bucket name the quarantined records are moved to
+const quarantineBucketSuffix = "_quarantine"
+
 // ErrNotFound key not found in store
 var ErrNotFound = errors.New("not found")
 
+// ErrQuarantine is returned by Deserializer for the record can't be understood, e.g. stored by newer version,
+// the record is moved to the quarantine bucket instead of failing the load.
+// The Deserializer may return the part of the record understood along with it, which is listed by ListQuarantined
+var ErrQuarantine = errors.New("record quarantined")
+
+// QuarantineLister is implemented by the storage keeps the quarantined records
+type QuarantineLister interface {
+	// ListQuarantined list the part understood of the quarantined records
+	ListQuarantined() ([]interface{}, error)
+}
+
 // Storage persistent storage on disk
 type Storage interface {
 	Put(key string, value interface{}) error
@@ -86,8 +102,14 @@ type DiskStorage struct {
 	db           *bolt.DB
 	name         string
 	memory       *MemoryStorage
+	quarantined  *MemoryStorage
 	serializer   Serializer
 	deserializer Deserializer
+
+	// revision is increased on every change, the snapshot is written only when it's changed
+	revision         int64
+	snapshotLock     sync.Mutex
+	snapshotRevision int64
 }
 
 // NewDiskStorage return new disk storage
@@ -105,8 +127,11 @@ func NewDiskStorage(name string, path string, serializer Serializer, deserialize
 		db:           db,
 		name:         name,
 		memory:       NewMemoryStorage(),
+		quarantined:  NewMemoryStorage(),
 		serializer:   serializer,
 		deserializer: deserializer,
+
+		snapshotRevision: -1,
 	}
 
 	err = diskstorage.load()
@@ -132,10 +157,12 @@ func (d *DiskStorage) Put(key string, value interface{}) error {
 	if err != nil {
 		return err
 	}
+	atomic.AddInt64(&d.revision, 1)
 	return d.memory.Put(key, value)
 }
 
-// load all data from disk db
+// load all data from disk db, the records can't be understood are moved to the quarantine bucket,
+// and the quarantined records understood now are moved back
 func (d *DiskStorage) load() error {
 	err := d.db.Update(func(tx *bolt.Tx) error {
 		_, err := tx.CreateBucketIfNotExists([]byte(d.name))
@@ -145,12 +172,23 @@ func (d *DiskStorage) load() error {
 		return err
 	}
 
+	var quarantined, restored []string
+	quarantinedSet := make(map[string]struct{})
 	err = d.db.View(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(d.name))
 		cursor := b.Cursor()
 		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
 			log.Infof("load pod cache %s from db", k)
 			obj, err := d.deserializer(v)
+			if errors.Is(err, ErrQuarantine) {
+				log.Warnf("quarantine pod cache %s: %v", k, err)
+				quarantined = append(quarantined, string(k))
+				quarantinedSet[string(k)] = struct{}{}
+				if obj != nil {
+					_ = d.quarantined.Put(string(k), obj)
+				}
+				continue
+			}
 			if err != nil {
 				return err
 			}
@@ -159,9 +197,78 @@ func (d *DiskStorage) load() error {
 				return err
 			}
 		}
+
+		q := tx.Bucket([]byte(d.name + quarantineBucketSuffix))
+		if q == nil {
+			return nil
+		}
+		return q.ForEach(func(k, v []byte) error {
+			obj, err := d.deserializer(v)
+			if errors.Is(err, ErrQuarantine) {
+				if obj != nil {
+					_ = d.quarantined.Put(string(k), obj)
+				}
+				return nil
+			}
+			if err != nil {
+				log.Warnf("error load quarantined pod cache %s: %v", k, err)
+				return nil
+			}
+			if _, err = d.memory.Get(string(k)); err == nil {
+				// the record stored after quarantined is newer
+				return nil
+			}
+			if _, ok := quarantinedSet[string(k)]; ok {
+				// the record stored after quarantined is newer, it takes the place of this one in quarantine
+				return nil
+			}
+			log.Infof("restore quarantined pod cache %s", k)
+			restored = append(restored, string(k))
+			return d.memory.Put(string(k), obj)
+		})
+	})
+	if err != nil || len(quarantined)+len(restored) == 0 {
+		return err
+	}
+	return d.quarantine(quarantined, restored)
+}
+
+// quarantine move the records to the quarantine bucket, which are kept for inspection,
+// and move the restored records back
+func (d *DiskStorage) quarantine(keys, restored []string) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(d.name))
+		q, err := tx.CreateBucketIfNotExists([]byte(d.name + quarantineBucketSuffix))
+		if err != nil {
+			return err
+		}
+		for _, k := range restored {
+			err = b.Put([]byte(k), q.Get([]byte(k)))
+			if err != nil {
+				return err
+			}
+			err = q.Delete([]byte(k))
+			if err != nil {
+				return err
+			}
+		}
+		for _, k := range keys {
+			err = q.Put([]byte(k), b.Get([]byte(k)))
+			if err != nil {
+				return err
+			}
+			err = b.Delete([]byte(k))
+			if err != nil {
+				return err
+			}
+		}
 		return nil
 	})
-	return err
+}
+
+// ListQuarantined list the part understood of the quarantined records
+func (d *DiskStorage) ListQuarantined() ([]interface{}, error) {
+	return d.quarantined.List()
 }
 
 // Get value in disk storage
@@ -183,5 +290,144 @@ func (d *DiskStorage) Delete(key string) error {
 	if err != nil {
 		return err
 	}
+	atomic.AddInt64(&d.revision, 1)
 	return d.memory.Delete(key)
 }
+
+// Snapshot write a consistent copy of the db to path if it's changed since the last snapshot,
+// the copy is replaced atomically and can be read by other processes without waiting for the file lock of the db
+func (d *DiskStorage) Snapshot(path string) error {
+	d.snapshotLock.Lock()
+	defer d.snapshotLock.Unlock()
+	revision := atomic.LoadInt64(&d.revision)
+	if revision == d.snapshotRevision {
+		return nil
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	err = d.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(f)
+		return err
+	})
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return err
+	}
+	d.snapshotRevision = revision
+	return nil
+}
+
+// Close the disk db
+func (d *DiskStorage) Close() error {
+	return d.db.Close()
+}
+
+// ErrReadOnly is returned by the write operations of read only storage
+var ErrReadOnly = errors.New("storage is read only")
+
+// readOnlyOpenTimeout the time waiting for the file lock of the db
+const readOnlyOpenTimeout = time.Second
+
+// ReadOnlyDiskStorage serve the records of a disk storage owned by another process from memory,
+// the view is loaded from the snapshot written by DiskStorage.Snapshot and refreshed by Refresh
+type ReadOnlyDiskStorage struct {
+	path         string
+	name         string
+	deserializer Deserializer
+
+	lock   sync.RWMutex
+	memory *MemoryStorage
+}
+
+// NewReadOnlyDiskStorage return new read only disk storage, the db is loaded once if it's available
+func NewReadOnlyDiskStorage(name string, path string, deserializer Deserializer) *ReadOnlyDiskStorage {
+	d := &ReadOnlyDiskStorage{
+		path:         path,
+		name:         name,
+		deserializer: deserializer,
+		memory:       NewMemoryStorage(),
+	}
+	err := d.Refresh()
+	if err != nil {
+		log.Warnf("error load read only storage %s: %v", path, err)
+	}
+	return d
+}
+
+// Refresh reload all data from disk db, the previous view is kept on error
+func (d *ReadOnlyDiskStorage) Refresh() error {
+	if _, err := os.Stat(d.path); err != nil {
+		return err
+	}
+	db, err := bolt.Open(d.path, 0600, &bolt.Options{ReadOnly: true, Timeout: readOnlyOpenTimeout})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	memory := NewMemoryStorage()
+	err = db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(d.name))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			obj, err := d.deserializer(v)
+			if errors.Is(err, ErrQuarantine) {
+				// left to the writer to quarantine
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return memory.Put(string(k), obj)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	d.lock.Lock()
+	d.memory = memory
+	d.lock.Unlock()
+	return nil
+}
+
+func (d *ReadOnlyDiskStorage) view() *MemoryStorage {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.memory
+}
+
+// Put is not allowed on read only storage
+func (d *ReadOnlyDiskStorage) Put(key string, value interface{}) error {
+	return ErrReadOnly
+}
+
+// Get value in the last loaded view
+func (d *ReadOnlyDiskStorage) Get(key string) (interface{}, error) {
+	return d.view().Get(key)
+}
+
+// List values in the last loaded view
+func (d *ReadOnlyDiskStorage) List() ([]interface{}, error) {
+	return d.view().List()
+}
+
+// Delete is not allowed on read only storage
+func (d *ReadOnlyDiskStorage) Delete(key string) error {
+	return ErrReadOnly
+}
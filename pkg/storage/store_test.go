@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func deserializeString(data []byte) (interface{}, error) {
+	var s string
+	err := json.Unmarshal(data, &s)
+	return s, err
+}
+
+func TestReadOnlyDiskStorageFromSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	snapshot := filepath.Join(dir, "db.snapshot")
+	db, err := NewDiskStorage("test", filepath.Join(dir, "db"), json.Marshal, deserializeString)
+	assert.NoError(t, err)
+	defer db.(*DiskStorage).Close()
+	assert.NoError(t, db.Put("a", "1"))
+	assert.NoError(t, db.(*DiskStorage).Snapshot(snapshot))
+
+	// the snapshot is read while the db is still locked by the writer
+	ro := NewReadOnlyDiskStorage("test", snapshot, deserializeString)
+	v, err := ro.Get("a")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", v)
+	assert.Equal(t, ErrReadOnly, ro.Put("b", "2"))
+
+	assert.NoError(t, db.Put("b", "2"))
+	assert.NoError(t, db.(*DiskStorage).Snapshot(snapshot))
+	assert.NoError(t, ro.Refresh())
+	v, err = ro.Get("b")
+	assert.NoError(t, err)
+	assert.Equal(t, "2", v)
+}
+
+// deserializeUnknown return a deserializer quarantine the string with any of the prefixes
+func deserializeUnknown(prefixes ...string) Deserializer {
+	return func(data []byte) (interface{}, error) {
+		obj, err := deserializeString(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(obj.(string), prefix) {
+				return nil, ErrQuarantine
+			}
+		}
+		return obj, nil
+	}
+}
+
+func TestDiskStorageQuarantineNewer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	db, err := NewDiskStorage("test", path, json.Marshal, deserializeString)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put("a", "v2-old"))
+	db.(*DiskStorage).Close()
+
+	// quarantined by old version, and a newer record stored after
+	db, err = NewDiskStorage("test", path, json.Marshal, deserializeUnknown("v2", "v3"))
+	assert.NoError(t, err)
+	_, err = db.Get("a")
+	assert.Error(t, err)
+	assert.NoError(t, db.Put("a", "v3-new"))
+	db.(*DiskStorage).Close()
+
+	// the newer record is quarantined in place of the old one, which is not restored
+	db, err = NewDiskStorage("test", path, json.Marshal, deserializeUnknown("v3"))
+	assert.NoError(t, err)
+	_, err = db.Get("a")
+	assert.Error(t, err)
+	db.(*DiskStorage).Close()
+
+	db, err = NewDiskStorage("test", path, json.Marshal, deserializeString)
+	assert.NoError(t, err)
+	defer db.(*DiskStorage).Close()
+	v, err := db.Get("a")
+	assert.NoError(t, err)
+	assert.Equal(t, "v3-new", v)
+}
@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+)
+
+// Attribute is a key-value pair attached to a span
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// Attr creates an Attribute
+func Attr(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span represents a phase of a traced operation
+type Span interface {
+	// SetAttributes set attributes on the span
+	SetAttributes(attrs ...Attribute)
+	// RecordError record the error as the span outcome, nil error is ignored
+	RecordError(err error)
+	// End finish the span
+	End()
+}
+
+// SpanStarter creates spans, the returned context carries the span so spans started from it are children
+// implement it with an adapter to plug in a tracing backend like OpenTelemetry
+type SpanStarter interface {
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+type noopSpanStarter struct{}
+
+func (noopSpanStarter) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+var (
+	spanStarterMtx sync.RWMutex
+	spanStarter    SpanStarter = noopSpanStarter{}
+)
+
+// SetSpanStarter set the SpanStarter used by StartSpan, nil for disable span
+func SetSpanStarter(starter SpanStarter) {
+	spanStarterMtx.Lock()
+	defer spanStarterMtx.Unlock()
+
+	if starter == nil {
+		starter = noopSpanStarter{}
+	}
+	spanStarter = starter
+}
+
+// StartSpan starts a span with the registered SpanStarter, spans are dropped if no SpanStarter registered
+func StartSpan(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	spanStarterMtx.RLock()
+	starter := spanStarter
+	spanStarterMtx.RUnlock()
+
+	return starter.Start(ctx, name, attrs...)
+}
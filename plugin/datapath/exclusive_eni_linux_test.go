@@ -11,6 +11,7 @@ import (
 	"github.com/AliyunContainerService/terway/plugin/driver/utils"
 	terwayTypes "github.com/AliyunContainerService/terway/types"
 
+	cniTypes "github.com/containernetworking/cni/pkg/types"
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/containernetworking/plugins/pkg/testutils"
 	"github.com/stretchr/testify/assert"
@@ -170,3 +171,27 @@ func TestDataPathExclusiveENI(t *testing.T) {
 	_, ok = err.(netlink.LinkNotFoundError)
 	assert.True(t, ok)
 }
+
+func TestGenerateExtraRoutes(t *testing.T) {
+	link := &netlink.Dummy{LinkAttrs: netlink.LinkAttrs{Index: 10}}
+	_, dst, _ := net.ParseCIDR("100.64.0.0/10")
+	_, dstMain, _ := net.ParseCIDR("100.100.0.0/16")
+	cfg := &types2.SetupConfig{
+		ContainerIPNet: &terwayTypes.IPNetSet{IPv4: containerIPNet},
+		ExtraRoutes: []types2.Route{
+			{Route: cniTypes.Route{Dst: *dst, GW: ipv4GW}, Table: 100},
+			{Route: cniTypes.Route{Dst: *dstMain}},
+		},
+	}
+
+	routes, rules := generateExtraRoutes(cfg.ExtraRoutes, cfg.ContainerIPNet, link)
+	assert.Len(t, routes, 2)
+	assert.Equal(t, 100, routes[0].Table)
+	assert.Equal(t, 0, routes[1].Table)
+
+	// only the route with table need the policy rule, and the unset priority is left to kernel
+	assert.Len(t, rules, 1)
+	assert.Equal(t, 100, rules[0].Table)
+	assert.Equal(t, -1, rules[0].Priority)
+	assert.Equal(t, containerIPNet.IP.String(), rules[0].Src.IP.String())
+}
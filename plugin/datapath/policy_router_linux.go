@@ -8,6 +8,7 @@ import (
 	"github.com/AliyunContainerService/terway/plugin/driver/types"
 	"github.com/AliyunContainerService/terway/plugin/driver/utils"
 	"github.com/AliyunContainerService/terway/plugin/driver/veth"
+	terwayTypes "github.com/AliyunContainerService/terway/types"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/vishvananda/netlink"
@@ -138,35 +139,97 @@ func generateContCfgForPolicy(cfg *types.SetupConfig, link netlink.Link, mac net
 		sysctl = utils.GenerateIPv6Sysctl(cfg.ContainerIfName, true, false)
 	}
 
-	for i := range cfg.ExtraRoutes {
-		if cfg.ExtraRoutes[i].GW != nil {
+	extraRoutes, extraRules := generateExtraRoutes(cfg.ExtraRoutes, cfg.ContainerIPNet, link)
+	routes = append(routes, extraRoutes...)
+	rules = append(rules, extraRules...)
+
+	contCfg := &nic.Conf{
+		IfName: cfg.ContainerIfName,
+		MTU:    cfg.MTU,
+		Addrs:  utils.NewIPNetToMaxMask(cfg.ContainerIPNet),
+		Routes: routes,
+		Rules:  rules,
+		Neighs: neighs,
+		SysCtl: sysctl,
+	}
+
+	return contCfg
+}
+
+// generateExtraRoutes build the routes of the extra routes on the container link,
+// routes with a table are looked up by the policy rule from extraRouteRule
+func generateExtraRoutes(extraRoutes []types.Route, containerIPNet *terwayTypes.IPNetSet, link netlink.Link) ([]*netlink.Route, []*netlink.Rule) {
+	var routes []*netlink.Route
+	var rules []*netlink.Rule
+	for i := range extraRoutes {
+		extra := &extraRoutes[i]
+		if extra.GW != nil {
 			routes = append(routes, &netlink.Route{
 				LinkIndex: link.Attrs().Index,
 				Scope:     netlink.SCOPE_UNIVERSE,
 				Flags:     int(netlink.FLAG_ONLINK),
-				Dst:       &cfg.ExtraRoutes[i].Dst,
-				Gw:        cfg.ExtraRoutes[i].GW,
+				Dst:       &extra.Dst,
+				Gw:        extra.GW,
+				Table:     extra.Table,
 			})
 		} else {
 			routes = append(routes, &netlink.Route{
 				LinkIndex: link.Attrs().Index,
 				Scope:     netlink.SCOPE_LINK,
-				Dst:       &cfg.ExtraRoutes[i].Dst,
+				Dst:       &extra.Dst,
+				Table:     extra.Table,
 			})
 		}
+		if extra.Table != 0 {
+			rules = append(rules, extraRouteRule(containerIPNet, extra))
+		}
 	}
+	return routes, rules
+}
 
-	contCfg := &nic.Conf{
-		IfName: cfg.ContainerIfName,
-		MTU:    cfg.MTU,
-		Addrs:  utils.NewIPNetToMaxMask(cfg.ContainerIPNet),
-		Routes: routes,
-		Rules:  rules,
-		Neighs: neighs,
-		SysCtl: sysctl,
+// extraRouteRule build the rule lookup the table of the policy route
+func extraRouteRule(containerIPNet *terwayTypes.IPNetSet, extra *types.Route) *netlink.Rule {
+	rule := netlink.NewRule()
+	rule.Dst = &extra.Dst
+	rule.Table = extra.Table
+	if extra.Priority > 0 {
+		rule.Priority = extra.Priority
 	}
+	if extra.Src != nil {
+		rule.Src = utils.NewIPNetWithMaxMask(&net.IPNet{IP: extra.Src})
+	} else if extra.Dst.IP.To4() != nil && containerIPNet.IPv4 != nil {
+		rule.Src = utils.NewIPNetWithMaxMask(containerIPNet.IPv4)
+	} else if extra.Dst.IP.To4() == nil && containerIPNet.IPv6 != nil {
+		rule.Src = utils.NewIPNetWithMaxMask(containerIPNet.IPv6)
+	}
+	return rule
+}
 
-	return contCfg
+// ensureExtraRoutes add the extra routes and rules missing in container, called on check when the routes changed
+func ensureExtraRoutes(cfg *types.CheckConfig, link netlink.Link) error {
+	if !cfg.RoutesChanged || cfg.ContainerIPNet == nil {
+		return nil
+	}
+	routes, rules := generateExtraRoutes(cfg.ExtraRoutes, cfg.ContainerIPNet, link)
+	for _, route := range routes {
+		changed, err := utils.EnsureRoute(route)
+		if err != nil {
+			return err
+		}
+		if changed {
+			cfg.RecordPodEvent(fmt.Sprintf("extra route %s added", route.Dst))
+		}
+	}
+	for _, rule := range rules {
+		changed, err := utils.EnsureIPRule(rule)
+		if err != nil {
+			return err
+		}
+		if changed {
+			cfg.RecordPodEvent(fmt.Sprintf("policy rule of extra route %s to table %d added", rule.Dst, rule.Table))
+		}
+	}
+	return nil
 }
 
 func generateHostPeerCfgForPolicy(cfg *types.SetupConfig, link netlink.Link, table int) *nic.Conf {
@@ -399,7 +462,7 @@ func (d *PolicyRoute) Check(cfg *types.CheckConfig) error {
 		if changed {
 			cfg.RecordPodEvent(fmt.Sprintf("link %s set mtu to %v", cfg.ContainerIfName, cfg.MTU))
 		}
-		return nil
+		return ensureExtraRoutes(cfg, link)
 	})
 	return err
 }
@@ -108,23 +108,9 @@ func generateContCfgForVlan(cfg *types.SetupConfig, link netlink.Link) *nic.Conf
 		sysctl = utils.GenerateIPv6Sysctl(cfg.ContainerIfName, true, false)
 	}
 
-	for i := range cfg.ExtraRoutes {
-		if cfg.ExtraRoutes[i].GW != nil {
-			routes = append(routes, &netlink.Route{
-				LinkIndex: link.Attrs().Index,
-				Scope:     netlink.SCOPE_UNIVERSE,
-				Flags:     int(netlink.FLAG_ONLINK),
-				Dst:       &cfg.ExtraRoutes[i].Dst,
-				Gw:        cfg.ExtraRoutes[i].GW,
-			})
-		} else {
-			routes = append(routes, &netlink.Route{
-				LinkIndex: link.Attrs().Index,
-				Scope:     netlink.SCOPE_LINK,
-				Dst:       &cfg.ExtraRoutes[i].Dst,
-			})
-		}
-	}
+	extraRoutes, extraRules := generateExtraRoutes(cfg.ExtraRoutes, cfg.ContainerIPNet, link)
+	routes = append(routes, extraRoutes...)
+	rules = append(rules, extraRules...)
 
 	contCfg := &nic.Conf{
 		IfName: cfg.ContainerIfName,
@@ -221,7 +207,7 @@ func (d *Vlan) Check(cfg *types.CheckConfig) error {
 		if changed {
 			cfg.RecordPodEvent(fmt.Sprintf("link %s set mtu to %v", cfg.ContainerIfName, cfg.MTU))
 		}
-		return nil
+		return ensureExtraRoutes(cfg, link)
 	})
 	return err
 }
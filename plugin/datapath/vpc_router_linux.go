@@ -21,6 +21,7 @@ func NewVPCRoute() *VPCRoute {
 
 func generateContCfgForVPCRoute(cfg *types.SetupConfig, link netlink.Link, mac net.HardwareAddr) *nic.Conf {
 	var routes []*netlink.Route
+	var rules []*netlink.Rule
 	var neighs []*netlink.Neigh
 
 	if cfg.ContainerIPNet.IPv4 != nil {
@@ -39,6 +40,26 @@ func generateContCfgForVPCRoute(cfg *types.SetupConfig, link netlink.Link, mac n
 			HardwareAddr: mac,
 			State:        netlink.NUD_PERMANENT,
 		})
+
+		// extra routes go through the host peer as the default route
+		for i := range cfg.ExtraRoutes {
+			extra := &cfg.ExtraRoutes[i]
+			// the ipv6 extra route is rejected by daemon, the pod has no ipv6 address to route
+			if extra.Dst.IP.To4() == nil {
+				continue
+			}
+			routes = append(routes, &netlink.Route{
+				LinkIndex: link.Attrs().Index,
+				Scope:     netlink.SCOPE_UNIVERSE,
+				Dst:       &extra.Dst,
+				Gw:        LinkIPNet.IP,
+				Flags:     int(netlink.FLAG_ONLINK),
+				Table:     extra.Table,
+			})
+			if extra.Table != 0 {
+				rules = append(rules, extraRouteRule(cfg.ContainerIPNet, extra))
+			}
+		}
 	}
 
 	contCfg := &nic.Conf{
@@ -46,6 +67,7 @@ func generateContCfgForVPCRoute(cfg *types.SetupConfig, link netlink.Link, mac n
 		MTU:    cfg.MTU,
 		Addrs:  utils.NewIPNetToMaxMask(cfg.ContainerIPNet),
 		Routes: routes,
+		Rules:  rules,
 		Neighs: neighs,
 	}
 
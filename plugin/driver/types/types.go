@@ -99,8 +99,11 @@ type SetupConfig struct {
 	DefaultRoute bool
 	MultiNetwork bool
 
+	// disable reverse path filter on the container interface
+	DisableRPFilter bool
+
 	// add extra route in container
-	ExtraRoutes []cniTypes.Route
+	ExtraRoutes []Route
 
 	ServiceCIDR *terwayTypes.IPNetSet
 	HostIPSet   *terwayTypes.IPNetSet
@@ -121,6 +124,15 @@ type SetupConfig struct {
 	AssistantGatewayIP      *terwayTypes.IPSet
 }
 
+// Route extra route in container, policy routing is used if Table is set
+type Route struct {
+	cniTypes.Route
+	// Src source of the policy route rule, nil for the container ip
+	Src      net.IP
+	Table    int
+	Priority int
+}
+
 type TeardownCfg struct {
 	DP DataPath
 
@@ -25,4 +25,8 @@ type CheckConfig struct {
 
 	DefaultRoute bool
 	MultiNetwork bool
+
+	ExtraRoutes []Route
+	// RoutesChanged the extra routes changed since allocation, reconcile them on check
+	RoutesChanged bool
 }
@@ -370,6 +370,20 @@ func EnsureNetConfSet(ipv4, ipv6 bool) error {
 	return err
 }
 
+// DisableRPFilter disable reverse path filter of the interface in the netns,
+// the effective value is the max of "all" and the interface, so both are set
+func DisableRPFilter(netNS ns.NetNS, ifName string) error {
+	return netNS.Do(func(netNS ns.NetNS) error {
+		for _, name := range []string{"all", ifName} {
+			err := terwaySysctl.EnsureConf(fmt.Sprintf("/proc/sys/net/ipv4/conf/%s/rp_filter", name), "0")
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func EnsureVlanUntagger(link netlink.Link) error {
 	if err := EnsureClsActQdsic(link); err != nil {
 		return fmt.Errorf("error ensure cls act qdisc for %s vlan untag, %w", link.Attrs().Name, err)
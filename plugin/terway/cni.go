@@ -245,7 +245,7 @@ func parseSetupConf(args *skel.CmdArgs, alloc *rpc.NetConf, conf *types.CNIConf,
 		egress          uint64
 		networkPriority uint32
 
-		routes []cniTypes.Route
+		routes []types.Route
 
 		disableCreatePeer bool
 	)
@@ -324,18 +324,9 @@ func parseSetupConf(args *skel.CmdArgs, alloc *rpc.NetConf, conf *types.CNIConf,
 	if name == "" {
 		name = args.IfName
 	}
-	for _, r := range alloc.GetExtraRoutes() {
-		ip, n, err := net.ParseCIDR(r.Dst)
-		if err != nil {
-			return nil, fmt.Errorf("error parse extra routes, %w", err)
-		}
-		route := cniTypes.Route{Dst: *n}
-		if ip.To4() != nil {
-			route.GW = gatewayIP.IPv4
-		} else {
-			route.GW = gatewayIP.IPv6
-		}
-		routes = append(routes, route)
+	routes, err = parseExtraRoutes(alloc, gatewayIP)
+	if err != nil {
+		return nil, err
 	}
 
 	dp := getDatePath(ipType, conf.VlanStripType, trunkENI)
@@ -356,6 +347,7 @@ func parseSetupConf(args *skel.CmdArgs, alloc *rpc.NetConf, conf *types.CNIConf,
 		StripVlan:             trunkENI,
 		Vid:                   int(vid),
 		DefaultRoute:          alloc.GetDefaultRoute(),
+		DisableRPFilter:       alloc.GetENIInfo().GetDisableRPFilter(),
 		ExtraRoutes:           routes,
 		DisableCreatePeer:     disableCreatePeer,
 		RuntimeConfig:         conf.RuntimeConfig,
@@ -416,6 +408,37 @@ func parseTearDownConf(alloc *rpc.NetConf, conf *types.CNIConf, ipType rpc.IPTyp
 	}, nil
 }
 
+// parseExtraRoutes convert the extra routes of the net conf, the routes go through the gateway of the pod
+func parseExtraRoutes(alloc *rpc.NetConf, gatewayIP *terwayTypes.IPSet) ([]types.Route, error) {
+	var routes []types.Route
+	for _, r := range alloc.GetExtraRoutes() {
+		ip, n, err := net.ParseCIDR(r.Dst)
+		if err != nil {
+			return nil, fmt.Errorf("error parse extra routes, %w", err)
+		}
+		route := types.Route{
+			Route:    cniTypes.Route{Dst: *n},
+			Table:    int(r.GetTable()),
+			Priority: int(r.GetPriority()),
+		}
+		if gatewayIP != nil {
+			if ip.To4() != nil {
+				route.GW = gatewayIP.IPv4
+			} else {
+				route.GW = gatewayIP.IPv6
+			}
+		}
+		if r.GetSrc() != "" {
+			route.Src = net.ParseIP(r.GetSrc())
+			if route.Src == nil {
+				return nil, fmt.Errorf("error parse extra routes, invalid src %s", r.GetSrc())
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
 func parseCheckConf(args *skel.CmdArgs, alloc *rpc.NetConf, conf *types.CNIConf, ipType rpc.IPType) (*types.CheckConfig, error) {
 	var (
 		err            error
@@ -455,6 +478,11 @@ func parseCheckConf(args *skel.CmdArgs, alloc *rpc.NetConf, conf *types.CNIConf,
 		name = args.IfName
 	}
 
+	extraRoutes, err := parseExtraRoutes(alloc, gatewayIP)
+	if err != nil {
+		return nil, err
+	}
+
 	dp := getDatePath(ipType, conf.VlanStripType, trunkENI)
 	return &types.CheckConfig{
 		DP:              dp,
@@ -465,6 +493,7 @@ func parseCheckConf(args *skel.CmdArgs, alloc *rpc.NetConf, conf *types.CNIConf,
 		ENIIndex:        deviceID,
 		TrunkENI:        trunkENI,
 		DefaultRoute:    alloc.GetDefaultRoute(),
+		ExtraRoutes:     extraRoutes,
 	}, nil
 }
 
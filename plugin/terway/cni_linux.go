@@ -136,9 +136,23 @@ func doCmdAdd(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBacken
 		return
 	}
 	if !allocResult.Success {
+		if allocResult.Error == rpc.Error_ErrHostNetworkPod {
+			// nothing is allocated for the host network pod, leave the netns as it is
+			logger.Debugf("pod is host network, skip the setup")
+			containerIPNet, gatewayIPSet = &terwayTypes.IPNetSet{}, &terwayTypes.IPSet{}
+			return
+		}
+		if allocResult.Error == rpc.Error_ErrUnsupportedNetworkType {
+			err = fmt.Errorf("cmdAdd: pod network type is not supported by terway, check the pod annotations and terway config")
+			return
+		}
 		err = fmt.Errorf("cmdAdd: alloc ip return not success")
 		return
 	}
+	if allocResult.SchemaVersion != rpc.NetConfSchemaVersion {
+		logger.Warnf("unrecognized NetConf schema version %d from daemon, expect %d, cni and daemon may be in different version",
+			allocResult.SchemaVersion, rpc.NetConfSchemaVersion)
+	}
 
 	defer func() {
 		if err != nil {
@@ -175,6 +189,7 @@ func doCmdAdd(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBacken
 	}
 	defer l.Close()
 
+	var disableRPFilterIfNames []string
 	for _, netConf := range allocResult.NetConfs {
 		var setupCfg *types.SetupConfig
 		setupCfg, err = parseSetupConf(args, netConf, conf, allocResult.IPType)
@@ -182,6 +197,9 @@ func doCmdAdd(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBacken
 			err = fmt.Errorf("error parse config, %w", err)
 			return
 		}
+		if setupCfg.DisableRPFilter {
+			disableRPFilterIfNames = append(disableRPFilterIfNames, setupCfg.ContainerIfName)
+		}
 		setupCfg.HostVETHName, _ = link.VethNameForPod(string(k8sConfig.K8S_POD_NAME), string(k8sConfig.K8S_POD_NAMESPACE), netConf.IfName, defaultVethPrefix)
 		setupCfg.HostIPSet = hostIPSet
 		setupCfg.MultiNetwork = multiNetwork
@@ -302,6 +320,14 @@ func doCmdAdd(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBacken
 		}
 	}
 
+	for _, ifName := range disableRPFilterIfNames {
+		err = utils.DisableRPFilter(cniNetns, ifName)
+		if err != nil {
+			err = fmt.Errorf("error disable rp_filter on %s, %w", ifName, err)
+			return
+		}
+	}
+
 	if containerIPNet == nil || gatewayIPSet == nil {
 		err = fmt.Errorf("eth0 config is missing")
 		return
@@ -435,6 +461,8 @@ func doCmdCheck(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBack
 	}
 	defer l.Close()
 
+	// the daemon keeps reporting the changed routes until they are reconciled, ipvlan check leaves them as they are
+	routesReconciled := getResult.RoutesChanged
 	for _, netConf := range getResult.NetConfs {
 		var checkCfg *types.CheckConfig
 		checkCfg, err = parseCheckConf(args, netConf, conf, getResult.IPType)
@@ -442,6 +470,7 @@ func doCmdCheck(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBack
 			return fmt.Errorf("error parse config, %w", err)
 		}
 		checkCfg.NetNS = cniNetns
+		checkCfg.RoutesChanged = getResult.RoutesChanged
 		checkCfg.HostVETHName, _ = link.VethNameForPod(string(k8sConfig.K8S_POD_NAME), string(k8sConfig.K8S_POD_NAMESPACE), netConf.IfName, defaultVethPrefix)
 		checkCfg.HostIPSet = hostIPSet
 		checkCfg.RecordPodEvent = func(msg string) {
@@ -473,6 +502,7 @@ func doCmdCheck(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBack
 					if err != nil {
 						return err
 					}
+					routesReconciled = false
 					continue
 				}
 			}
@@ -502,5 +532,16 @@ func doCmdCheck(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBack
 			return fmt.Errorf("not support this network type")
 		}
 	}
+	if routesReconciled {
+		_, err = client.GetIPInfo(ctx, &rpc.GetInfoRequest{
+			K8SPodName:             string(k8sConfig.K8S_POD_NAME),
+			K8SPodNamespace:        string(k8sConfig.K8S_POD_NAMESPACE),
+			K8SPodInfraContainerId: string(k8sConfig.K8S_POD_INFRA_CONTAINER_ID),
+			RouteFingerprint:       getResult.RouteFingerprint,
+		})
+		if err != nil {
+			logger.Warnf("error report the extra routes reconciled, %v", err)
+		}
+	}
 	return nil
 }
@@ -133,9 +133,23 @@ func doCmdAdd(ctx context.Context, logger *logrus.Entry, client rpc.TerwayBacken
 		return
 	}
 	if !allocResult.Success {
+		if allocResult.Error == rpc.Error_ErrHostNetworkPod {
+			// nothing is allocated for the host network pod, leave the netns as it is
+			logger.Debugf("pod is host network, skip the setup")
+			containerIPNet, gatewayIPSet = &terwayTypes.IPNetSet{}, &terwayTypes.IPSet{}
+			return
+		}
+		if allocResult.Error == rpc.Error_ErrUnsupportedNetworkType {
+			err = fmt.Errorf("cmdAdd: pod network type is not supported by terway, check the pod annotations and terway config")
+			return
+		}
 		err = fmt.Errorf("cmdAdd: alloc ip return not success")
 		return
 	}
+	if allocResult.SchemaVersion != rpc.NetConfSchemaVersion {
+		logger.Warnf("unrecognized NetConf schema version %d from daemon, expect %d, cni and daemon may be in different version",
+			allocResult.SchemaVersion, rpc.NetConfSchemaVersion)
+	}
 
 	defer func() {
 		if err != nil {
@@ -26,6 +26,12 @@ type TerwayBackendClient interface {
 	ReleaseIP(ctx context.Context, in *ReleaseIPRequest, opts ...grpc.CallOption) (*ReleaseIPReply, error)
 	GetIPInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoReply, error)
 	RecordEvent(ctx context.Context, in *EventRequest, opts ...grpc.CallOption) (*EventReply, error)
+	GetPodStatus(ctx context.Context, in *GetPodStatusRequest, opts ...grpc.CallOption) (*GetPodStatusReply, error)
+	WarmPool(ctx context.Context, in *WarmPoolRequest, opts ...grpc.CallOption) (*WarmPoolReply, error)
+	TriggerGC(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TriggerGCReply, error)
+	ReloadConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ReloadConfigReply, error)
+	GetAllocatableCapacity(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AllocatableCapacityReply, error)
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelReply, error)
 }
 
 type terwayBackendClient struct {
@@ -72,6 +78,60 @@ func (c *terwayBackendClient) RecordEvent(ctx context.Context, in *EventRequest,
 	return out, nil
 }
 
+func (c *terwayBackendClient) GetPodStatus(ctx context.Context, in *GetPodStatusRequest, opts ...grpc.CallOption) (*GetPodStatusReply, error) {
+	out := new(GetPodStatusReply)
+	err := c.cc.Invoke(ctx, "/rpc.TerwayBackend/GetPodStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terwayBackendClient) WarmPool(ctx context.Context, in *WarmPoolRequest, opts ...grpc.CallOption) (*WarmPoolReply, error) {
+	out := new(WarmPoolReply)
+	err := c.cc.Invoke(ctx, "/rpc.TerwayBackend/WarmPool", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terwayBackendClient) TriggerGC(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TriggerGCReply, error) {
+	out := new(TriggerGCReply)
+	err := c.cc.Invoke(ctx, "/rpc.TerwayBackend/TriggerGC", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terwayBackendClient) ReloadConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ReloadConfigReply, error) {
+	out := new(ReloadConfigReply)
+	err := c.cc.Invoke(ctx, "/rpc.TerwayBackend/ReloadConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terwayBackendClient) GetAllocatableCapacity(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AllocatableCapacityReply, error) {
+	out := new(AllocatableCapacityReply)
+	err := c.cc.Invoke(ctx, "/rpc.TerwayBackend/GetAllocatableCapacity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *terwayBackendClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelReply, error) {
+	out := new(SetLogLevelReply)
+	err := c.cc.Invoke(ctx, "/rpc.TerwayBackend/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TerwayBackendServer is the server API for TerwayBackend service.
 // All implementations must embed UnimplementedTerwayBackendServer
 // for forward compatibility
@@ -80,6 +140,12 @@ type TerwayBackendServer interface {
 	ReleaseIP(context.Context, *ReleaseIPRequest) (*ReleaseIPReply, error)
 	GetIPInfo(context.Context, *GetInfoRequest) (*GetInfoReply, error)
 	RecordEvent(context.Context, *EventRequest) (*EventReply, error)
+	GetPodStatus(context.Context, *GetPodStatusRequest) (*GetPodStatusReply, error)
+	WarmPool(context.Context, *WarmPoolRequest) (*WarmPoolReply, error)
+	TriggerGC(context.Context, *Empty) (*TriggerGCReply, error)
+	ReloadConfig(context.Context, *Empty) (*ReloadConfigReply, error)
+	GetAllocatableCapacity(context.Context, *Empty) (*AllocatableCapacityReply, error)
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelReply, error)
 	mustEmbedUnimplementedTerwayBackendServer()
 }
 
@@ -99,6 +165,24 @@ func (UnimplementedTerwayBackendServer) GetIPInfo(context.Context, *GetInfoReque
 func (UnimplementedTerwayBackendServer) RecordEvent(context.Context, *EventRequest) (*EventReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RecordEvent not implemented")
 }
+func (UnimplementedTerwayBackendServer) GetPodStatus(context.Context, *GetPodStatusRequest) (*GetPodStatusReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPodStatus not implemented")
+}
+func (UnimplementedTerwayBackendServer) WarmPool(context.Context, *WarmPoolRequest) (*WarmPoolReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WarmPool not implemented")
+}
+func (UnimplementedTerwayBackendServer) TriggerGC(context.Context, *Empty) (*TriggerGCReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TriggerGC not implemented")
+}
+func (UnimplementedTerwayBackendServer) ReloadConfig(context.Context, *Empty) (*ReloadConfigReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadConfig not implemented")
+}
+func (UnimplementedTerwayBackendServer) GetAllocatableCapacity(context.Context, *Empty) (*AllocatableCapacityReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllocatableCapacity not implemented")
+}
+func (UnimplementedTerwayBackendServer) SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
 func (UnimplementedTerwayBackendServer) mustEmbedUnimplementedTerwayBackendServer() {}
 
 // UnsafeTerwayBackendServer may be embedded to opt out of forward compatibility for this service.
@@ -184,6 +268,114 @@ func _TerwayBackend_RecordEvent_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TerwayBackend_GetPodStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPodStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerwayBackendServer).GetPodStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.TerwayBackend/GetPodStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerwayBackendServer).GetPodStatus(ctx, req.(*GetPodStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerwayBackend_WarmPool_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WarmPoolRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerwayBackendServer).WarmPool(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.TerwayBackend/WarmPool",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerwayBackendServer).WarmPool(ctx, req.(*WarmPoolRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerwayBackend_TriggerGC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerwayBackendServer).TriggerGC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.TerwayBackend/TriggerGC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerwayBackendServer).TriggerGC(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerwayBackend_ReloadConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerwayBackendServer).ReloadConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.TerwayBackend/ReloadConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerwayBackendServer).ReloadConfig(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerwayBackend_GetAllocatableCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerwayBackendServer).GetAllocatableCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.TerwayBackend/GetAllocatableCapacity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerwayBackendServer).GetAllocatableCapacity(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TerwayBackend_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TerwayBackendServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.TerwayBackend/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TerwayBackendServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TerwayBackend_ServiceDesc is the grpc.ServiceDesc for TerwayBackend service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -207,6 +399,30 @@ var TerwayBackend_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RecordEvent",
 			Handler:    _TerwayBackend_RecordEvent_Handler,
 		},
+		{
+			MethodName: "GetPodStatus",
+			Handler:    _TerwayBackend_GetPodStatus_Handler,
+		},
+		{
+			MethodName: "WarmPool",
+			Handler:    _TerwayBackend_WarmPool_Handler,
+		},
+		{
+			MethodName: "TriggerGC",
+			Handler:    _TerwayBackend_TriggerGC_Handler,
+		},
+		{
+			MethodName: "ReloadConfig",
+			Handler:    _TerwayBackend_ReloadConfig_Handler,
+		},
+		{
+			MethodName: "GetAllocatableCapacity",
+			Handler:    _TerwayBackend_GetAllocatableCapacity_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _TerwayBackend_SetLogLevel_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
@@ -0,0 +1,8 @@
+package rpc
+
+// NetConfSchemaVersion is the version of NetConf schema in AllocIPReply,
+// bump it when NetConf fields or their meanings change, so cni can detect daemon of a different version
+// 2: src, table and priority of Route added
+// 3: DisableRPFilter of ENIInfo added
+// 4: ENIPrimaryIP of ENIInfo added
+const NetConfSchemaVersion = 4
@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 // PoolConfig configuration of pool and resource factory
 type PoolConfig struct {
 	MaxPoolSize               int
@@ -9,6 +11,7 @@ type PoolConfig struct {
 	VPC                       string
 	Zone                      string
 	VSwitch                   []string
+	FallbackVSwitch           []string
 	ENITags                   map[string]string
 	SecurityGroups            []string
 	InstanceID                string
@@ -22,4 +25,16 @@ type PoolConfig struct {
 	DisableDevicePlugin       bool
 	WaitTrunkENI              bool
 	DisableSecurityGroupCheck bool
+	EnablePrefixDelegation    bool
+	ReservedIPs               []string
+	ENIIdleRetain             time.Duration // keep the released eni idle in pool for the duration before dispose
+	TrunkENIIdleDetach        time.Duration // detach the trunk eni no trunk pod used for the duration, 0 for never
+	TagENIWithPod             bool          // tag the eni with the namespace and name of pod using it
+	ENICreateQPS              float32       // rate limit of eni creation, 0 for unlimited
+	ENICreateBurst            int
+	RestoreParallelism        int           // workers verifying the local resource on pool init, 0 for default
+	ENIAttachTimeout          time.Duration // wait for the device of eni created appears in kernel, 0 for not wait
+	ENIAttachPollInterval     time.Duration
+	VSwitchWeights            map[string]int // weight of the vswitches for weighted round robin selection
+	PodLabelENITags           []string       // the label keys of pod copied to the tags of eni
 }
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"os"
+	"strings"
 
 	"github.com/AliyunContainerService/terway/types"
 	"github.com/AliyunContainerService/terway/types/route"
@@ -33,19 +34,89 @@ type Config struct {
 	EnableEIPPool          string              `yaml:"enable_eip_pool" json:"enable_eip_pool"`
 	IPStack                string              `yaml:"ip_stack" json:"ip_stack" validate:"oneof=ipv4 ipv6 dual" mod:"default=ipv4"` // default ipv4 , support ipv4 dual
 	// rob the eip instance even the eip already bound to other resource
-	AllowEIPRob                 string                  `yaml:"allow_eip_rob" json:"allow_eip_rob"`
-	EnableENITrunking           bool                    `yaml:"enable_eni_trunking" json:"enable_eni_trunking"`
-	CustomStatefulWorkloadKinds []string                `yaml:"custom_stateful_workload_kinds" json:"custom_stateful_workload_kinds"`
-	IPAMType                    types.IPAMType          `yaml:"ipam_type" json:"ipam_type"`           // crd or default
-	ENICapPolicy                types.ENICapPolicy      `yaml:"eni_cap_policy" json:"eni_cap_policy"` // prefer trunk or secondary
-	BackoffOverride             map[string]wait.Backoff `json:"backoff_override,omitempty"`
-	ExtraRoutes                 []route.Route           `json:"extra_routes,omitempty"`
-	DisableDevicePlugin         bool                    `json:"disable_device_plugin"`
-	WaitTrunkENI                bool                    `json:"wait_trunk_eni"` // true for don't create trunk eni
-	ENITagFilter                map[string]string       `json:"eni_tag_filter"` // if set , only enis match filter, will be managed
-	DisableSecurityGroupCheck   bool                    `json:"disable_security_group_check"`
-	KubeClientQPS               float32                 `json:"kube_client_qps"`
-	KubeClientBurst             int                     `json:"kube_client_burst"`
+	AllowEIPRob                         string                  `yaml:"allow_eip_rob" json:"allow_eip_rob"`
+	EnableENITrunking                   bool                    `yaml:"enable_eni_trunking" json:"enable_eni_trunking"`
+	CustomStatefulWorkloadKinds         []string                `yaml:"custom_stateful_workload_kinds" json:"custom_stateful_workload_kinds"`
+	IPAMType                            types.IPAMType          `yaml:"ipam_type" json:"ipam_type"`           // crd or default
+	ENICapPolicy                        types.ENICapPolicy      `yaml:"eni_cap_policy" json:"eni_cap_policy"` // prefer trunk or secondary
+	BackoffOverride                     map[string]wait.Backoff `json:"backoff_override,omitempty"`           // key is operation or category read/write of openapi
+	ExtraRoutes                         []route.Route           `json:"extra_routes,omitempty"`
+	DisableDevicePlugin                 bool                    `json:"disable_device_plugin"`
+	WaitTrunkENI                        bool                    `json:"wait_trunk_eni"` // true for don't create trunk eni, otherwise min_eni is raised to 1 when trunking enabled
+	ENITagFilter                        map[string]string       `json:"eni_tag_filter"` // if set , only enis match filter, will be managed
+	DisableSecurityGroupCheck           bool                    `json:"disable_security_group_check"`
+	KubeClientQPS                       float32                 `json:"kube_client_qps"`
+	KubeClientBurst                     int                     `json:"kube_client_burst"`
+	CriticalKubeClientQPS               float32                 `json:"critical_kube_client_qps"`                  // separate rate limit for AllocIP critical calls, 0 for share with kube_client_qps
+	CriticalKubeClientBurst             int                     `json:"critical_kube_client_burst"`                // burst for AllocIP critical calls, default to kube_client_burst
+	MaxAllocLatencySeconds              int                     `json:"max_alloc_latency_seconds"`                 // 0 for use the grpc context deadline
+	EnablePrefixDelegation              bool                    `json:"enable_prefix_delegation"`                  // assign ipv4 prefix instead of secondary ip for eniip
+	SufficientIPThreshold               int                     `json:"sufficient_ip_threshold"`                   // set node condition SufficientIP to false when free ip below it, 0 for disable
+	AutoSizePool                        bool                    `json:"auto_size_pool"`                            // compute max_eni and max_pool_size from instance type
+	ValidateGateways                    bool                    `json:"validate_gateways"`                         // validate the derived ipv6 gateway in AllocIP
+	DNS                                 *types.DNSConfig        `json:"dns,omitempty"`                             // default pod dns config pass to cni, nil for not config
+	PendingPodTTLSeconds                int                     `json:"pending_pod_ttl_seconds"`                   // evict leaked pending pod entries older than it, 0 for default 10 minutes
+	TrunkVlanMin                        int                     `json:"trunk_vlan_min"`                            // min vlan id allowed for trunk eni, 0 for default 1
+	TrunkVlanMax                        int                     `json:"trunk_vlan_max"`                            // max vlan id allowed for trunk eni, 0 for default 4094
+	ReservedIPs                         []string                `json:"reserved_ips"`                              // ips in vswitch reserved for other usage, will not be handed out to pod
+	AllowEmptyServiceCIDR               bool                    `json:"allow_empty_service_cidr"`                  // tolerate the service cidr auto detect failure when service_cidr is empty
+	ENINamePrefix                       string                  `json:"eni_name_prefix"`                           // prefix of eni name, the name is made of prefix, cluster id and node name, empty for default
+	ReleaseAllOnShutdown                bool                    `json:"release_all_on_shutdown"`                   // release idle eni/eniip to ecs when daemon stop on a terminating node
+	AllocQueueSize                      int                     `json:"alloc_queue_size"`                          // serve AllocIP in fifo order of pod first seen with max waiting pods, 0 for disable
+	AllowCrossZoneFallback              bool                    `json:"allow_cross_zone_fallback"`                 // try vswitches configured for other zones when vswitches of the instance zone have no available ip, the ones not in the zone of instance are skipped
+	MaxEIPPoolSize                      int                     `json:"max_eip_pool_size"`                         // max count of eip created by terway on the node, 0 for unlimited
+	HostNetNSPrefix                     string                  `json:"host_netns_prefix"`                         // prefix of pod netns path for accessing from daemon, default /proc/1/root/
+	DefaultNetworkPriority              string                  `json:"default_network_priority"`                  // network priority for pod not specify one, empty for no priority
+	ExtraRouteTable                     int                     `json:"extra_route_table"`                         // route table for extra routes of crd not specify one, 0 for main table
+	ExtraRoutePriority                  int                     `json:"extra_route_priority"`                      // ip rule priority for extra routes of crd not specify one
+	AllocWebhookURL                     string                  `json:"alloc_webhook_url"`                         // local webhook to review the allocation before stored, empty for disable
+	AllowPartialDualStack               bool                    `json:"allow_partial_dual_stack"`                  // degrade pod to single stack instead of fail when only one ip family allocated in dual stack
+	EIPStickTimeSeconds                 int                     `json:"eip_stick_time_seconds"`                    // keep the eip of deleted pod for a while so it can be reused by the restarted pod, 0 for release at once
+	MaxSecondaryIPCount                 int                     `json:"max_secondary_ip_count"`                    // max extra eniips a pod can request by annotation, 0 for disable
+	AllocFailureLogSize                 int                     `json:"alloc_failure_log_size"`                    // count of recent failed allocations kept for the failures command, 0 for default 100
+	ENIIdleRetainSeconds                int                     `json:"eni_idle_retain_seconds"`                   // keep released eni idle in pool for the seconds before return to ecs, 0 for disable
+	ValidateExtraRoutes                 bool                    `json:"validate_extra_routes"`                     // reject extra routes of crd overlap the service cidr
+	PatchPodIPRetries                   int                     `json:"patch_pod_ip_retries"`                      // retries of patching pod ip annotation, 0 for default 2
+	ReserveIPsForCritical               int                     `json:"reserve_ips_for_critical"`                  // free eniips only critical pods can use, 0 for disable
+	MaxEniCapRatio                      float64                 `json:"max_eni_cap_ratio"`                         // upper bound of eni_cap_ratio, larger one is clamped, 0 for default 1
+	MaxAllocLatencySecondsByNetworkType map[string]int          `json:"max_alloc_latency_seconds_by_network_type"` // key is pod network type VPCIP, VPCENI or ENIMultiIP, override max_alloc_latency_seconds
+	EIPBandwidthPackageID               string                  `json:"eip_bandwidth_package_id"`                  // common bandwidth package new eips are added to, overridden by pod annotation
+	PreferPreviousIP                    bool                    `json:"prefer_previous_ip"`                        // reuse the exact eniip allocated to pod before if it is still free
+	SlowAllocThresholdSeconds           int                     `json:"slow_alloc_threshold_seconds"`              // record a pod event with the durations of phases if alloc ip took longer, 0 for disable
+	CNICheckFailureRatio                float64                 `json:"cni_check_failure_ratio"`                   // set node condition PodNetworkHealthy to false when failed ratio of cni check in a cycle exceed it, 0 for disable
+	ReadOnly                            bool                    `json:"read_only"`                                 // run as standby serving the snapshot file at standby_snapshot_path written by the active daemon, not the resource db itself, alloc and release are rejected
+	StandbySnapshotPath                 string                  `json:"standby_snapshot_path"`                     // the active daemon writes a snapshot of the resource db to the separate file periodically and the read only standby reads it, empty for disable
+	VSwitchCacheTTLSeconds              int                     `json:"vswitch_cache_ttl_seconds"`                 // cache the vswitches described from openapi for the seconds, available ip count for ordered vswitch selection is stale for it too, 0 for disable
+	PodHistoryRetentionSeconds          int                     `json:"pod_history_retention_seconds"`             // keep the allocation and release history of pods in memory for the seconds, 0 for disable
+	DisableRPFilter                     bool                    `json:"disable_rp_filter"`                         // disable reverse path filter on interfaces of all pods, pod can enable it by annotation
+	TrunkENIIdleDetachSeconds           int                     `json:"trunk_eni_idle_detach_seconds"`             // detach the trunk eni in eni only mode when no trunk pod on node for the seconds, recreated for next trunk pod, 0 for disable
+	MaxPendingPods                      int                     `json:"max_pending_pods"`                          // AllocIP is rejected with a retryable error when the pods in processing exceed it, 0 for unlimited
+	GetPodRetries                       int                     `json:"get_pod_retries"`                           // retries of getting pod on transient errors in AllocIP, 0 for default 2
+	TagENIWithPod                       bool                    `json:"tag_eni_with_pod"`                          // tag the eni with the namespace and name of pod using it in eni only mode, removed on release
+	MaxIPStickDurationSeconds           int                     `json:"max_ip_stick_duration_seconds"`             // resources of pod with ip stick time are released by gc once allocated longer than the seconds, 0 for unlimited
+	ResourceGroupID                     string                  `json:"resource_group_id"`                         // resource group of eni created, checked at startup, empty for the default group of account
+	ENIQuotaAlertRatio                  float64                 `json:"eni_quota_alert_ratio"`                     // record node event when the enis attached exceed the fraction of instance quota, 0 for disable
+	ContainerIDMismatchEventThreshold   int                     `json:"container_id_mismatch_event_threshold"`     // record pod event when the cni requests of pod not match the container id stored for the times in a row, 0 for disable
+	ENICreateRateLimit                  float32                 `json:"eni_create_rate_limit"`                     // eni created per second, the creation waits for the limit, 0 for unlimited
+	ENICreateBurst                      int                     `json:"eni_create_burst"`                          // burst of eni creation, 0 for default 1
+	PodSubCIDRs                         []PodSubCIDR            `json:"pod_sub_cidrs"`                             // the pods selected get eniip in the sub range of vswitch, the first matched is used
+	SubCIDRAllocRetries                 int                     `json:"sub_cidr_alloc_retries"`                    // attempts to get an eniip in the sub cidr besides the first one, 0 for default 3
+	StartupK8SRetries                   int                     `json:"startup_k8s_retries"`                       // retries of the k8s calls on startup for transient errors, 0 for default 4
+	RestoreParallelism                  int                     `json:"restore_parallelism"`                       // workers verifying the local resources on startup, 0 for default 4
+	GCQuarantineThreshold               int                     `json:"gc_quarantine_threshold"`                   // consecutive gc failures of a resource before it is quarantined, 0 for disable
+	ENIAttachTimeoutSeconds             int                     `json:"eni_attach_timeout_seconds"`                // wait for the device of eni created appears in kernel for the seconds, the eni is freed if timeout, 0 for not wait
+	ENIAttachPollIntervalMilliseconds   int                     `json:"eni_attach_poll_interval_ms"`               // interval checking the device of eni appears in kernel, 0 for default 500
+	VSwitchWeights                      map[string]int          `json:"vswitch_weights"`                           // weight of the vswitches for weighted_round_robin vswitch selection policy, 1 for the vswitch not set
+	PodLabelENITags                     []string                `json:"pod_label_eni_tags"`                        // the label keys of pod copied to the tags of eni in eni only mode, removed on release, the ones exceed the tag count limit of eni are dropped
+	CleanOrphanRoutes                   bool                    `json:"clean_orphan_routes"`                       // delete the ip rules and routes left in the route tables of the eni deleted by period check
+	IPAffinitySingleENI                 bool                    `json:"ip_affinity_single_eni"`                    // allocate the secondary eniips of pod from the eni of its primary ip if capacity allows, fallback to other enis
+	LogLevel                            string                  `json:"log_level"`                                 // log level of daemon, override the one of command line flag, empty for keep the flag
+}
+
+// PodSubCIDR the eniip of pods selected is allocated in the cidr, a sub range of the vswitches
+type PodSubCIDR struct {
+	Selector string `json:"selector"` // label selector of pods, e.g. "zone=dmz"
+	CIDR     string `json:"cidr"`
 }
 
 func (c *Config) GetSecurityGroups() []string {
@@ -73,6 +144,15 @@ func (c *Config) GetExtraRoutes() []string {
 	return vsws
 }
 
+// GetExtraRouteDsts return the dst of extra routes
+func (c *Config) GetExtraRouteDsts() []string {
+	var dsts []string
+	for _, r := range c.ExtraRoutes {
+		dsts = append(dsts, r.Dst)
+	}
+	return dsts
+}
+
 // GetConfigFromFileWithMerge parse Config from file
 func GetConfigFromFileWithMerge(filePath string, cfg []byte) (*Config, error) {
 	data, err := os.ReadFile(filePath)
@@ -87,6 +167,7 @@ func MergeConfigAndUnmarshal(topCfg, baseCfg []byte) (*Config, error) {
 	if len(topCfg) == 0 { // no topCfg, unmarshal baseCfg and return
 		config := &Config{}
 		err := json.Unmarshal(baseCfg, config)
+		config.normalize()
 		return config, err
 	}
 
@@ -98,6 +179,14 @@ func MergeConfigAndUnmarshal(topCfg, baseCfg []byte) (*Config, error) {
 
 	config := &Config{}
 	err = json.Unmarshal(jsonBytes, config)
+	config.normalize()
 
 	return config, err
 }
+
+// normalize trim the spaces of the label keys in pod_label_eni_tags
+func (c *Config) normalize() {
+	for i, key := range c.PodLabelENITags {
+		c.PodLabelENITags[i] = strings.TrimSpace(key)
+	}
+}
@@ -87,3 +87,13 @@ func Test_MergeConfigAndUnmarshal(t *testing.T) {
 	assert.Equal(t, "ordered", cfg.VSwitchSelectionPolicy)
 	t.Logf("%+v", cfg)
 }
+
+func Test_MergeConfigAndUnmarshal_normalize(t *testing.T) {
+	cfg, err := MergeConfigAndUnmarshal(nil, []byte(`{"pod_label_eni_tags": [" app ", "tier"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app", "tier"}, cfg.PodLabelENITags)
+
+	cfg, err = MergeConfigAndUnmarshal([]byte(`{"pod_label_eni_tags": ["app "]}`), []byte(`{}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"app"}, cfg.PodLabelENITags)
+}
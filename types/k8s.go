@@ -37,6 +37,8 @@ const (
 
 	// PodIPReservation whether pod's IP will be reserved for a reuse
 	PodIPReservation = AnnotationPrefix + "pod-ip-reservation"
+	// PodIPv6Reservation set to false to release the ipv6 of pod once it is deleted while the ipv4 is reserved
+	PodIPv6Reservation = AnnotationPrefix + "pod-ipv6-reservation"
 
 	// PodNetworks for additional net config
 	PodNetworks = AnnotationPrefix + "pod-networks"
@@ -65,6 +67,12 @@ const (
 // FinalizerPodENI finalizer for podENI resource
 const FinalizerPodENI = "pod-eni"
 
+// NodeConditionSufficientIP node condition for whether the node have enough ip for pods
+const NodeConditionSufficientIP corev1.NodeConditionType = "SufficientIP"
+
+// NodeConditionPodNetworkHealthy node condition for whether the network of pods on the node pass the cni check
+const NodeConditionPodNetworkHealthy corev1.NodeConditionType = "PodNetworkHealthy"
+
 // events for control plane
 const (
 	EventCreateENISucceed = "CreateENISucceed"
@@ -113,3 +121,12 @@ const (
 	NetworkPrioBurstable  NetworkPrio = "burstable"
 	NetworkPrioGuaranteed NetworkPrio = "guaranteed"
 )
+
+// IsValid return true if the priority is known
+func (p NetworkPrio) IsValid() bool {
+	switch p {
+	case NetworkPrioBestEffort, NetworkPrioBurstable, NetworkPrioGuaranteed:
+		return true
+	}
+	return false
+}
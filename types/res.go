@@ -16,25 +16,45 @@ type PodEipInfo struct {
 	PodEipISP                string
 	PodEipPoolID             string
 	PodEipBandwidthPackageID string
+	PodEipDisabled           bool // eip is opted out by pod, override PodEip
 }
 
 // PodInfo store the pod info
 // NOTE: this is the type store in db
 type PodInfo struct {
 	//K8sPod *v1.Pod
-	Name            string
-	Namespace       string
-	TcIngress       uint64
-	TcEgress        uint64
-	PodNetworkType  string
-	PodIP           string // used for eip and mip
-	PodIPs          IPSet  // used for eip and mip
-	SandboxExited   bool
-	EipInfo         PodEipInfo
-	IPStickTime     time.Duration
-	PodENI          bool
-	PodUID          string
-	NetworkPriority string
+	Name             string
+	Namespace        string
+	TcIngress        uint64
+	TcEgress         uint64
+	PodNetworkType   string
+	PodIP            string // used for eip and mip
+	PodIPs           IPSet  // used for eip and mip
+	SandboxExited    bool
+	EipInfo          PodEipInfo
+	IPStickTime      time.Duration
+	PodENI           bool
+	PodUID           string
+	NetworkPriority  string
+	DNS              *DNSConfig   // dns override from pod annotations
+	VSwitchID        string       // vswitch the pod ip is requested from, empty for any
+	SecondaryIPs     int          // count of extra eniip allocated for the pod, configured on non default interfaces
+	ENIIndex         int          // device index of the eni the pod ip is requested from, 0 for any
+	Critical         bool         // critical pod can use the ips reserved
+	HostNetwork      bool         // pod use the host network, no resource should be allocated
+	PreferPreviousIP bool         // reuse the exact ip allocated before if it is still free
+	DisableRPFilter  bool         // disable reverse path filter on the pod interfaces
+	IPv6NoStick      bool         // the ipv6 is released once the pod deleted while the ipv4 sticks for IPStickTime
+	ENICapPolicy     ENICapPolicy // override the eni_cap_policy of node for the pod, empty for the node policy
+
+	Labels map[string]string `json:"-"` // labels of pod, not stored
+}
+
+// DNSConfig config for pod resolv.conf
+type DNSConfig struct {
+	Nameservers []string `json:"nameservers,omitempty"`
+	Search      []string `json:"search,omitempty"`
+	Options     []string `json:"options,omitempty"`
 }
 
 // ExtraEipInfo store extra eip info
@@ -51,20 +71,51 @@ type ResourceItem struct {
 	ID           string        `json:"id"`
 	ExtraEipInfo *ExtraEipInfo `json:"extra_eip_info"`
 
-	ENIID  string `json:"eni_id"`
-	ENIMAC string `json:"eni_mac"`
-	IPv4   string `json:"ipv4"`
-	IPv6   string `json:"ipv6"`
+	ENIID          string `json:"eni_id"`
+	ENIMAC         string `json:"eni_mac"`
+	ENIDeviceIndex int    `json:"eni_device_index,omitempty"`
+	IPv4           string `json:"ipv4"`
+	IPv6           string `json:"ipv6"`
+
+	// PoolID identify the resource manager which the resource belongs to, empty for DefaultPoolID
+	PoolID string `json:"pool_id,omitempty"`
+}
+
+// DefaultPoolID the pool id for resource manager configured by the daemon config
+const DefaultPoolID = "default"
+
+// GetPoolID return the pool id of the resource, fallback to DefaultPoolID
+func (r ResourceItem) GetPoolID() string {
+	if r.PoolID == "" {
+		return DefaultPoolID
+	}
+	return r.PoolID
 }
 
 // PodResources pod resources related
 type PodResources struct {
+	// SchemaVersion version of the record stored, 0 for the record stored by old version
+	SchemaVersion int `json:",omitempty"`
+
 	Resources   []ResourceItem
 	PodInfo     *PodInfo
 	NetNs       *string
 	ContainerID *string
+	// RouteFingerprint fingerprint of the extra routes from crd on allocation
+	RouteFingerprint string `json:",omitempty"`
+	// AllocatedAt the time the resources first allocated, kept when the resources reused by the pod recreated,
+	// zero for the record stored by old version
+	AllocatedAt time.Time
+	// ENIAffinity the mac of eni all the eniips of pod allocated from with ip affinity of single eni,
+	// empty if they are spread across enis
+	ENIAffinity string `json:",omitempty"`
+	// EIPStickUntil the time the eip kept for the deleted pod released, zero if the pod is not deleted
+	EIPStickUntil time.Time
 }
 
+// PodResourcesSchemaVersion the version of PodResources stored, bump it on incompatible changes
+const PodResourcesSchemaVersion = 1
+
 // GetResourceItemByType get pod resource by resource type
 func (p PodResources) GetResourceItemByType(resType string) []ResourceItem {
 	var ret []ResourceItem
@@ -46,6 +46,8 @@ const (
 const (
 	VSwitchSelectionPolicyRandom  = "random"
 	VSwitchSelectionPolicyOrdered = "ordered"
+	// VSwitchSelectionPolicyWeightedRoundRobin select the vswitch by the probability of its weight
+	VSwitchSelectionPolicyWeightedRoundRobin = "weighted_round_robin"
 )
 
 // IPStack is the ip family type
@@ -78,8 +80,9 @@ type ENICapPolicy string
 
 // how eni cap is calculated
 const (
-	ENICapPolicyPreferTrunk = "preferTrunk"
-	ENICapPolicyDefault     = ""
+	ENICapPolicyPreferTrunk     = "preferTrunk"
+	ENICapPolicyPreferSecondary = "preferSecondary"
+	ENICapPolicyDefault         = ""
 )
 
 // NewIPFamilyFromIPStack parse IPStack to IPFamily
@@ -228,6 +231,9 @@ type ENI struct {
 	VSwitchCIDR IPNetSet
 
 	VSwitchID string
+
+	// DeviceIndex index of the eni attached to the instance, 0 for primary eni or unknown
+	DeviceIndex int
 }
 
 // GetResourceID return mac address of eni
@@ -243,12 +249,13 @@ func (e *ENI) GetType() string {
 func (e *ENI) ToResItems() []ResourceItem {
 	return []ResourceItem{
 		{
-			Type:   e.GetType(),
-			ID:     e.GetResourceID(),
-			ENIID:  e.ID,
-			ENIMAC: e.MAC,
-			IPv4:   e.PrimaryIP.GetIPv4(),
-			IPv6:   e.PrimaryIP.GetIPv6(),
+			Type:           e.GetType(),
+			ID:             e.GetResourceID(),
+			ENIID:          e.ID,
+			ENIMAC:         e.MAC,
+			ENIDeviceIndex: e.DeviceIndex,
+			IPv4:           e.PrimaryIP.GetIPv4(),
+			IPv6:           e.PrimaryIP.GetIPv6(),
 		},
 	}
 }
@@ -257,6 +264,15 @@ func (e *ENI) ToResItems() []ResourceItem {
 type ENIIP struct {
 	ENI   *ENI
 	IPSet IPSet
+
+	// Prefix the delegated ipv4 prefix which the ip is carved from, nil for secondary ip
+	Prefix *net.IPNet
+}
+
+// PodCIDR return the cidr for pod. the ip carved from the delegated prefix also use the vswitch cidr,
+// the prefix is inside it and the gateway of vswitch must stay on link
+func (e *ENIIP) PodCIDR() *IPNetSet {
+	return &e.ENI.VSwitchCIDR
 }
 
 // GetResourceID return mac address of eni and secondary ip address
@@ -272,12 +288,13 @@ func (e *ENIIP) GetType() string {
 func (e *ENIIP) ToResItems() []ResourceItem {
 	return []ResourceItem{
 		{
-			Type:   e.GetType(),
-			ID:     e.GetResourceID(),
-			ENIID:  e.ENI.ID,
-			ENIMAC: e.ENI.MAC,
-			IPv4:   e.IPSet.GetIPv4(),
-			IPv6:   e.IPSet.GetIPv6(),
+			Type:           e.GetType(),
+			ID:             e.GetResourceID(),
+			ENIID:          e.ENI.ID,
+			ENIMAC:         e.ENI.MAC,
+			ENIDeviceIndex: e.ENI.DeviceIndex,
+			IPv4:           e.IPSet.GetIPv4(),
+			IPv6:           e.IPSet.GetIPv6(),
 		},
 	}
 }
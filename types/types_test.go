@@ -77,3 +77,21 @@ func TestIPNetSet_SetIPNet(t *testing.T) {
 	assert.Equal(t, "fd00::/120", ipNetSet.SetIPNet("fd00::/120").IPv6.String())
 	assert.NotNil(t, ipNetSet.IPv6)
 }
+
+func TestENIIP_PodCIDR(t *testing.T) {
+	_, vsw, _ := net.ParseCIDR("192.168.0.0/16")
+	_, prefix, _ := net.ParseCIDR("192.168.1.16/28")
+	eniIP := &ENIIP{
+		ENI: &ENI{
+			VSwitchCIDR: IPNetSet{IPv4: vsw},
+			GatewayIP:   IPSet{IPv4: net.ParseIP("192.168.0.253")},
+		},
+		IPSet:  IPSet{IPv4: net.ParseIP("192.168.1.17")},
+		Prefix: prefix,
+	}
+	// the gateway of the ip carved from prefix is on link
+	cidr := eniIP.PodCIDR()
+	assert.Equal(t, "192.168.0.0/16", cidr.IPv4.String())
+	assert.True(t, cidr.IPv4.Contains(eniIP.ENI.GatewayIP.IPv4))
+	assert.True(t, cidr.IPv4.Contains(eniIP.IPSet.IPv4))
+}